@@ -36,6 +36,7 @@ import (
 
 	"github.com/lack-io/vine"
 	rrvine "github.com/lack-io/vine/cmd/vine/client/resolver/api"
+	cserver "github.com/lack-io/vine/core/server"
 	ahandler "github.com/lack-io/vine/lib/api/handler"
 	aapi "github.com/lack-io/vine/lib/api/handler/api"
 	"github.com/lack-io/vine/lib/api/handler/event"
@@ -43,6 +44,7 @@ import (
 	"github.com/lack-io/vine/lib/api/handler/openapi"
 	arpc "github.com/lack-io/vine/lib/api/handler/rpc"
 	aweb "github.com/lack-io/vine/lib/api/handler/web"
+	"github.com/lack-io/vine/lib/api/marshal"
 	"github.com/lack-io/vine/lib/api/resolver"
 	"github.com/lack-io/vine/lib/api/resolver/grpc"
 	"github.com/lack-io/vine/lib/api/resolver/host"
@@ -55,6 +57,7 @@ import (
 	"github.com/lack-io/vine/util/helper"
 	"github.com/lack-io/vine/util/namespace"
 	"github.com/lack-io/vine/util/stats"
+	"github.com/lack-io/vine/util/wrapper"
 
 	_ "github.com/lack-io/vine/lib/api/handler/openapi/statik"
 )
@@ -99,6 +102,12 @@ func Run(ctx *cli.Context, svcOpts ...vine.Option) {
 		Namespace = strings.TrimSuffix(ctx.String("namespace"), "."+Type)
 	}
 
+	// json options of the proto responses
+	mo, err := marshal.Parse(ctx.String("json-options"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// apiNamespace has the format: "go.vine.api"
 	apiNamespace := Namespace + "." + Type
 
@@ -118,8 +127,7 @@ func Run(ctx *cli.Context, svcOpts ...vine.Option) {
 	if ctx.Bool("enable-tls") {
 		config, err := helper.TLSConfig(ctx)
 		if err != nil {
-			log.Errorf(err.Error())
-			return
+			log.Fatalf("Invalid TLS configuration: %v", err)
 		}
 
 		opts = append(opts, server.EnableTLS(true))
@@ -136,6 +144,8 @@ func Run(ctx *cli.Context, svcOpts ...vine.Option) {
 	if ctx.Bool("enable-stats") {
 		st := stats.New()
 		app.All("/stats", st.StatsHandler)
+		// record the in-flight requests of the handlers of the service
+		_ = svc.Server().Init(cserver.WrapHandler(wrapper.HandlerStats(st)))
 		st.Start()
 		defer st.Stop()
 	}
@@ -202,6 +212,7 @@ func Run(ctx *cli.Context, svcOpts ...vine.Option) {
 			ahandler.WithNamespace(apiNamespace),
 			ahandler.WithRouter(rt),
 			ahandler.WithClient(svc.Client()),
+			ahandler.WithMarshalOptions(mo),
 		)
 		app.Group(APIPath, rp.Handle)
 	case "api":
@@ -262,7 +273,7 @@ func Run(ctx *cli.Context, svcOpts ...vine.Option) {
 			router.WithResolver(rr),
 			router.WithRegistry(svc.Options().Registry),
 		)
-		app.Group(ProxyPath, handler.Meta(svc, rt, nsResolver.ResolveWithType).Handle)
+		app.Group(ProxyPath, handler.Meta(svc, rt, nsResolver.ResolveWithType, ahandler.WithMarshalOptions(mo)).Handle)
 	}
 
 	// create the auth wrapper and the server
@@ -338,8 +349,15 @@ func Commands(options ...vine.Option) []*cli.Command {
 				EnvVars: []string{"VINE_API_ENABLE_CORS"},
 				Value:   true,
 			},
+			&cli.StringFlag{
+				Name:    "json-options",
+				Usage:   "Set how the proto responses are rendered as json {emit_unpopulated, enums_as_ints, int64_as_number, camel_case}",
+				EnvVars: []string{"VINE_API_JSON_OPTIONS"},
+			},
 		},
 	}
 
+	command.Flags = append(command.Flags, helper.TLSFlags()...)
+
 	return []*cli.Command{command}
 }
@@ -33,6 +33,7 @@ import (
 	ahttp "github.com/lack-io/vine/lib/api/handler/http"
 	arpc "github.com/lack-io/vine/lib/api/handler/rpc"
 	aweb "github.com/lack-io/vine/lib/api/handler/web"
+	"github.com/lack-io/vine/lib/api/marshal"
 	"github.com/lack-io/vine/lib/api/router"
 	"github.com/lack-io/vine/proto/apis/errors"
 	ctx "github.com/lack-io/vine/util/context"
@@ -42,6 +43,7 @@ type metaHandler struct {
 	c  client.Client
 	r  router.Router
 	ns func(*fiber.Ctx) string
+	mo marshal.Options
 }
 
 func (m *metaHandler) Handle(c *fiber.Ctx) error {
@@ -64,7 +66,7 @@ func (m *metaHandler) Handle(c *fiber.Ctx) error {
 		return ahttp.WithService(service, handler.WithClient(m.c)).Handle(c)
 	// rpcx handler
 	case arpc.Handler:
-		return arpc.WithService(service, handler.WithClient(m.c)).Handle(c)
+		return arpc.WithService(service, handler.WithClient(m.c), handler.WithMarshalOptions(m.mo)).Handle(c)
 	// event handler
 	case event.Handler:
 		ev := event.NewHandler(
@@ -77,7 +79,7 @@ func (m *metaHandler) Handle(c *fiber.Ctx) error {
 		return aapi.WithService(service, handler.WithClient(m.c)).Handle(c)
 	// default handler: rpc
 	default:
-		return arpc.WithService(service, handler.WithClient(m.c)).Handle(c)
+		return arpc.WithService(service, handler.WithClient(m.c), handler.WithMarshalOptions(m.mo)).Handle(c)
 	}
 }
 
@@ -86,10 +88,16 @@ func (m *metaHandler) String() string {
 }
 
 // Meta is a http.Handler that routes based on endpoint metadata
-func Meta(s vine.Service, r router.Router, ns func(ctx *fiber.Ctx) string) handler.Handler {
+func Meta(s vine.Service, r router.Router, ns func(ctx *fiber.Ctx) string, opts ...handler.Option) handler.Handler {
+	var options handler.Options
+	for _, o := range opts {
+		o(&options)
+	}
+
 	return &metaHandler{
 		c:  s.Client(),
 		r:  r,
 		ns: ns,
+		mo: options.Marshal,
 	}
 }
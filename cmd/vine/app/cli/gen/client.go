@@ -0,0 +1,198 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+var builtins = map[string]bool{
+	"bool":    true,
+	"string":  true,
+	"int":     true,
+	"int8":    true,
+	"int16":   true,
+	"int32":   true,
+	"int64":   true,
+	"uint":    true,
+	"uint8":   true,
+	"uint16":  true,
+	"uint32":  true,
+	"uint64":  true,
+	"float32": true,
+	"float64": true,
+	"byte":    true,
+	"rune":    true,
+}
+
+// GenerateClient returns the go source of a client stub for the service.
+// Every endpoint becomes a method of the client and the request and response
+// structs are derived from the endpoint values in the registry.
+func GenerateClient(pkg string, svc *regpb.Service) ([]byte, error) {
+	g := &generator{
+		structs: make(map[string]*regpb.Value),
+	}
+
+	methods := make(map[string]int)
+	for _, ep := range svc.Endpoints {
+		methods[methodName(ep.Name)]++
+	}
+
+	var body bytes.Buffer
+	for _, ep := range svc.Endpoints {
+		name := methodName(ep.Name)
+		if methods[name] > 1 {
+			name = ident(ep.Name)
+		}
+
+		req := g.messageType(ep.Request)
+		rsp := g.messageType(ep.Response)
+
+		if ep.Metadata["stream"] == "true" {
+			fmt.Fprintf(&body, "\n// %s calls the %s streaming endpoint, the request is sent on the stream\n", name, ep.Name)
+			fmt.Fprintf(&body, "func (c *Client) %s(ctx context.Context, opts ...client.CallOption) (client.Stream, error) {\n", name)
+			fmt.Fprintf(&body, "\treq := c.c.NewRequest(c.name, %q, %s, client.StreamingRequest())\n", ep.Name, newValue(req))
+			fmt.Fprintf(&body, "\treturn c.c.Stream(ctx, req, opts...)\n}\n")
+			continue
+		}
+
+		fmt.Fprintf(&body, "\n// %s calls the %s endpoint\n", name, ep.Name)
+		fmt.Fprintf(&body, "func (c *Client) %s(ctx context.Context, in %s, opts ...client.CallOption) (%s, error) {\n", name, req, rsp)
+		fmt.Fprintf(&body, "\treq := c.c.NewRequest(c.name, %q, in)\n", ep.Name)
+		fmt.Fprintf(&body, "\tout := %s\n", newValue(rsp))
+		fmt.Fprintf(&body, "\tif err := c.c.Call(ctx, req, out, opts...); err != nil {\n\t\treturn nil, err\n\t}\n")
+		fmt.Fprintf(&body, "\treturn out, nil\n}\n")
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by vine gen client. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "// Package %s is a client of the %s service\n", pkg, svc.Name)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import (\n\t\"context\"\n\n\t\"github.com/lack-io/vine/core/client\"\n)\n\n")
+	fmt.Fprintf(&b, "// ServiceName is the registered name of the service\n")
+	fmt.Fprintf(&b, "const ServiceName = %q\n", svc.Name)
+
+	for _, name := range g.order {
+		v := g.structs[name]
+		fmt.Fprintf(&b, "\ntype %s struct {\n", name)
+		for _, field := range v.Values {
+			fmt.Fprintf(&b, "\t%s %s `json:\"%s,omitempty\"`\n", ident(field.Name), g.fieldType(field), field.Name)
+		}
+		fmt.Fprintf(&b, "}\n")
+	}
+
+	fmt.Fprintf(&b, "\n// Client is the client of the %s service\n", svc.Name)
+	fmt.Fprintf(&b, "type Client struct {\n\tname string\n\tc    client.Client\n}\n")
+	fmt.Fprintf(&b, "\n// NewClient returns a client of the service, name defaults to ServiceName\n")
+	fmt.Fprintf(&b, "func NewClient(name string, c client.Client) *Client {\n")
+	fmt.Fprintf(&b, "\tif len(name) == 0 {\n\t\tname = ServiceName\n\t}\n")
+	fmt.Fprintf(&b, "\treturn &Client{name: name, c: c}\n}\n")
+	b.Write(body.Bytes())
+
+	return format.Source(b.Bytes())
+}
+
+type generator struct {
+	structs map[string]*regpb.Value
+	order   []string
+}
+
+// messageType returns the go type of a request or response
+func (g *generator) messageType(v *regpb.Value) string {
+	if v == nil || len(v.Type) == 0 || builtins[v.Type] {
+		return "interface{}"
+	}
+	return "*" + g.addStruct(v)
+}
+
+// fieldType returns the go type of a struct field
+func (g *generator) fieldType(v *regpb.Value) string {
+	switch {
+	case len(v.Values) > 0 && len(v.Type) > 0:
+		return "*" + g.addStruct(v)
+	case builtins[v.Type]:
+		return v.Type
+	case strings.HasPrefix(v.Type, "[]"):
+		if elem := strings.TrimPrefix(v.Type, "[]"); builtins[elem] {
+			return v.Type
+		}
+		return "[]interface{}"
+	}
+	return "interface{}"
+}
+
+// addStruct records the struct type of the value and returns its name
+func (g *generator) addStruct(v *regpb.Value) string {
+	name := ident(v.Type)
+	if _, ok := g.structs[name]; ok {
+		return name
+	}
+	g.structs[name] = v
+	g.order = append(g.order, name)
+
+	// register the nested types
+	for _, field := range v.Values {
+		g.fieldType(field)
+	}
+
+	return name
+}
+
+func newValue(typ string) string {
+	if strings.HasPrefix(typ, "*") {
+		return "&" + typ[1:] + "{}"
+	}
+	return "new(" + typ + ")"
+}
+
+// methodName returns the method part of an endpoint e.g Greeter.Hello => Hello
+func methodName(endpoint string) string {
+	parts := strings.Split(endpoint, ".")
+	return ident(parts[len(parts)-1])
+}
+
+// ident converts the name into an exported go identifier
+func ident(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, part := range parts {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	s := b.String()
+	if len(s) == 0 || unicode.IsDigit([]rune(s)[0]) {
+		s = "X" + s
+	}
+	return s
+}
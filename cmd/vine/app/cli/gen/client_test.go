@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+func TestGenerateClient(t *testing.T) {
+	svc := &regpb.Service{
+		Name: "go.vine.helloworld",
+		Endpoints: []*regpb.Endpoint{
+			{
+				Name: "Helloworld.Call",
+				Request: &regpb.Value{
+					Name: "CallRequest",
+					Type: "CallRequest",
+					Values: []*regpb.Value{
+						{Name: "name", Type: "string"},
+						{Name: "tags", Type: "[]string"},
+						{Name: "meta", Type: "Meta", Values: []*regpb.Value{{Name: "id", Type: "int64"}}},
+						{Name: "items", Type: "[]Item"},
+					},
+				},
+				Response: &regpb.Value{
+					Name:   "CallResponse",
+					Type:   "CallResponse",
+					Values: []*regpb.Value{{Name: "msg", Type: "string"}},
+				},
+			},
+			{
+				Name:     "Helloworld.Stream",
+				Request:  &regpb.Value{Name: "StreamRequest", Type: "StreamRequest"},
+				Response: &regpb.Value{Name: "StreamResponse", Type: "StreamResponse"},
+				Metadata: map[string]string{"stream": "true"},
+			},
+			{
+				Name:     "Debug.Call",
+				Request:  &regpb.Value{Name: "DebugRequest", Type: "DebugRequest"},
+				Response: &regpb.Value{Name: "DebugResponse", Type: "DebugResponse"},
+			},
+		},
+	}
+
+	b, err := GenerateClient("helloworld", svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "client.go", b, 0)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, b)
+	}
+
+	if testing.Short() {
+		t.Skip("skipping type check of the generated code in short mode")
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("helloworld", fset, []*ast.File{f}, nil); err != nil {
+		t.Fatalf("generated code does not compile: %v\n%s", err, b)
+	}
+
+	methods := make(map[string]bool)
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+			methods[fn.Name.Name] = true
+		}
+	}
+
+	for _, name := range []string{"HelloworldCall", "Stream", "DebugCall"} {
+		if !methods[name] {
+			t.Fatalf("Expected method %s in generated client, got %v", name, methods)
+		}
+	}
+}
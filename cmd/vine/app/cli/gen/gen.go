@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package gen generates code for services registered in the registry
+package gen
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/lack-io/cli"
+
+	"github.com/lack-io/vine/lib/cmd"
+	"github.com/lack-io/vine/util/helper"
+)
+
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:        "gen",
+			Usage:       "Generate code for a registered service",
+			Subcommands: []*cli.Command{cmdClient()},
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() > 0 {
+					command := c.Args().First()
+
+					v, err := exec.LookPath(command)
+					if err != nil {
+						fmt.Println(helper.UnexpectedSubcommand(c))
+						os.Exit(1)
+					}
+
+					// execute the command
+					ce := exec.Command(v, c.Args().Slice()[1:]...)
+					ce.Stdout = os.Stdout
+					ce.Stderr = os.Stderr
+					return ce.Run()
+				}
+				fmt.Println("No command provided to vine. Please refer to 'vine gen help'")
+				os.Exit(1)
+				return nil
+			},
+		},
+	}
+}
+
+func cmdClient() *cli.Command {
+	return &cli.Command{
+		Name:  "client",
+		Usage: "Generate a go client stub of a service from its registered endpoints, e.g vine gen client go.vine.helloworld",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "package",
+				Usage: "Package name of the generated code, defaults to the last segment of the service name",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output file, defaults to stdout",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return runClient(c)
+		},
+	}
+}
+
+func runClient(c *cli.Context) error {
+	name := c.Args().First()
+	if len(name) == 0 {
+		return fmt.Errorf("specify service name")
+	}
+
+	services, err := (*cmd.DefaultOptions().Registry).GetService(name)
+	if err != nil {
+		return fmt.Errorf("get service %s: %v", name, err)
+	}
+	if len(services) == 0 {
+		return fmt.Errorf("service %s not found", name)
+	}
+
+	pkg := c.String("package")
+	if len(pkg) == 0 {
+		parts := strings.Split(name, ".")
+		pkg = parts[len(parts)-1]
+	}
+
+	b, err := GenerateClient(pkg, services[0])
+	if err != nil {
+		return err
+	}
+
+	if output := c.String("output"); len(output) > 0 {
+		return ioutil.WriteFile(output, b, 0644)
+	}
+
+	fmt.Print(string(b))
+	return nil
+}
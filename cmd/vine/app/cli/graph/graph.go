@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package graph renders the service dependency graph of recorded trace spans
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lack-io/cli"
+
+	"github.com/lack-io/vine/lib/trace"
+)
+
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "graph",
+			Usage: "Build the service dependency graph from trace spans, e.g vine graph -i spans.json -f dot",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "input",
+					Aliases: []string{"i"},
+					Usage:   "File of the json encoded spans, an array or a stream of spans. Defaults to stdin",
+				},
+				&cli.StringFlag{
+					Name:    "format",
+					Aliases: []string{"f"},
+					Usage:   "Output format: text, dot or json",
+					Value:   "text",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return run(c)
+			},
+		},
+	}
+}
+
+func run(c *cli.Context) error {
+	in := io.Reader(os.Stdin)
+	if name := c.String("input"); len(name) > 0 && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	spans, err := readSpans(in)
+	if err != nil {
+		return fmt.Errorf("read spans: %v", err)
+	}
+
+	g := trace.NewGraph(spans)
+
+	switch c.String("format") {
+	case "text":
+		fmt.Print(g.String())
+	case "dot":
+		fmt.Print(string(g.DOT()))
+	case "json":
+		b, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	default:
+		return fmt.Errorf("unknown format %s", c.String("format"))
+	}
+
+	return nil
+}
+
+// readSpans decodes a json array of spans or a stream of json spans
+func readSpans(r io.Reader) ([]*trace.Span, error) {
+	br := bufio.NewReader(r)
+	b, err := br.Peek(1)
+	for err == nil && len(bytes.TrimSpace(b)) == 0 {
+		br.ReadByte()
+		b, err = br.Peek(1)
+	}
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(br)
+
+	if b[0] == '[' {
+		var spans []*trace.Span
+		if err := dec.Decode(&spans); err != nil {
+			return nil, err
+		}
+		return spans, nil
+	}
+
+	var spans []*trace.Span
+	for {
+		span := new(trace.Span)
+		if err := dec.Decode(span); err == io.EOF {
+			return spans, nil
+		} else if err != nil {
+			return nil, err
+		}
+		spans = append(spans, span)
+	}
+}
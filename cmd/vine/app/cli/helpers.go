@@ -28,7 +28,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"strings"
 	"text/tabwriter"
 
@@ -37,6 +39,7 @@ import (
 	"github.com/lack-io/vine/core/client"
 	"github.com/lack-io/vine/core/codec/bytes"
 	"github.com/lack-io/vine/lib/cmd"
+	signalutil "github.com/lack-io/vine/util/signal"
 )
 
 type exec func(*cli.Context, []string) ([]byte, error)
@@ -104,6 +107,8 @@ func addEnv(c *cli.Context, args []string) ([]byte, error) {
 	return nil, nil
 }
 
+// streamService calls a streaming endpoint and prints each message received
+// as a JSON line until the stream closes or the user interrupts it
 // TODO: stream via HTTP
 func streamService(c *cli.Context, args []string) ([]byte, error) {
 	if len(args) < 2 {
@@ -116,33 +121,69 @@ func streamService(c *cli.Context, args []string) ([]byte, error) {
 	// ignore error
 	json.Unmarshal([]byte(strings.Join(args[2:], " ")), &request)
 
+	// cancel the stream on Ctrl-C so the server doesn't leak it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signalutil.Shutdown()...)
+	defer signal.Stop(ch)
+
+	go func() {
+		select {
+		case <-ch:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	req := (*cmd.DefaultOptions().Client).NewRequest(service, endpoint, request, client.WithContentType("application/json"))
-	stream, err := (*cmd.DefaultOptions().Client).Stream(context.Background(), req)
+	stream, err := (*cmd.DefaultOptions().Client).Stream(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("error calling %s.%s: %v", service, endpoint, err)
 	}
+	defer stream.Close()
 
 	if err := stream.Send(request); err != nil {
 		return nil, fmt.Errorf("error sending to %s.%s: %v", service, endpoint, err)
 	}
 
 	output := c.String("output")
+	byt := b.NewBuffer([]byte{})
 
 	for {
+		var data []byte
 		if output == "raw" {
 			rsp := bytes.Frame{}
-			if err := stream.Recv(&rsp); err != nil {
-				return nil, fmt.Errorf("error receiving from %s.%s: %v", service, endpoint, err)
-			}
-			fmt.Print(string(rsp.Data))
+			err = stream.Recv(&rsp)
+			data = rsp.Data
 		} else {
-			var response map[string]interface{}
-			if err := stream.Recv(&response); err != nil {
-				return nil, fmt.Errorf("error receiving from %s.%s: %v", service, endpoint, err)
+			var response json.RawMessage
+			err = stream.Recv(&response)
+			data = response
+
+			// print each message on a single line
+			byt.Reset()
+			if err == nil && json.Compact(byt, response) == nil {
+				data = byt.Bytes()
 			}
-			b, _ := json.MarshalIndent(response, "", "\t")
-			fmt.Print(string(b))
 		}
+
+		switch {
+		case err == io.EOF:
+			return nil, nil
+		case err != nil && ctx.Err() != nil:
+			// interrupted by the user
+			return nil, nil
+		case err != nil:
+			return nil, fmt.Errorf("error receiving from %s.%s: %v", service, endpoint, err)
+		}
+
+		// the raw frames are printed as they are
+		if output == "raw" {
+			fmt.Print(string(data))
+			continue
+		}
+		fmt.Println(string(data))
 	}
 }
-
@@ -38,6 +38,17 @@ func Commands() []*cli.Command {
 			Name:        "new",
 			Usage:       "Create vine resource template",
 			Subcommands: []*cli.Command{cmdSRV(), cmdGateway(), cmdWeb(), cmdProto()},
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "output",
+					Usage: "Output of the summary of the created resource, tree or json",
+					Value: "tree",
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Print the files of the resource without writing them",
+				},
+			},
 			Action: func(c *cli.Context) error {
 				if c.Args().Len() > 0 {
 					command := c.Args().First()
@@ -65,13 +76,16 @@ func Commands() []*cli.Command {
 			Flags: []cli.Flag{
 				&cli.StringFlag{
 					Name:  "namespace",
-					Usage: "Namespace for the project e.g com.example",
-					Value: "go.vine",
+					Usage: "Namespace for the project e.g com.example, inferred from the git remote when not set",
 				},
 				&cli.BoolFlag{
 					Name:  "cluster",
 					Usage: "create cluster package.",
 				},
+				&cli.StringFlag{
+					Name:  "module",
+					Usage: "Module path written to go.mod and used by the imports of the generated code e.g github.com/foo/bar",
+				},
 			},
 			Action: func(c *cli.Context) error {
 				runInit(c)
@@ -46,6 +46,11 @@ func runGateway(ctx *cli.Context) {
 
 	var plugins []string
 	atype := "web"
+	// the gateway serves a handler of its own
+	withService := ctx.Bool("with-service")
+	if withService {
+		atype = "service"
+	}
 	dir, _ := os.Getwd()
 	namespace := cfg.Package.Namespace
 	cluster := cfg.Package.Kind == "cluster"
@@ -67,6 +72,11 @@ func runGateway(ctx *cli.Context) {
 		name = filepath.Base(dir)
 	}
 
+	if err := checkName(name); err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	alias := strings.Join([]string{namespace, atype, name}, ".")
 
 	// set the command
@@ -100,12 +110,7 @@ func runGateway(ctx *cli.Context) {
 	}
 
 	goDir := dir
-	dir = strings.TrimPrefix(dir, goPath+"/src/")
-	if runtime.GOOS == "windows" {
-		dir = strings.TrimPrefix(dir, goPath+"\\src\\")
-	} else {
-		dir = strings.TrimPrefix(dir, goPath+"/src/")
-	}
+	dir = importPath(cfg, dir, goPath)
 	c := config{
 		Name:      name,
 		Command:   command,
@@ -116,9 +121,13 @@ func runGateway(ctx *cli.Context) {
 		Dir:       dir,
 		GoDir:     goDir,
 		GoPath:    goPath,
+		Group:     name,
+		Version:   "v1",
 		Plugins:   plugins,
 		Comments:  protoComments(dir, name),
 		Toml:      cfg,
+		Output:    ctx.String("output"),
+		DryRun:    ctx.Bool("dry-run"),
 	}
 
 	c.GoVersion = version.GoV()
@@ -147,22 +156,59 @@ func runGateway(ctx *cli.Context) {
 		Dir:     filepath.Join(c.Dir, c.Name),
 		Flags:   defaultFlag,
 	})
-	// create gateway config
-	c.Files = []file{
+	if withService {
+		c.Toml.Proto = append(
+			c.Toml.Proto,
+			tool.Proto{
+				Name:    name,
+				Pb:      filepath.Join(c.Dir, "proto", "service", name, "v1", name+".proto"),
+				Group:   name,
+				Version: "v1",
+				Type:    "service",
+				Plugins: []string{"vine", "validator"},
+			},
+		)
+	}
+	c.Files = gatewayFiles(c, withService)
+
+	if err := create(c); err != nil {
+		fmt.Println(err)
+		return
+	}
+}
+
+// gatewayFiles returns the files of a gateway template, withService adds a vine
+// handler served by the gateway and its proto annotated with the http mappings
+func gatewayFiles(c config, withService bool) []file {
+	name := c.Name
+
+	if !withService {
+		return []file{
+			{"cmd/" + name + "/main.go", t2.ClusterCMD},
+			{"pkg/runtime/doc.go", t2.Doc},
+			{"pkg/" + name + "/plugin.go", t2.ClusterPlugin},
+			{"pkg/" + name + "/app.go", t2.GatewayApp},
+			{"deploy/docker/" + name + "/Dockerfile", t2.DockerSRV},
+			{"deploy/config/" + name + ".ini", t2.ConfSRV},
+			{"deploy/systemd/" + name + ".service", t2.SystemedSRV},
+			{"vine.toml", t2.TOML},
+		}
+	}
+
+	return []file{
 		{"cmd/" + name + "/main.go", t2.ClusterCMD},
 		{"pkg/runtime/doc.go", t2.Doc},
+		{"pkg/runtime/inject/inject.go", t2.Inject},
 		{"pkg/" + name + "/plugin.go", t2.ClusterPlugin},
-		{"pkg/" + name + "/app.go", t2.GatewayApp},
+		{"pkg/" + name + "/app.go", t2.GatewaySRVApp},
+		{"pkg/" + name + "/server/" + name + ".go", t2.GatewaySRV},
+		{"pkg/" + name + "/service/" + name + ".go", t2.ServiceSRV},
 		{"deploy/docker/" + name + "/Dockerfile", t2.DockerSRV},
 		{"deploy/config/" + name + ".ini", t2.ConfSRV},
 		{"deploy/systemd/" + name + ".service", t2.SystemedSRV},
+		{"proto/service/" + name + "/v1/" + name + ".proto", t2.ProtoGateway},
 		{"vine.toml", t2.TOML},
 	}
-
-	if err := create(c); err != nil {
-		fmt.Println(err)
-		return
-	}
 }
 
 func cmdGateway() *cli.Command {
@@ -174,6 +220,10 @@ func cmdGateway() *cli.Command {
 				Name:  "plugin",
 				Usage: "Specify plugins e.g --plugin=registry=etcd:broker=nats or use flag multiple times",
 			},
+			&cli.BoolFlag{
+				Name:  "with-service",
+				Usage: "Create a vine handler served by the gateway, with a proto annotated with the http mappings",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			runGateway(c)
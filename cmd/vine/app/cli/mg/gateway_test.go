@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mg
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGatewayFilesWithService(t *testing.T) {
+	c := testConfig(t, true)
+	c.Type = "service"
+	c.Files = gatewayFiles(c, true)
+
+	if err := create(c); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{
+		"cmd/foo/main.go",
+		"pkg/foo/app.go",
+		"pkg/foo/server/foo.go",
+		"pkg/foo/service/foo.go",
+		"pkg/runtime/inject/inject.go",
+		"proto/service/foo/v1/foo.proto",
+		"vine.toml",
+	} {
+		path := filepath.Join(c.GoDir, name)
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Expected %s to be generated: %v", name, err)
+		}
+		if strings.HasSuffix(name, ".go") {
+			if _, err := parser.ParseFile(token.NewFileSet(), path, b, 0); err != nil {
+				t.Fatalf("generated %s does not parse: %v\n%s", name, err, b)
+			}
+		}
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(c.GoDir, "proto/service/foo/v1/foo.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mapping := range []string{"+gen:post=/foo/v1/foo/Call", "+gen:get=/foo/v1/foo/{name}"} {
+		if !strings.Contains(string(b), mapping) {
+			t.Fatalf("Expected the proto to map %s:\n%s", mapping, b)
+		}
+	}
+
+	b, err = ioutil.ReadFile(filepath.Join(c.GoDir, "pkg/foo/app.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "example.com/foo/pkg/foo/server") {
+		t.Fatalf("Expected the gateway to serve the handler:\n%s", b)
+	}
+}
+
+func TestGatewayFiles(t *testing.T) {
+	c := testConfig(t, true)
+	c.Type = "web"
+	c.Files = gatewayFiles(c, false)
+
+	if err := create(c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(c.GoDir, "pkg/foo/app.go")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(c.GoDir, "proto")); !os.IsNotExist(err) {
+		t.Fatal("Expected no proto without the service")
+	}
+}
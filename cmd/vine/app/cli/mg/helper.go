@@ -23,9 +23,13 @@
 package mg
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"text/template"
@@ -39,6 +43,54 @@ import (
 
 var defaultFlag = []string{"-a", "-installsuffix", "cgo", `-ldflags "-s -w"`}
 
+// reservedNames are the names of the built-in services
+var reservedNames = []string{"api", "auth", "broker", "config", "network", "proxy", "registry", "router", "runtime", "store", "web"}
+
+// importPath returns the import path of the project in dir, the module of
+// the project when set, otherwise dir relative to the GOPATH
+func importPath(cfg *tool.Config, dir, goPath string) string {
+	if cfg != nil && len(cfg.Package.Module) > 0 {
+		return cfg.Package.Module
+	}
+	if runtime.GOOS == "windows" {
+		return strings.TrimPrefix(dir, goPath+"\\src\\")
+	}
+	return strings.TrimPrefix(dir, goPath+"/src/")
+}
+
+// checkModule returns an error when the path doesn't look like a module path,
+// e.g. github.com/lack-io/vine
+func checkModule(module string) error {
+	if len(module) == 0 {
+		return fmt.Errorf("invalid module path: empty")
+	}
+	if strings.HasPrefix(module, "/") || strings.HasSuffix(module, "/") {
+		return fmt.Errorf("invalid module path %s: leading or trailing slash", module)
+	}
+	for _, elem := range strings.Split(module, "/") {
+		if len(elem) == 0 || elem == "." || elem == ".." || strings.HasPrefix(elem, ".") || strings.HasSuffix(elem, ".") {
+			return fmt.Errorf("invalid module path %s: invalid element '%s'", module, elem)
+		}
+		for _, r := range elem {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-._~", r)) {
+				return fmt.Errorf("invalid module path %s: invalid char '%c'", module, r)
+			}
+		}
+	}
+	return nil
+}
+
+// checkName returns an error when the name clashes with a built-in service
+func checkName(name string) error {
+	for _, r := range reservedNames {
+		if strings.EqualFold(name, r) {
+			return fmt.Errorf("invalid service name: '%s' is reserved by the built-in services (%s), use a more specific name e.g. 'my-%s'",
+				name, strings.Join(reservedNames, ", "), r)
+		}
+	}
+	return nil
+}
+
 func protoComments(goDir, name string) []string {
 	return []string{
 		"\ndownload protoc zip packages (protoc-$VERSION-$PLATFORM.zip) and install:\n",
@@ -91,6 +143,10 @@ type config struct {
 	Comments []string
 	// Plugins registry=etcd:broker=nats
 	Plugins []string
+	// Output of the summary, the tree view when empty or json
+	Output string
+	// DryRun renders the files without writing them
+	DryRun bool
 
 	Toml *tool.Config
 }
@@ -100,7 +156,8 @@ type file struct {
 	Tmpl string
 }
 
-func write(c config, file, tmpl string) error {
+// render executes the template of a file with the config
+func render(c config, w io.Writer, tmpl string) error {
 	fn := template.FuncMap{
 		"title": strings.Title,
 		"quota": func(s string) string {
@@ -135,6 +192,15 @@ func write(c config, file, tmpl string) error {
 
 	c.Toml.Proto = append(apiProtos, svcProtos...)
 
+	t, err := template.New("f").Funcs(fn).Parse(tmpl)
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(w, c)
+}
+
+func write(c config, file, tmpl string) error {
 	var f *os.File
 	var err error
 	stat, _ := os.Stat(file)
@@ -151,21 +217,77 @@ func write(c config, file, tmpl string) error {
 	}
 	defer f.Close()
 
-	t, err := template.New("f").Funcs(fn).Parse(tmpl)
-	if err != nil {
-		return err
+	return render(c, f, tmpl)
+}
+
+// summary is the json output of a created resource
+type summary struct {
+	Name     string   `json:"name"`
+	FQDN     string   `json:"fqdn"`
+	Type     string   `json:"type"`
+	Dir      string   `json:"dir"`
+	Files    []string `json:"files"`
+	Commands []string `json:"commands"`
+	// DryRun is set when the files were rendered without being written
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// summarize writes the json summary of the created resource
+func summarize(c config, w io.Writer) error {
+	s := summary{
+		Name:     c.Name,
+		FQDN:     c.Alias,
+		Type:     c.Type,
+		Dir:      c.GoDir,
+		Files:    make([]string, 0, len(c.Files)),
+		Commands: make([]string, 0, len(c.Comments)),
+		DryRun:   c.DryRun,
+	}
+	for _, file := range c.Files {
+		s.Files = append(s.Files, file.Path)
+	}
+	for _, comment := range c.Comments {
+		if comment = strings.TrimSpace(comment); len(comment) > 0 {
+			s.Commands = append(s.Commands, comment)
+		}
 	}
 
-	return t.Execute(f, c)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
 }
 
 func create(c config) error {
-	fmt.Printf("Creating resource %s in %s\n\n", c.Name, c.GoDir)
+	switch c.Output {
+	case "", "tree":
+		if c.DryRun {
+			fmt.Printf("Creating resource %s in %s (dry run)\n\n", c.Name, c.GoDir)
+		} else {
+			fmt.Printf("Creating resource %s in %s\n\n", c.Name, c.GoDir)
+		}
+	case "json":
+	default:
+		return fmt.Errorf("unsupported output %s, use tree or json", c.Output)
+	}
 
 	t := treeprint.New()
 
+	// the size of the rendered files of a dry run
+	sizes := make([]int, 0, len(c.Files))
+
 	// write the files
 	for _, file := range c.Files {
+		addFileToTree(t, file.Path)
+
+		if c.DryRun {
+			buf := bytes.NewBuffer(nil)
+			if err := render(c, buf, file.Tmpl); err != nil {
+				return fmt.Errorf("%s: %v", file.Path, err)
+			}
+			sizes = append(sizes, buf.Len())
+			continue
+		}
+
 		f := filepath.Join(c.GoDir, file.Path)
 		dir := filepath.Dir(f)
 
@@ -173,17 +295,30 @@ func create(c config) error {
 			_ = os.MkdirAll(dir, 0755)
 		}
 
-		addFileToTree(t, file.Path)
 		if err := write(c, f, file.Tmpl); err != nil {
 			return err
 		}
 	}
 
-	// print tree
-	fmt.Println(t.String())
+	if c.Output == "json" {
+		if err := summarize(c, os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		// print tree
+		fmt.Println(t.String())
 
-	for _, comment := range c.Comments {
-		fmt.Println(comment)
+		if c.DryRun {
+			for i, file := range c.Files {
+				fmt.Printf("%s (%d bytes)\n", file.Path, sizes[i])
+			}
+			fmt.Println("\nno files written")
+			return nil
+		}
+
+		for _, comment := range c.Comments {
+			fmt.Println(comment)
+		}
 	}
 
 	// just wait
@@ -25,7 +25,11 @@ package mg
 import (
 	"fmt"
 	"go/build"
+	"net/url"
 	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -50,29 +54,66 @@ func runInit(ctx *cli.Context) {
 	}
 
 	dir, _ := os.Getwd()
-	goDir := dir
-	if runtime.GOOS == "windows" {
-		goDir = strings.TrimPrefix(goDir, goPath+"\\src\\")
-	} else {
-		goDir = strings.TrimPrefix(goDir, goPath+"/src/")
+	if len(namespace) == 0 {
+		namespace = inferNamespace(dir)
 	}
-	c := config{
-		Namespace: namespace,
-		Cluster:   cluster,
-		Dir:       goDir,
-		GoDir:     dir,
+
+	if _, err := os.Stat("vine.toml"); !os.IsNotExist(err) {
+		fmt.Println("vine.toml already exists")
+		return
+	}
+
+	c, err := initConfig(dir, goPath, namespace, ctx.String("module"), cluster, useGoModule != "off")
+	if err != nil {
+		fmt.Println(err)
+		return
 	}
 
 	c.GoVersion = version.GoV()
 	c.VineVersion = version.GitTag
 
-	if _, err := os.Stat("vine.toml"); !os.IsNotExist(err) {
-		fmt.Println("vine.toml already exists")
+	if err := create(c); err != nil {
+		fmt.Println(err)
 		return
 	}
+}
+
+// initConfig returns the config of the project in dir. With go modules the
+// module path is written to go.mod and vine.toml and used by the imports of
+// the generated code, it defaults to dir relative to the GOPATH or the name
+// of dir outside the GOPATH.
+func initConfig(dir, goPath, namespace, module string, cluster, goModule bool) (config, error) {
+	if len(module) > 0 && !goModule {
+		return config{}, fmt.Errorf("--module requires go modules, GO111MODULE is off")
+	}
+
+	importDir := importPath(nil, dir, goPath)
+	if goModule {
+		if len(module) == 0 {
+			module = importDir
+			// outside the GOPATH
+			if filepath.IsAbs(module) {
+				module = filepath.Base(module)
+			}
+			module = path.Clean(filepath.ToSlash(module))
+		}
+		if err := checkModule(module); err != nil {
+			return config{}, err
+		}
+		importDir = module
+	}
+
+	c := config{
+		Namespace: namespace,
+		Cluster:   cluster,
+		Dir:       importDir,
+		GoDir:     dir,
+	}
+
 	c.Toml = &tool.Config{
 		Package: tool.Package{
 			Namespace: namespace,
+			Module:    module,
 		},
 	}
 	if cluster {
@@ -88,12 +129,66 @@ func runInit(ctx *cli.Context) {
 	}
 
 	// set gomodule
-	if useGoModule != "off" {
+	if goModule {
 		c.Files = append(c.Files, file{"go.mod", t2.Module})
 	}
 
-	if err := create(c); err != nil {
-		fmt.Println(err)
-		return
+	return c, nil
+}
+
+// defaultNamespace is used when the namespace can't be inferred
+const defaultNamespace = "go.vine"
+
+// inferNamespace returns the namespace derived from the origin remote of
+// the git repository in dir, falling back to the default namespace
+func inferNamespace(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "config", "--get", "remote.origin.url").Output()
+	if err != nil {
+		return defaultNamespace
+	}
+
+	if ns, ok := namespaceFromRemote(strings.TrimSpace(string(out))); ok {
+		return ns
 	}
+	return defaultNamespace
+}
+
+// namespaceFromRemote converts a git remote into a namespace of the reversed
+// host followed by the path, e.g git@github.com:lack-io/vine.git => com.github.lack-io.vine
+func namespaceFromRemote(remote string) (string, bool) {
+	var host, repoPath string
+
+	if u, err := url.Parse(remote); err == nil && len(u.Host) > 0 {
+		// https://github.com/lack-io/vine.git, ssh://git@github.com/lack-io/vine.git
+		host = u.Hostname()
+		repoPath = u.Path
+	} else if i := strings.Index(remote, ":"); i > 0 {
+		// git@github.com:lack-io/vine.git
+		host = remote[:i]
+		repoPath = remote[i+1:]
+		if j := strings.Index(host, "@"); j >= 0 {
+			host = host[j+1:]
+		}
+	} else {
+		return "", false
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if len(host) == 0 || len(repoPath) == 0 {
+		return "", false
+	}
+
+	hosts := strings.Split(strings.ToLower(host), ".")
+	for i, j := 0, len(hosts)-1; i < j; i, j = i+1, j-1 {
+		hosts[i], hosts[j] = hosts[j], hosts[i]
+	}
+
+	parts := hosts
+	for _, p := range strings.Split(strings.ToLower(repoPath), "/") {
+		if len(p) > 0 {
+			parts = append(parts, strings.ReplaceAll(p, ".", "-"))
+		}
+	}
+
+	return strings.Join(parts, "."), true
 }
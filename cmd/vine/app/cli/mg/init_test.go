@@ -0,0 +1,160 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mg
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lack-io/vine/cmd/vine/app/cli/util/tool"
+)
+
+func TestNamespaceFromRemote(t *testing.T) {
+	testData := []struct {
+		remote    string
+		namespace string
+		ok        bool
+	}{
+		{"git@github.com:lack-io/vine.git", "com.github.lack-io.vine", true},
+		{"https://github.com/lack-io/vine.git", "com.github.lack-io.vine", true},
+		{"ssh://git@gitlab.example.com:2222/team/app", "com.example.gitlab.team.app", true},
+		{"vine", "", false},
+	}
+
+	for _, d := range testData {
+		ns, ok := namespaceFromRemote(d.remote)
+		if ok != d.ok || ns != d.namespace {
+			t.Fatalf("Expected namespace %q (%v) for %s, got %q (%v)", d.namespace, d.ok, d.remote, ns, ok)
+		}
+	}
+}
+
+func TestInferNamespace(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	// not a git repository
+	dir := t.TempDir()
+	if ns := inferNamespace(dir); ns != defaultNamespace {
+		t.Fatalf("Expected namespace %s, got %s", defaultNamespace, ns)
+	}
+
+	if err := exec.Command("git", "-C", dir, "init").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a repository without remote
+	if ns := inferNamespace(dir); ns != defaultNamespace {
+		t.Fatalf("Expected namespace %s, got %s", defaultNamespace, ns)
+	}
+
+	if err := exec.Command("git", "-C", dir, "remote", "add", "origin", "git@github.com:lack-io/vine.git").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if ns := inferNamespace(dir); ns != "com.github.lack-io.vine" {
+		t.Fatalf("Expected namespace com.github.lack-io.vine, got %s", ns)
+	}
+}
+
+func TestInitConfigModule(t *testing.T) {
+	// outside the GOPATH the module defaults to the name of the directory
+	dir := t.TempDir()
+	c, err := initConfig(dir, "/go", "go.vine", "", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := create(c); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "module "+filepath.Base(dir)+"\n") {
+		t.Fatalf("Expected the module %s:\n%s", filepath.Base(dir), b)
+	}
+
+	// the module set with --module is used by the generated code
+	dir = t.TempDir()
+	c, err = initConfig(dir, "/go", "go.vine", "example.com/foo/bar", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := create(c); err != nil {
+		t.Fatal(err)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "module example.com/foo/bar\n") {
+		t.Fatalf("Expected the module example.com/foo/bar:\n%s", b)
+	}
+
+	cfg, err := tool.New(filepath.Join(dir, "vine.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Package.Module != "example.com/foo/bar" {
+		t.Fatalf("Expected the module in vine.toml, got %q", cfg.Package.Module)
+	}
+
+	sc := testConfig(t, false)
+	sc.Dir = importPath(cfg, dir, "/go")
+	sc.Files = serviceFiles(sc, false, false)
+	if err := create(sc); err != nil {
+		t.Fatal(err)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(sc.GoDir, "cmd", "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"example.com/foo/bar/pkg"`) {
+		t.Fatalf("Expected the imports of the module:\n%s", b)
+	}
+}
+
+func TestCheckModule(t *testing.T) {
+	for _, module := range []string{"foo", "example.com/foo/bar", "github.com/lack-io/vine", "example.com/foo/v2"} {
+		if err := checkModule(module); err != nil {
+			t.Fatalf("Expected module %s to be valid: %v", module, err)
+		}
+	}
+	for _, module := range []string{"", "/home/foo", "./foo", "foo/", "foo//bar", "foo bar", "../foo", "C:\\foo"} {
+		if err := checkModule(module); err == nil {
+			t.Fatalf("Expected module %s to be invalid", module)
+		}
+	}
+
+	if _, err := initConfig(t.TempDir(), "/go", "go.vine", "./foo", false, true); err == nil {
+		t.Fatal("Expected an invalid module to be rejected")
+	}
+	if _, err := initConfig(t.TempDir(), "/go", "go.vine", "example.com/foo", false, false); err == nil {
+		t.Fatal("Expected --module to require go modules")
+	}
+}
@@ -27,7 +27,6 @@ import (
 	"go/build"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/lack-io/cli"
@@ -66,11 +65,7 @@ func runProto(ctx *cli.Context) {
 
 	dir, _ := os.Getwd()
 	goDir := dir
-	if runtime.GOOS == "windows" {
-		dir = strings.TrimPrefix(dir, build.Default.GOPATH+"\\src\\")
-	} else {
-		dir = strings.TrimPrefix(dir, build.Default.GOPATH+"/src/")
-	}
+	dir = importPath(cfg, dir, build.Default.GOPATH)
 	c := config{
 		Name:    name,
 		Type:    atype,
@@ -80,6 +75,8 @@ func runProto(ctx *cli.Context) {
 		GoDir:   goDir,
 		Version: pv,
 		Toml:    cfg,
+		Output:  ctx.String("output"),
+		DryRun:  ctx.Bool("dry-run"),
 	}
 
 	c.GoVersion = version.GoV()
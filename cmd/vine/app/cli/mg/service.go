@@ -68,6 +68,11 @@ func runSRV(ctx *cli.Context) {
 		name = filepath.Base(dir)
 	}
 
+	if err := checkName(name); err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	alias := strings.Join([]string{namespace, atype, name}, ".")
 
 	// set the command
@@ -101,13 +106,14 @@ func runSRV(ctx *cli.Context) {
 	}
 
 	withAPI := ctx.Bool("with-api")
+	noProto := ctx.Bool("no-proto")
+	if withAPI && noProto {
+		fmt.Println("--with-api requires protobuf, it can't be used with --no-proto")
+		return
+	}
 
 	goDir := dir
-	if runtime.GOOS == "windows" {
-		dir = strings.TrimPrefix(dir, goPath+"\\src\\")
-	} else {
-		dir = strings.TrimPrefix(dir, goPath+"/src/")
-	}
+	dir = importPath(cfg, dir, goPath)
 	c := config{
 		Name:      name,
 		Command:   command,
@@ -121,8 +127,13 @@ func runSRV(ctx *cli.Context) {
 		Group:     name,
 		Version:   "v1",
 		Plugins:   plugins,
-		Comments:  protoComments(dir, name),
 		Toml:      cfg,
+		Output:    ctx.String("output"),
+		DryRun:    ctx.Bool("dry-run"),
+	}
+
+	if !noProto {
+		c.Comments = protoComments(dir, name)
 	}
 
 	c.GoVersion = version.GoV()
@@ -148,38 +159,20 @@ func runSRV(ctx *cli.Context) {
 			Dir:     filepath.Join(c.Dir, c.Name),
 			Flags:   defaultFlag,
 		})
-		c.Toml.Proto = append(
-			c.Toml.Proto,
-			tool.Proto{
-				Name:    name,
-				Pb:      filepath.Join(c.Dir, "proto", "service", name, "v1", name+".proto"),
-				Group:   name,
-				Version: "v1",
-				Type:    "service",
-				Plugins: []string{"vine", "validator"},
-			},
-		)
-		// create service config
-		srvTpl := t2.ClusterSRV
-		if withAPI {
-			srvTpl = t2.ClusterSRVWithAPI
-		}
-		c.Files = []file{
-			{"cmd/" + name + "/main.go", t2.ClusterCMD},
-			{"pkg/runtime/doc.go", t2.Doc},
-			{"pkg/runtime/inject/inject.go", t2.Inject},
-			{"pkg/" + name + "/plugin.go", t2.ClusterPlugin},
-			{"pkg/" + name + "/app.go", t2.ClusterApp},
-			{"pkg/" + name + "/server/" + name + ".go", srvTpl},
-			{"pkg/" + name + "/service/" + name + ".go", t2.ServiceSRV},
-			{"pkg/" + name + "/dao/" + name + ".go", t2.DaoHandler},
-			{"deploy/docker/" + name + "/Dockerfile", t2.DockerSRV},
-			{"deploy/config/" + name + ".ini", t2.ConfSRV},
-			{"deploy/systemd/" + name + ".service", t2.SystemedSRV},
-			{"proto/service/" + name + "/v1/" + name + ".proto", t2.ProtoSRV},
-			{"Makefile", t2.ClusterMakefile},
-			{"vine.toml", t2.TOML},
+		if !noProto {
+			c.Toml.Proto = append(
+				c.Toml.Proto,
+				tool.Proto{
+					Name:    name,
+					Pb:      filepath.Join(c.Dir, "proto", "service", name, "v1", name+".proto"),
+					Group:   name,
+					Version: "v1",
+					Type:    "service",
+					Plugins: []string{"vine", "validator"},
+				},
+			)
 		}
+		c.Files = serviceFiles(c, withAPI, noProto)
 	} else {
 		if c.Toml.Pkg != nil {
 			fmt.Printf("service %s already exists", name)
@@ -194,44 +187,97 @@ func runSRV(ctx *cli.Context) {
 			Dir:     filepath.Join(c.Dir),
 			Flags:   defaultFlag,
 		}
-		c.Toml.Proto = append(
-			c.Toml.Proto,
-			tool.Proto{
-				Name:    name,
-				Pb:      filepath.Join(c.Dir, "proto", "service", name, "v1", name+".proto"),
-				Group:   name,
-				Version: "v1",
-				Type:    "service",
-				Plugins: []string{"gogo", "vine", "validator"},
-			},
-		)
+		if !noProto {
+			c.Toml.Proto = append(
+				c.Toml.Proto,
+				tool.Proto{
+					Name:    name,
+					Pb:      filepath.Join(c.Dir, "proto", "service", name, "v1", name+".proto"),
+					Group:   name,
+					Version: "v1",
+					Type:    "service",
+					Plugins: []string{"gogo", "vine", "validator"},
+				},
+			)
+		}
+		c.Files = serviceFiles(c, withAPI, noProto)
+	}
+
+	if err := create(c); err != nil {
+		fmt.Println(err)
+		return
+	}
+}
+
+// serviceFiles returns the files of a service template
+func serviceFiles(c config, withAPI, noProto bool) []file {
+	name := c.Name
+
+	if c.Cluster {
 		// create service config
-		srvTpl := t2.SingleSRV
+		srvTpl := t2.ClusterSRV
 		if withAPI {
-			srvTpl = t2.SingleSRVWithAPI
+			srvTpl = t2.ClusterSRVWithAPI
+		}
+		testTpl := t2.TestSRV
+		if noProto {
+			srvTpl = t2.ClusterSRVNoProto
+			testTpl = t2.TestSRVNoProto
 		}
-		c.Files = []file{
-			{"cmd/main.go", t2.SingleCMD},
+		files := []file{
+			{"cmd/" + name + "/main.go", t2.ClusterCMD},
 			{"pkg/runtime/doc.go", t2.Doc},
 			{"pkg/runtime/inject/inject.go", t2.Inject},
-			{"pkg/plugin.go", t2.SinglePlugin},
-			{"pkg/app.go", t2.SingleApp},
-			{"pkg/server/" + name + ".go", srvTpl},
-			{"pkg/service/" + name + ".go", t2.ServiceSRV},
-			{"pkg/dao/" + name + ".go", t2.DaoHandler},
-			{"deploy/Dockerfile", t2.DockerSRV},
-			{"deploy/" + name + ".ini", t2.ConfSRV},
-			{"deploy/" + name + ".service", t2.SystemedSRV},
-			{"proto/service/" + name + "/v1/" + name + ".proto", t2.ProtoSRV},
-			{"Makefile", t2.SingleMakefile},
-			{"vine.toml", t2.TOML},
+			{"pkg/" + name + "/plugin.go", t2.ClusterPlugin},
+			{"pkg/" + name + "/app.go", t2.ClusterApp},
+			{"pkg/" + name + "/server/" + name + ".go", srvTpl},
+			{"pkg/" + name + "/server/" + name + "_test.go", testTpl},
+			{"pkg/" + name + "/service/" + name + ".go", t2.ServiceSRV},
+			{"pkg/" + name + "/dao/" + name + ".go", t2.DaoHandler},
+			{"deploy/docker/" + name + "/Dockerfile", t2.DockerSRV},
+			{"deploy/config/" + name + ".ini", t2.ConfSRV},
+			{"deploy/systemd/" + name + ".service", t2.SystemedSRV},
+		}
+		if !noProto {
+			files = append(files, file{"proto/service/" + name + "/v1/" + name + ".proto", t2.ProtoSRV})
 		}
+		return append(files,
+			file{"Makefile", t2.ClusterMakefile},
+			file{"vine.toml", t2.TOML},
+		)
 	}
 
-	if err := create(c); err != nil {
-		fmt.Println(err)
-		return
+	// create service config
+	srvTpl := t2.SingleSRV
+	if withAPI {
+		srvTpl = t2.SingleSRVWithAPI
+	}
+	testTpl := t2.TestSRV
+	if noProto {
+		srvTpl = t2.SingleSRVNoProto
+		testTpl = t2.TestSRVNoProto
+	}
+	files := []file{
+		{"cmd/main.go", t2.SingleCMD},
+		{"pkg/runtime/doc.go", t2.Doc},
+		{"pkg/runtime/inject/inject.go", t2.Inject},
+		{"pkg/plugin.go", t2.SinglePlugin},
+		{"pkg/app.go", t2.SingleApp},
+		{"pkg/server/" + name + ".go", srvTpl},
+		{"pkg/server/" + name + "_test.go", testTpl},
+		{"pkg/service/" + name + ".go", t2.ServiceSRV},
+		{"pkg/dao/" + name + ".go", t2.DaoHandler},
+		{"deploy/Dockerfile", t2.DockerSRV},
+		{"deploy/" + name + ".ini", t2.ConfSRV},
+		{"deploy/" + name + ".service", t2.SystemedSRV},
 	}
+	if !noProto {
+		files = append(files, file{"proto/service/" + name + "/v1/" + name + ".proto", t2.ProtoSRV})
+	}
+	return append(files,
+		file{"Makefile", t2.SingleMakefile},
+		file{"vine.toml", t2.TOML},
+	)
 }
 
 func cmdSRV() *cli.Command {
@@ -247,6 +293,10 @@ func cmdSRV() *cli.Command {
 				Name:  "with-api",
 				Usage: "Specify restful api code for service",
 			},
+			&cli.BoolFlag{
+				Name:  "no-proto",
+				Usage: "Create the service without protobuf, the handler uses plain go types",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			runSRV(c)
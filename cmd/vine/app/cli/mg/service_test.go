@@ -0,0 +1,267 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mg
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lack-io/cli"
+
+	"github.com/lack-io/vine/cmd/vine/app/cli/util/tool"
+)
+
+func testConfig(t *testing.T, cluster bool) config {
+	kind := "single"
+	if cluster {
+		kind = "cluster"
+	}
+	toml := &tool.Config{
+		Package: tool.Package{Kind: kind, Namespace: "go.vine"},
+	}
+	if cluster {
+		toml.Mod = &tool.Mods{{Name: "foo"}}
+	} else {
+		toml.Pkg = &tool.Mod{Name: "foo"}
+	}
+	return config{
+		Name:      "foo",
+		Namespace: "go.vine",
+		Type:      "service",
+		Cluster:   cluster,
+		Alias:     "go.vine.service.foo",
+		Dir:       "example.com/foo",
+		GoDir:     t.TempDir(),
+		Group:     "foo",
+		Version:   "v1",
+		Toml:      toml,
+	}
+}
+
+func TestServiceFilesNoProto(t *testing.T) {
+	for _, cluster := range []bool{false, true} {
+		c := testConfig(t, cluster)
+		c.Files = serviceFiles(c, false, true)
+
+		if err := create(c); err != nil {
+			t.Fatal(err)
+		}
+
+		var server string
+		err := filepath.Walk(c.GoDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasSuffix(path, ".proto") || strings.Contains(path, string(filepath.Separator)+"proto") {
+				t.Fatalf("Expected no proto files, got %s", path)
+			}
+			if strings.HasSuffix(path, filepath.Join("server", "foo.go")) {
+				server = path
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(server) == 0 {
+			t.Fatal("Expected server file to be generated")
+		}
+
+		b, err := ioutil.ReadFile(server)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), server, b, 0); err != nil {
+			t.Fatalf("generated server does not parse: %v\n%s", err, b)
+		}
+		if strings.Contains(string(b), "proto/service") {
+			t.Fatalf("Expected server without proto imports:\n%s", b)
+		}
+
+		toml, err := ioutil.ReadFile(filepath.Join(c.GoDir, "vine.toml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(toml), "[[proto]]") {
+			t.Fatalf("Expected vine.toml without proto:\n%s", toml)
+		}
+	}
+}
+
+func TestServiceFiles(t *testing.T) {
+	c := testConfig(t, false)
+
+	var proto bool
+	for _, f := range serviceFiles(c, false, false) {
+		if strings.HasSuffix(f.Path, ".proto") {
+			proto = true
+		}
+	}
+	if !proto {
+		t.Fatal("Expected proto file in service template")
+	}
+}
+
+func TestCheckName(t *testing.T) {
+	for _, name := range []string{"auth", "Registry", "store", "api"} {
+		if err := checkName(name); err == nil {
+			t.Fatalf("expected reserved name %s to be rejected", name)
+		}
+	}
+	for _, name := range []string{"helloworld", "auth-service", "my-store"} {
+		if err := checkName(name); err != nil {
+			t.Fatalf("expected name %s to be accepted: %v", name, err)
+		}
+	}
+}
+
+func TestServiceFilesTest(t *testing.T) {
+	for _, noProto := range []bool{false, true} {
+		c := testConfig(t, true)
+		c.Files = serviceFiles(c, false, noProto)
+
+		if err := create(c); err != nil {
+			t.Fatal(err)
+		}
+
+		path := filepath.Join(c.GoDir, "pkg", "foo", "server", "foo_test.go")
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Expected test file to be generated: %v", err)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), path, b, 0); err != nil {
+			t.Fatalf("generated test does not parse: %v\n%s", err, b)
+		}
+
+		handler := "&server{"
+		if noProto {
+			handler = "&Foo{"
+		}
+		if !strings.Contains(string(b), handler) || !strings.Contains(string(b), "func TestFooCall(t *testing.T)") {
+			t.Fatalf("Expected test of the %s handler:\n%s", handler, b)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	c := testConfig(t, false)
+	c.Files = serviceFiles(c, false, false)
+	c.Comments = protoComments(c.Dir, c.Name)
+
+	buf := bytes.NewBuffer(nil)
+	if err := summarize(c, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var s map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &s); err != nil {
+		t.Fatalf("Expected a json summary: %v\n%s", err, buf)
+	}
+
+	expected := map[string]string{"name": "foo", "fqdn": "go.vine.service.foo", "type": "service", "dir": c.GoDir}
+	for k, v := range expected {
+		if s[k] != v {
+			t.Fatalf("Expected %s %q, got %v", k, v, s[k])
+		}
+	}
+
+	files, _ := s["files"].([]interface{})
+	if len(files) != len(c.Files) || files[0] != "cmd/main.go" {
+		t.Fatalf("Unexpected files %v", s["files"])
+	}
+	commands, _ := s["commands"].([]interface{})
+	if len(commands) == 0 || commands[len(commands)-1] != "vine build foo" {
+		t.Fatalf("Unexpected commands %v", s["commands"])
+	}
+	if _, ok := s["dry_run"]; ok {
+		t.Fatalf("Expected no dry_run without a dry run, got %v", s["dry_run"])
+	}
+
+	c.DryRun = true
+	buf.Reset()
+	if err := summarize(c, buf); err != nil {
+		t.Fatal(err)
+	}
+	s = nil
+	if err := json.Unmarshal(buf.Bytes(), &s); err != nil {
+		t.Fatalf("Expected a json summary: %v\n%s", err, buf)
+	}
+	if s["dry_run"] != true {
+		t.Fatalf("Expected dry_run true, got %v", s["dry_run"])
+	}
+
+	c.Output = "yaml"
+	if err := create(c); err == nil {
+		t.Fatal("Expected an unsupported output to fail")
+	}
+}
+
+func TestServiceDryRun(t *testing.T) {
+	dir := t.TempDir()
+	c, err := initConfig(dir, "/go", "go.vine", "example.com/foo", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := create(c); err != nil {
+		t.Fatal(err)
+	}
+	toml, err := ioutil.ReadFile(filepath.Join(dir, "vine.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	app := &cli.App{Name: "vine", Commands: Commands()}
+	if err := app.Run([]string{"vine", "new", "--dry-run", "service"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"cmd", "pkg", "proto", "deploy", "Makefile"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Fatalf("Expected %s not to be written by a dry run", name)
+		}
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "vine.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != string(toml) {
+		t.Fatalf("Expected vine.toml to be unchanged by a dry run:\n%s", b)
+	}
+}
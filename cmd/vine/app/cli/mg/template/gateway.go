@@ -0,0 +1,280 @@
+package template
+
+var (
+	GatewaySRVApp = `package {{.Name}}
+
+import (
+	"mime"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+	"github.com/lack-io/cli"
+
+	"github.com/lack-io/vine"
+	ahandler "github.com/lack-io/vine/lib/api/handler"
+	"github.com/lack-io/vine/lib/api/handler/openapi"
+	arpc "github.com/lack-io/vine/lib/api/handler/rpc"
+	"github.com/lack-io/vine/lib/api/resolver"
+	"github.com/lack-io/vine/lib/api/resolver/grpc"
+	"github.com/lack-io/vine/lib/api/router"
+	regRouter "github.com/lack-io/vine/lib/api/router/registry"
+	"github.com/lack-io/vine/lib/api/server"
+	httpapi "github.com/lack-io/vine/lib/api/server/http"
+	log "github.com/lack-io/vine/lib/logger"
+	"github.com/lack-io/vine/util/helper"
+	"github.com/lack-io/vine/util/namespace"
+	"github.com/rakyll/statik/fs"
+
+	"{{.Dir}}/pkg/runtime"
+	srv "{{.Dir}}/pkg/{{.Name}}/server"
+
+	_ "github.com/lack-io/vine/lib/api/handler/openapi/statik"
+)
+
+var (
+	Address       = ":8080"
+	Handler       = "rpc"
+	Type          = "service"
+	APIPath       = "/"
+	enableOpenAPI = false
+
+	flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "api-address",
+			Usage:       "The specify for api address",
+			EnvVars:     []string{"VINE_API_ADDRESS"},
+			Required:    true,
+			Value:       Address,
+			Destination: &Address,
+		},
+		&cli.BoolFlag{
+			Name:    "enable-openapi",
+			Usage:   "Enable OpenAPI3",
+			EnvVars: []string{"VINE_ENABLE_OPENAPI"},
+			Value:   true,
+		},
+		&cli.BoolFlag{
+			Name:    "enable-cors",
+			Usage:   "Enable CORS, allowing the API to be called by frontend applications",
+			EnvVars: []string{"VINE_API_ENABLE_CORS"},
+			Value:   true,
+		},
+	}
+)
+
+func Run() {
+	// Init API
+	var opts []server.Option
+
+	// initialise the service, the handler is served over grpc and
+	// through the api router below
+	s := srv.New()
+	err := s.Init(
+		vine.Metadata(map[string]string{
+			"api-address": Address,
+			"namespace":   runtime.Namespace,
+		}),
+		vine.Flags(flags...),
+		vine.Action(func(ctx *cli.Context) error {
+			enableOpenAPI = ctx.Bool("enable-openapi")
+
+			if ctx.Bool("enable-tls") {
+				config, err := helper.TLSConfig(ctx)
+				if err != nil {
+					log.Errorf(err.Error())
+					return err
+				}
+
+				opts = append(opts, server.EnableTLS(true))
+				opts = append(opts, server.TLSConfig(config))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts = append(opts, server.EnableCORS(true))
+
+	// create the router
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+
+	if enableOpenAPI {
+		openAPI := openapi.New(s)
+		_ = mime.AddExtensionType(".svg", "image/svg+xml")
+		sfs, err := fs.New()
+		if err != nil {
+			log.Fatalf("Starting OpenAPI: %v", err)
+		}
+		prefix := "/openapi-ui/"
+		app.All(prefix, openAPI.OpenAPIHandler)
+		app.Use(prefix, filesystem.New(filesystem.Config{Root: sfs}))
+		app.Get("/openapi.json", openAPI.OpenAPIJOSNHandler)
+		app.Get("/services", openAPI.OpenAPIServiceHandler)
+		log.Infof("Starting OpenAPI at %v", prefix)
+	}
+
+	// create the namespace resolver
+	nsResolver := namespace.NewResolver(Type, runtime.Namespace)
+	// resolver options
+	ropts := []resolver.Option{
+		resolver.WithNamespace(nsResolver.ResolveWithType),
+		resolver.WithHandler(Handler),
+	}
+
+	log.Infof("Registering API RPC Handler at %s", APIPath)
+	rr := grpc.NewResolver(ropts...)
+	rt := regRouter.NewRouter(
+		router.WithHandler(arpc.Handler),
+		router.WithResolver(rr),
+		router.WithRegistry(s.Options().Registry),
+	)
+	rp := arpc.NewHandler(
+		ahandler.WithNamespace(runtime.Namespace),
+		ahandler.WithRouter(rt),
+		ahandler.WithClient(s.Client()),
+	)
+	app.Group(APIPath, rp.Handle)
+
+	api := httpapi.NewServer(Address)
+
+	if err := api.Init(opts...); err != nil {
+		log.Fatal(err)
+	}
+	api.Handle("/", app)
+
+	// Start API
+	if err := api.Start(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Run server
+	if err := s.Run(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Stop API
+	if err := api.Stop(); err != nil {
+		log.Fatal(err)
+	}
+}
+`
+
+	GatewaySRV = `package server
+
+import (
+	"context"
+
+	"github.com/lack-io/vine"
+	log "github.com/lack-io/vine/lib/logger"
+
+	"{{.Dir}}/pkg/runtime"
+	"{{.Dir}}/pkg/{{.Name}}/service"
+	"{{.Dir}}/pkg/runtime/inject"
+	pb "{{.Dir}}/proto/service/{{.Group}}/{{.Version}}"
+)
+
+type server struct{
+	vine.Service
+
+	H service.{{title .Name}} ` + "`inject:\"\"`" + `
+}
+
+// Call is a single request handler called via client.Call, the generated client code
+// or POST /{{.Group}}/{{.Version}}/{{.Name}}/Call through the gateway
+func (s *server) Call(ctx context.Context, req *pb.Request, rsp *pb.Response) error {
+	// TODO: Validate
+	s.H.Call()
+	// FIXME: fix call method
+	log.Info("Received {{title .Name}}.Call request")
+	rsp.Msg = "Hello " + req.Name
+	return nil
+}
+
+// Get is a single request handler called via client.Call, the generated client code
+// or GET /{{.Group}}/{{.Version}}/{{.Name}}/{name} through the gateway
+func (s *server) Get(ctx context.Context, req *pb.GetRequest, rsp *pb.GetResponse) error {
+	log.Infof("Received {{title .Name}}.Get request for %s", req.Name)
+	rsp.Msg = "Hello " + req.Name
+	return nil
+}
+
+// Init initialises the service, the options are applied after the defaults
+func (s *server) Init(opts ...vine.Option) error {
+	var err error
+
+	opts = append([]vine.Option{
+		vine.Name(runtime.{{title .Name}}Name),
+		vine.Id(runtime.{{title .Name}}Id),
+		vine.Version(runtime.GetVersion()),
+		vine.Metadata(map[string]string{
+			"namespace": runtime.Namespace,
+		}),
+	}, opts...)
+
+	s.Service.Init(opts...)
+
+	if err = inject.Provide(s.Service, s.Client(), s); err != nil {
+		return err
+	}
+
+	// TODO: inject more objects
+
+	if err = inject.Populate(); err != nil {
+		return err
+	}
+
+	if err = s.H.Init(); err != nil {
+		return err
+	}
+
+	if err = pb.Register{{title .Name}}ServiceHandler(s.Service.Server(), s); err != nil {
+		return err
+	}
+
+	return err
+}
+
+func New() *server {
+	srv := vine.NewService()
+	return &server{
+		Service: srv,
+	}
+}
+`
+
+	ProtoGateway = `syntax = "proto3";
+
+package {{.Group}}{{.Version}};
+
+option go_package = "{{.Dir}}/proto/service/{{.Group}}/{{.Version}};{{.Group}}{{.Version}}";
+
+// +gen:openapi
+service {{title .Name}}Service {
+	// +gen:post=/{{.Group}}/{{.Version}}/{{.Name}}/Call
+	// +gen:body=*
+	rpc Call(Request) returns (Response) {}
+	// +gen:get=/{{.Group}}/{{.Version}}/{{.Name}}/{name}
+	rpc Get(GetRequest) returns (GetResponse) {}
+}
+
+message Request {
+    // +gen:required
+	string name = 1;
+}
+
+message Response {
+	string msg = 1;
+}
+
+message GetRequest {
+    // +gen:required
+	string name = 1;
+}
+
+message GetResponse {
+	string msg = 1;
+}
+`
+)
@@ -511,6 +511,180 @@ func (s *server) Init() error {
 	return err
 }
 
+func New() *server {
+	srv := vine.NewService()
+	return &server{
+		Service: srv,
+	}
+}
+`
+
+	SingleSRVNoProto = `package server
+
+import (
+	"context"
+
+	"github.com/lack-io/vine"
+	log "github.com/lack-io/vine/lib/logger"
+
+	"{{.Dir}}/pkg/runtime"
+	"{{.Dir}}/pkg/runtime/inject"
+	"{{.Dir}}/pkg/service"
+)
+
+type server struct{
+	vine.Service
+
+	H service.{{title .Name}} ` + "`inject:\"\"`" + `
+}
+
+// Request is the request of {{title .Name}}.Call
+type Request struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+// Response is the response of {{title .Name}}.Call
+type Response struct {
+	Msg string ` + "`json:\"msg\"`" + `
+}
+
+// {{title .Name}} is the handler of the service, the requests are encoded as json
+type {{title .Name}} struct {
+	H service.{{title .Name}}
+}
+
+// Call is a single request handler called via client.Call with the application/json content type
+func (h *{{title .Name}}) Call(ctx context.Context, req *Request, rsp *Response) error {
+	// TODO: Validate
+	h.H.Call()
+	// FIXME: fix call method
+	log.Info("Received {{title .Name}}.Call request")
+	rsp.Msg = "Hello " + req.Name
+	return nil
+}
+
+func (s *server) Init() error {
+	var err error
+
+	opts := []vine.Option{
+		vine.Name(runtime.{{title .Name}}Name),
+		vine.Id(runtime.{{title .Name}}Id),
+		vine.Version(runtime.GetVersion()),
+		vine.Metadata(map[string]string{
+			"namespace": runtime.Namespace,
+		}),
+	}
+
+	s.Service.Init(opts...)
+
+	if err = inject.Provide(s.Service, s.Client(), s); err != nil {
+		return err
+	}
+
+	// TODO: inject more objects
+
+	if err = inject.Populate(); err != nil {
+		return err
+	}
+
+	if err = s.H.Init(); err != nil {
+		return err
+	}
+
+	if err = s.Service.Server().Handle(s.Service.Server().NewHandler(&{{title .Name}}{H: s.H})); err != nil {
+		return err
+	}
+
+	return err
+}
+
+func New() *server {
+	srv := vine.NewService()
+	return &server{
+		Service: srv,
+	}
+}
+`
+
+	ClusterSRVNoProto = `package server
+
+import (
+	"context"
+
+	"github.com/lack-io/vine"
+	log "github.com/lack-io/vine/lib/logger"
+
+	"{{.Dir}}/pkg/runtime"
+	"{{.Dir}}/pkg/{{.Name}}/service"
+	"{{.Dir}}/pkg/runtime/inject"
+)
+
+type server struct{
+	vine.Service
+
+	H service.{{title .Name}} ` + "`inject:\"\"`" + `
+}
+
+// Request is the request of {{title .Name}}.Call
+type Request struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+// Response is the response of {{title .Name}}.Call
+type Response struct {
+	Msg string ` + "`json:\"msg\"`" + `
+}
+
+// {{title .Name}} is the handler of the service, the requests are encoded as json
+type {{title .Name}} struct {
+	H service.{{title .Name}}
+}
+
+// Call is a single request handler called via client.Call with the application/json content type
+func (h *{{title .Name}}) Call(ctx context.Context, req *Request, rsp *Response) error {
+	// TODO: Validate
+	h.H.Call()
+	// FIXME: fix call method
+	log.Info("Received {{title .Name}}.Call request")
+	rsp.Msg = "Hello " + req.Name
+	return nil
+}
+
+func (s *server) Init() error {
+	var err error
+
+	opts := []vine.Option{
+		vine.Name(runtime.{{title .Name}}Name),
+		vine.Id(runtime.{{title .Name}}Id),
+		vine.Version(runtime.GetVersion()),
+		vine.Metadata(map[string]string{
+			"namespace": runtime.Namespace,
+		}),
+	}
+
+	s.Service.Init(opts...)
+
+	if err = inject.Provide(s.Service, s.Client(), s); err != nil {
+		return err
+	}
+
+	// TODO: inject more objects
+
+	if err = inject.Populate(); err != nil {
+		return err
+	}
+
+	if err = s.H.Init(); err != nil {
+		return err
+	}
+
+	if err = s.Service.Server().Handle(s.Service.Server().NewHandler(&{{title .Name}}{H: s.H})); err != nil {
+		return err
+	}
+
+	return err
+}
+
 func New() *server {
 	srv := vine.NewService()
 	return &server{
@@ -0,0 +1,107 @@
+package template
+
+var (
+	TestSRV = `package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lack-io/vine"
+	"github.com/lack-io/vine/core/registry/memory"
+
+	pb "{{.Dir}}/proto/service/{{.Group}}/{{.Version}}"
+)
+
+// stub is a stand-in for the service.{{title .Name}} logic, replace it by a mock as the service grows
+type stub struct{}
+
+func (stub) Init() error { return nil }
+func (stub) Call()       {}
+func (stub) Stream()     {}
+func (stub) PingPong()   {}
+
+func newTestServer(t *testing.T) *server {
+	svc := vine.NewService(
+		vine.Name("{{.Alias}}"),
+		vine.Address("127.0.0.1:0"),
+		vine.Registry(memory.NewRegistry()),
+	)
+	s := &server{Service: svc, H: stub{}}
+
+	if err := pb.Register{{title .Name}}ServiceHandler(svc.Server(), s); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Server().Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = svc.Server().Stop() })
+
+	return s
+}
+
+func Test{{title .Name}}Call(t *testing.T) {
+	s := newTestServer(t)
+
+	cc := pb.New{{title .Name}}Service("{{.Alias}}", s.Client())
+	rsp, err := cc.Call(context.TODO(), &pb.Request{Name: "John"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Msg != "Hello John" {
+		t.Fatalf("expected 'Hello John', got '%s'", rsp.Msg)
+	}
+}
+`
+
+	TestSRVNoProto = `package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lack-io/vine"
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/core/registry/memory"
+)
+
+// stub is a stand-in for the service.{{title .Name}} logic, replace it by a mock as the service grows
+type stub struct{}
+
+func (stub) Init() error { return nil }
+func (stub) Call()       {}
+func (stub) Stream()     {}
+func (stub) PingPong()   {}
+
+func newTestService(t *testing.T) vine.Service {
+	svc := vine.NewService(
+		vine.Name("{{.Alias}}"),
+		vine.Address("127.0.0.1:0"),
+		vine.Registry(memory.NewRegistry()),
+	)
+
+	if err := svc.Server().Handle(svc.Server().NewHandler(&{{title .Name}}{H: stub{}})); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Server().Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = svc.Server().Stop() })
+
+	return svc
+}
+
+func Test{{title .Name}}Call(t *testing.T) {
+	svc := newTestService(t)
+
+	req := svc.Client().NewRequest("{{.Alias}}", "{{title .Name}}.Call", &Request{Name: "John"}, client.WithContentType("application/json"))
+	rsp := &Response{}
+	if err := svc.Client().Call(context.TODO(), req, rsp); err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Msg != "Hello John" {
+		t.Fatalf("expected 'Hello John', got '%s'", rsp.Msg)
+	}
+}
+`
+)
@@ -25,7 +25,8 @@ package template
 var (
 	TOML = `[package]
 kind = "{{.Toml.Package.Kind}}"
-namespace = "{{.Toml.Package.Namespace}}"
+namespace = "{{.Toml.Package.Namespace}}"{{if .Toml.Package.Module}}
+module = "{{.Toml.Package.Module}}"{{end}}
 {{if .Toml.Mod}}{{range .Toml.Mod}}
 [[mod]]
 name = "{{.Name}}"
@@ -68,6 +68,11 @@ func runWeb(ctx *cli.Context) {
 		name = filepath.Base(dir)
 	}
 
+	if err := checkName(name); err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	alias := strings.Join([]string{namespace, atype, name}, ".")
 
 	// set the command
@@ -101,12 +106,7 @@ func runWeb(ctx *cli.Context) {
 	}
 
 	goDir := dir
-	dir = strings.TrimPrefix(dir, goPath+"/src/")
-	if runtime.GOOS == "windows" {
-		dir = strings.TrimPrefix(dir, goPath+"\\src\\")
-	} else {
-		dir = strings.TrimPrefix(dir, goPath+"/src/")
-	}
+	dir = importPath(cfg, dir, goPath)
 	c := config{
 		Name:      name,
 		Command:   command,
@@ -120,6 +120,8 @@ func runWeb(ctx *cli.Context) {
 		Plugins:   plugins,
 		Comments:  protoComments(dir, name),
 		Toml:      cfg,
+		Output:    ctx.String("output"),
+		DryRun:    ctx.Bool("dry-run"),
 	}
 
 	c.GoVersion = version.GoV()
@@ -38,6 +38,8 @@ type Config struct {
 type Package struct {
 	Kind      string `json:"kind" toml:"kind"`
 	Namespace string `json:"namespace" toml:"namespace"`
+	// Module is the go module path of the project
+	Module string `json:"module,omitempty" toml:"module,omitempty"`
 }
 
 type Mods []Mod
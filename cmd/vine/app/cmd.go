@@ -35,8 +35,13 @@ import (
 	"github.com/lack-io/vine"
 	"github.com/lack-io/vine/cmd/vine/app/api"
 	cliBuild "github.com/lack-io/vine/cmd/vine/app/cli/build"
+	cliGen "github.com/lack-io/vine/cmd/vine/app/cli/gen"
+	cliGraph "github.com/lack-io/vine/cmd/vine/app/cli/graph"
 	cliMg "github.com/lack-io/vine/cmd/vine/app/cli/mg"
 	cliRun "github.com/lack-io/vine/cmd/vine/app/cli/run"
+	"github.com/lack-io/vine/cmd/vine/app/federation"
+	"github.com/lack-io/vine/cmd/vine/app/registry"
+	"github.com/lack-io/vine/cmd/vine/app/router"
 	"github.com/lack-io/vine/lib/cmd"
 	"github.com/lack-io/vine/util/helper"
 )
@@ -312,6 +317,11 @@ func Setup(app *ccli.App, options ...vine.Option) {
 	app.Commands = append(app.Commands, cliMg.Commands()...)
 	app.Commands = append(app.Commands, cliRun.Commands()...)
 	app.Commands = append(app.Commands, cliBuild.Commands()...)
+	app.Commands = append(app.Commands, cliGen.Commands()...)
+	app.Commands = append(app.Commands, cliGraph.Commands()...)
+	app.Commands = append(app.Commands, federation.Commands()...)
+	app.Commands = append(app.Commands, router.Commands()...)
+	app.Commands = append(app.Commands, registry.Commands()...)
 	//app.Commands = append(app.Commands, auth.Commands()...)
 	//app.Commands = append(app.Commands, bot.Commands()...)
 	//app.Commands = append(app.Commands, cli.Commands()...)
@@ -0,0 +1,223 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package federation mirrors services of the local registry into the registries of other regions
+package federation
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lack-io/cli"
+
+	"github.com/lack-io/vine/core/registry"
+	"github.com/lack-io/vine/core/registry/federation"
+	"github.com/lack-io/vine/core/registry/grpc"
+	"github.com/lack-io/vine/lib/cmd"
+	log "github.com/lack-io/vine/lib/logger"
+	signalutil "github.com/lack-io/vine/util/signal"
+)
+
+func pair(s string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", fmt.Errorf("invalid value '%s', expected key=value", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+func run(c *cli.Context) error {
+	region := c.String("region")
+	if len(region) == 0 {
+		return fmt.Errorf("specify the region with --region")
+	}
+
+	ttl := c.Duration("ttl")
+	if ttl <= 0 {
+		return fmt.Errorf("invalid --ttl %v, expected a positive duration", ttl)
+	}
+
+	opts := []federation.Option{
+		federation.Region(region),
+		federation.Prefix(c.StringSlice("prefix")...),
+		federation.TTL(ttl),
+		federation.GracePeriod(c.Duration("grace-period")),
+	}
+
+	for _, r := range c.StringSlice("remote") {
+		name, address, err := pair(r)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, federation.WithRemote(name, grpc.NewRegistry(registry.Addrs(address))))
+	}
+	if len(c.StringSlice("remote")) == 0 {
+		return fmt.Errorf("specify at least a remote registry with --remote")
+	}
+
+	for _, l := range c.StringSlice("label") {
+		k, v, err := pair(l)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, federation.Label(k, v))
+	}
+
+	f, err := federation.NewFederation(*cmd.DefaultOptions().Registry, opts...)
+	if err != nil {
+		return err
+	}
+	if err := f.Start(); err != nil {
+		return err
+	}
+
+	// log the status of the remotes
+	go func() {
+		t := time.NewTicker(ttl)
+		defer t.Stop()
+		for range t.C {
+			for _, st := range f.Status() {
+				log.Infof("Federation to %s: healthy=%v services=%d syncs=%d failures=%d",
+					st.Remote, st.Healthy, st.Services, st.Syncs, st.Failures)
+			}
+		}
+	}()
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signalutil.Shutdown()...)
+	<-ch
+
+	return f.Stop()
+}
+
+// status prints the services federated into the registry with their region
+func status(c *cli.Context) error {
+	reg := *cmd.DefaultOptions().Registry
+
+	names := c.Args().Slice()
+	if len(names) == 0 {
+		list, err := reg.ListServices()
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool)
+		for _, s := range list {
+			if !seen[s.Name] {
+				seen[s.Name] = true
+				names = append(names, s.Name)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tVERSION\tNODE\tADDRESS\tFEDERATED FROM")
+
+	for _, name := range names {
+		services, err := reg.GetService(name)
+		if err == registry.ErrNotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+		for _, s := range services {
+			for _, n := range s.Nodes {
+				region, ok := n.Metadata[federation.FederatedFrom]
+				if !ok {
+					if !c.Bool("all") {
+						continue
+					}
+					region = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Name, s.Version, n.Id, n.Address, region)
+			}
+		}
+	}
+
+	return w.Flush()
+}
+
+func Commands() []*cli.Command {
+	command := &cli.Command{
+		Name:  "federation",
+		Usage: "Mirror services of the local registry into the registries of other regions",
+		Action: func(c *cli.Context) error {
+			return run(c)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "region",
+				Usage:   "Set the region of the local registry, set as the federated-from metadata of the mirrored nodes",
+				EnvVars: []string{"VINE_FEDERATION_REGION"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "remote",
+				Usage:   "Add a remote registry service e.g us=10.0.0.1:8000",
+				EnvVars: []string{"VINE_FEDERATION_REMOTE"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "prefix",
+				Usage:   "Federate the services with the name prefix e.g go.vine.api",
+				EnvVars: []string{"VINE_FEDERATION_PREFIX"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "label",
+				Usage:   "Federate the services with the metadata e.g global=true",
+				EnvVars: []string{"VINE_FEDERATION_LABEL"},
+			},
+			&cli.DurationFlag{
+				Name:    "ttl",
+				Usage:   "Set the ttl of the mirrored services",
+				EnvVars: []string{"VINE_FEDERATION_TTL"},
+				Value:   federation.DefaultTTL,
+			},
+			&cli.DurationFlag{
+				Name:    "grace-period",
+				Usage:   "Set how long the mirrored services are kept when the local registry is unreachable",
+				EnvVars: []string{"VINE_FEDERATION_GRACE_PERIOD"},
+				Value:   federation.DefaultGracePeriod,
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:  "status",
+				Usage: "List the federated services of the registry and the region they come from, e.g vine federation status go.vine.api.foo",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "List the local nodes too",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return status(c)
+				},
+			},
+		},
+	}
+
+	return []*cli.Command{command}
+}
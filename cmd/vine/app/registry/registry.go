@@ -0,0 +1,163 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package registry diagnoses the registry
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lack-io/cli"
+
+	"github.com/lack-io/vine/core/registry"
+	"github.com/lack-io/vine/lib/cmd"
+)
+
+// Fetch is the timing of fetching the full details of a service
+type Fetch struct {
+	Service  string        `json:"service"`
+	Versions int           `json:"versions"`
+	Nodes    int           `json:"nodes"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Report is the timing of warming the registry
+type Report struct {
+	// List is the time to list the services
+	List time.Duration `json:"list"`
+	// Fetches are sorted from the slowest service
+	Fetches []*Fetch      `json:"fetches"`
+	Total   time.Duration `json:"total"`
+}
+
+// Warm lists all the services and fetches the full details of each of them,
+// the way the registry router does, timing every call
+func Warm(r registry.Registry) (*Report, error) {
+	start := time.Now()
+
+	services, err := r.ListServices()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{List: time.Since(start)}
+
+	seen := make(map[string]bool)
+	for _, service := range services {
+		if seen[service.Name] {
+			continue
+		}
+		seen[service.Name] = true
+
+		fetch := &Fetch{Service: service.Name}
+		t := time.Now()
+		svcs, err := r.GetService(service.Name)
+		fetch.Duration = time.Since(t)
+		if err != nil {
+			fetch.Error = err.Error()
+		}
+		for _, svc := range svcs {
+			fetch.Versions++
+			fetch.Nodes += len(svc.Nodes)
+		}
+
+		report.Fetches = append(report.Fetches, fetch)
+	}
+
+	report.Total = time.Since(start)
+
+	sort.SliceStable(report.Fetches, func(i, j int) bool {
+		return report.Fetches[i].Duration > report.Fetches[j].Duration
+	})
+
+	return report, nil
+}
+
+// Print writes the report in the output format, table or json
+func (r *Report) Print(w io.Writer, output string) error {
+	switch output {
+	case "json":
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(b))
+		return nil
+	case "table":
+	default:
+		return fmt.Errorf("unknown output %s", output)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 1, ' ', 0)
+	fmt.Fprintln(tw, "SERVICE\tVERSIONS\tNODES\tTIME\tERROR")
+	for _, f := range r.Fetches {
+		errStr := "-"
+		if len(f.Error) > 0 {
+			errStr = f.Error
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n", f.Service, f.Versions, f.Nodes, f.Duration, errStr)
+	}
+	fmt.Fprintf(tw, "\nList:\t%s\n", r.List)
+	fmt.Fprintf(tw, "Total:\t%s\t(%d services)\n", r.Total, len(r.Fetches))
+	return tw.Flush()
+}
+
+func warm(c *cli.Context) error {
+	report, err := Warm(*cmd.DefaultOptions().Registry)
+	if err != nil {
+		return fmt.Errorf("warm registry: %v", err)
+	}
+	return report.Print(os.Stdout, c.String("output"))
+}
+
+func Commands() []*cli.Command {
+	command := &cli.Command{
+		Name:  "registry",
+		Usage: "Diagnose the registry",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "warm",
+				Usage: "Fetch every service of the registry and report the timing, e.g vine --registry etcd registry warm",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output format: table or json",
+						Value:   "table",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return warm(c)
+				},
+			},
+		},
+	}
+
+	return []*cli.Command{command}
+}
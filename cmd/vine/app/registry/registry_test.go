@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/core/registry"
+	"github.com/lack-io/vine/core/registry/memory"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+// slowRegistry delays the fetch of some services
+type slowRegistry struct {
+	registry.Registry
+	delay map[string]time.Duration
+}
+
+func (r *slowRegistry) GetService(name string, opts ...registry.GetOption) ([]*regpb.Service, error) {
+	time.Sleep(r.delay[name])
+	return r.Registry.GetService(name, opts...)
+}
+
+func TestWarm(t *testing.T) {
+	m := memory.NewRegistry()
+	for _, name := range []string{"foo", "bar", "baz"} {
+		for _, version := range []string{"1.0.0", "1.0.1"} {
+			if err := m.Register(&regpb.Service{
+				Name:    name,
+				Version: version,
+				Nodes:   []*regpb.Node{{Id: name + "-" + version, Address: "127.0.0.1:9090"}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	r := &slowRegistry{Registry: m, delay: map[string]time.Duration{"bar": time.Millisecond * 50}}
+
+	report, err := Warm(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Fetches) != 3 {
+		t.Fatalf("Expected 3 services fetched, got %d", len(report.Fetches))
+	}
+
+	slowest := report.Fetches[0]
+	if slowest.Service != "bar" || slowest.Duration < time.Millisecond*50 {
+		t.Fatalf("Expected bar to be the slowest service, got %s in %s", slowest.Service, slowest.Duration)
+	}
+
+	for _, f := range report.Fetches {
+		if f.Versions != 2 || f.Nodes != 2 {
+			t.Fatalf("Expected 2 versions and 2 nodes for %s, got %d and %d", f.Service, f.Versions, f.Nodes)
+		}
+		if f.Duration > report.Total {
+			t.Fatalf("Expected %s fetch time %s within total %s", f.Service, f.Duration, report.Total)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.Print(&buf, "table"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, v := range []string{"bar", "foo", "baz", "Total:", slowest.Duration.String()} {
+		if !strings.Contains(out, v) {
+			t.Fatalf("Expected output to contain %q, got:\n%s", v, out)
+		}
+	}
+
+	if err := report.Print(&buf, "yaml"); err == nil {
+		t.Fatal("Expected an error for an unknown output")
+	}
+}
@@ -0,0 +1,195 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package router inspects the routing table of a router instance
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lack-io/cli"
+
+	"github.com/lack-io/vine/core/client"
+	rr "github.com/lack-io/vine/core/router"
+	"github.com/lack-io/vine/lib/cmd"
+	pb "github.com/lack-io/vine/proto/services/router"
+)
+
+// callOptions returns the options to call the router instance at the address
+func callOptions(c *cli.Context) []client.CallOption {
+	if address := c.String("address"); len(address) > 0 {
+		return []client.CallOption{client.WithAddress(address)}
+	}
+	return nil
+}
+
+func routes(c *cli.Context) error {
+	r := pb.NewRouterService(c.String("name"), *cmd.DefaultOptions().Client)
+
+	stream, err := r.Table(context.Background(), &pb.TableRequest{
+		Query: &pb.Query{Service: c.String("service")},
+	}, callOptions(c)...)
+	if err != nil {
+		return fmt.Errorf("list routes: %v", err)
+	}
+	defer stream.Close()
+
+	output := c.String("output")
+
+	var w *tabwriter.Writer
+	switch output {
+	case "json":
+	case "table":
+		w = tabwriter.NewWriter(os.Stdout, 0, 8, 1, ' ', 0)
+		fmt.Fprintln(w, "SERVICE\tADDRESS\tGATEWAY\tNETWORK\tMETRIC\tLINK\tAGE")
+	default:
+		return fmt.Errorf("unknown output %s", output)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("list routes: %v", err)
+		}
+
+		route := entry.Route
+		if route == nil {
+			continue
+		}
+
+		// rows are written as they are received
+		if w == nil {
+			if err := enc.Encode(entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		age := "-"
+		if entry.Age > 0 {
+			age = time.Duration(entry.Age).Round(time.Second).String()
+		}
+		gateway := route.Gateway
+		if len(gateway) == 0 {
+			gateway = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", route.Service, route.Address, gateway, route.Network, route.Metric, route.Link, age)
+	}
+
+	if w != nil {
+		return w.Flush()
+	}
+	return nil
+}
+
+func stats(c *cli.Context) error {
+	r := pb.NewRouterService(c.String("name"), *cmd.DefaultOptions().Client)
+
+	rsp, err := r.Stats(context.Background(), &pb.StatsRequest{}, callOptions(c)...)
+	if err != nil {
+		return fmt.Errorf("get stats: %v", err)
+	}
+
+	switch output := c.String("output"); output {
+	case "json":
+		b, err := json.MarshalIndent(rsp, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	case "table":
+	default:
+		return fmt.Errorf("unknown output %s", output)
+	}
+
+	lastSync := "never"
+	if rsp.LastSync > 0 {
+		lastSync = time.Unix(0, rsp.LastSync).Format(time.RFC3339)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, ' ', 0)
+	fmt.Fprintf(w, "Routes:\t%d\n", rsp.Routes)
+	fmt.Fprintf(w, "Events processed:\t%d\n", rsp.Events)
+	fmt.Fprintf(w, "Adverts sent:\t%d\n", rsp.AdvertsSent)
+	fmt.Fprintf(w, "Adverts received:\t%d\n", rsp.AdvertsReceived)
+	fmt.Fprintf(w, "Last registry sync:\t%s\n", lastSync)
+	return w.Flush()
+}
+
+func Commands() []*cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "Address of the router instance to inspect e.g 10.0.0.1:9093, defaults to any instance of the router service",
+		},
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "Name of the router service",
+			Value: rr.DefaultName,
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Output format: table or json",
+			Value:   "table",
+		},
+	}
+
+	command := &cli.Command{
+		Name:  "router",
+		Usage: "Inspect the routing table of a router",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "routes",
+				Usage: "List the routes of the routing table, e.g vine router routes --address 10.0.0.1:9093",
+				Flags: append(flags, &cli.StringFlag{
+					Name:  "service",
+					Usage: "List the routes of the service only",
+				}),
+				Action: func(c *cli.Context) error {
+					return routes(c)
+				},
+			},
+			{
+				Name:  "stats",
+				Usage: "Show the router statistics, e.g vine router stats --address 10.0.0.1:9093",
+				Flags: flags,
+				Action: func(c *cli.Context) error {
+					return stats(c)
+				},
+			},
+		},
+	}
+
+	return []*cli.Command{command}
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/lack-io/vine/cmd/vine/client/resolver/web"
 	"github.com/lack-io/vine/core/client/selector"
 	"github.com/lack-io/vine/core/registry"
+	cserver "github.com/lack-io/vine/core/server"
 	"github.com/lack-io/vine/lib/api/server"
 	"github.com/lack-io/vine/lib/api/server/cors"
 	httpapi "github.com/lack-io/vine/lib/api/server/http"
@@ -47,6 +48,7 @@ import (
 	"github.com/lack-io/vine/util/helper"
 	"github.com/lack-io/vine/util/namespace"
 	"github.com/lack-io/vine/util/stats"
+	"github.com/lack-io/vine/util/wrapper"
 	"github.com/serenize/snaker"
 	"golang.org/x/net/publicsuffix"
 )
@@ -310,103 +312,90 @@ func (s *service) indexHandler(c *fiber.Ctx) error {
 	return s.render(c, indexTemplate, data)
 }
 
+// wantsJSON negotiates the response format with the Accept header, either
+// application/json or text/html. The format query, json or html, overrides it.
+func wantsJSON(c *fiber.Ctx) bool {
+	switch c.Query("format") {
+	case "json":
+		return true
+	case "html":
+		return false
+	}
+	return c.Accepts(fiber.MIMETextHTML, fiber.MIMEApplicationJSON) == fiber.MIMEApplicationJSON
+}
+
+// registryHandler lists the services on /services, as {"services": [...]}
+// sorted by name, and shows the versions of a service on /service/:name, as
+// {"services": [...]} with the endpoints, nodes and metadata of each version
 func (s *service) registryHandler(c *fiber.Ctx) error {
-	//vars := mux.Vars(c)
-	//svc := vars["name"]
-	//
-	//if len(svc) > 0 {
-	//	sv, err := s.registry.GetService(svc, registry.GetContext(r.Context()))
-	//	if err != nil {
-	//		http.Error(w, "Error occurred:"+err.Error(), 500)
-	//		return
-	//	}
-	//
-	//	if len(sv) == 0 {
-	//		http.Error(w, "Not found", 404)
-	//		return
-	//	}
-	//
-	//	if r.Header.Get("Content-Type") == "application/json" {
-	//		b, err := json.Marshal(map[string]interface{}{
-	//			"services": s,
-	//		})
-	//		if err != nil {
-	//			http.Error(w, "Error occurred:"+err.Error(), 500)
-	//			return
-	//		}
-	//		w.Header().Set("Content-Type", "application/json")
-	//		w.Write(b)
-	//		return
-	//	}
-	//
-	//	s.render(c, serviceTemplate, sv)
-	//	return
-	//}
-	//
-	//services, err := s.registry.ListServices(registry.ListContext(r.Context()))
-	//if err != nil {
-	//	log.Errorf("Error listing services: %v", err)
-	//}
-	//
-	//sort.Sort(sortedServices{services})
-	//
-	//if r.Header.Get("Content-Type") == "application/json" {
-	//	b, err := json.Marshal(map[string]interface{}{
-	//		"services": services,
-	//	})
-	//	if err != nil {
-	//		http.Error(w, "Error occurred:"+err.Error(), 500)
-	//		return
-	//	}
-	//	w.Header().Set("Content-Type", "application/json")
-	//	w.Write(b)
-	//	return
-	//}
+	if name := c.Params("name"); len(name) > 0 {
+		sv, err := s.registry.GetService(name, registry.GetContext(c.Context()))
+		if err == registry.ErrNotFound || (err == nil && len(sv) == 0) {
+			return fiber.NewError(fiber.StatusNotFound, "Not found")
+		}
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Error occurred:"+err.Error())
+		}
 
-	//return s.render(c, registryTemplate, services)
-	return nil
+		if wantsJSON(c) {
+			return c.JSON(map[string]interface{}{
+				"services": sv,
+			})
+		}
+
+		return s.render(c, serviceTemplate, sv)
+	}
+
+	services, err := s.registry.ListServices(registry.ListContext(c.Context()))
+	if err != nil {
+		log.Errorf("Error listing services: %v", err)
+	}
+
+	sort.Sort(sortedServices{services})
+
+	if wantsJSON(c) {
+		return c.JSON(map[string]interface{}{
+			"services": services,
+		})
+	}
+
+	return s.render(c, registryTemplate, services)
 }
 
+// callHandler lists the endpoints callable on /client, as {"services":
+// {"name": [endpoints]}}
 func (s *service) callHandler(c *fiber.Ctx) error {
-	//services, err := s.registry.ListServices(registry.ListContext(c.Context()))
-	//if err != nil {
-	//	log.Errorf("Error listing services: %v", err)
-	//}
-	//
-	//sort.Sort(sortedServices{services})
-	//
-	//serviceMap := make(map[string][]*regpb.Endpoint)
-	//for _, service := range services {
-	//	if len(service.Endpoints) > 0 {
-	//		serviceMap[service.Name] = service.Endpoints
-	//		continue
-	//	}
-	//	// lookup the endpoints otherwise
-	//	s, err := s.registry.GetService(service.Name, registry.GetContext(r.Context()))
-	//	if err != nil {
-	//		continue
-	//	}
-	//	if len(s) == 0 {
-	//		continue
-	//	}
-	//	serviceMap[service.Name] = s[0].Endpoints
-	//}
-	//
-	//if r.Header.Get("Content-Type") == "application/json" {
-	//	b, err := json.Marshal(map[string]interface{}{
-	//		"services": services,
-	//	})
-	//	if err != nil {
-	//		http.Error(w, "Error occurred:"+err.Error(), 500)
-	//		return
-	//	}
-	//	w.Header().Set("Content-Type", "application/json")
-	//	w.Write(b)
-	//	return
-	//}
-	//
-	//return s.render(c, callTemplate, serviceMap)
-	return nil
+	services, err := s.registry.ListServices(registry.ListContext(c.Context()))
+	if err != nil {
+		log.Errorf("Error listing services: %v", err)
+	}
+
+	sort.Sort(sortedServices{services})
+
+	serviceMap := make(map[string][]*regpb.Endpoint)
+	for _, service := range services {
+		if len(service.Endpoints) > 0 {
+			serviceMap[service.Name] = service.Endpoints
+			continue
+		}
+		// lookup the endpoints otherwise
+		sv, err := s.registry.GetService(service.Name, registry.GetContext(c.Context()))
+		if err != nil {
+			continue
+		}
+		if len(sv) == 0 {
+			continue
+		}
+		serviceMap[service.Name] = sv[0].Endpoints
+	}
+
+	if wantsJSON(c) {
+		return c.JSON(map[string]interface{}{
+			"services": serviceMap,
+		})
+	}
+
+	return s.render(c, callTemplate, serviceMap)
 }
 
 func (s *service) render(c *fiber.Ctx, tmpl string, data interface{}) error {
@@ -500,6 +489,8 @@ func Run(ctx *cli.Context, svcOpts ...vine.Option) {
 		statsURL = "/stats"
 		st := stats.New()
 		s.app.All("/stats", st.StatsHandler)
+		// record the in-flight requests of the handlers of the service
+		_ = svc.Server().Init(cserver.WrapHandler(wrapper.HandlerStats(st)))
 		st.Start()
 		defer st.Stop()
 	}
@@ -511,7 +502,7 @@ func Run(ctx *cli.Context, svcOpts ...vine.Option) {
 	s.app.All("/favicon.ico", faviconHandler)
 	s.app.All("/client", s.callHandler)
 	s.app.All("/services", s.registryHandler)
-	s.app.All("/service/{name}", s.registryHandler)
+	s.app.All("/service/:name", s.registryHandler)
 	s.app.All("/rpc", handler.RPC)
 	s.app.All("/{service:[a-zA-Z0-9]+}", p.Handler)
 	s.app.All("/", s.indexHandler)
@@ -524,8 +515,7 @@ func Run(ctx *cli.Context, svcOpts ...vine.Option) {
 	if ctx.Bool("enable-tls") {
 		config, err := helper.TLSConfig(ctx)
 		if err != nil {
-			log.Errorf(err.Error())
-			return
+			log.Fatalf("Invalid TLS configuration: %v", err)
 		}
 
 		opts = append(opts, server.EnableTLS(true))
@@ -588,6 +578,8 @@ func Commands(options ...vine.Option) []*cli.Command {
 		},
 	}
 
+	command.Flags = append(command.Flags, helper.TLSFlags()...)
+
 	return []*cli.Command{command}
 }
 
@@ -0,0 +1,113 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package web
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/lack-io/vine/core/registry/memory"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+func testService(t *testing.T) *service {
+	r := memory.NewRegistry()
+	if err := r.Register(&regpb.Service{
+		Name:      "go.vine.foo",
+		Version:   "1.0.0",
+		Metadata:  map[string]string{"foo": "bar"},
+		Endpoints: []*regpb.Endpoint{{Name: "Foo.Bar"}},
+		Nodes:     []*regpb.Node{{Id: "foo-1", Address: "127.0.0.1:9090"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &service{
+		app:      fiber.New(fiber.Config{DisableStartupMessage: true}),
+		registry: &reg{Registry: r},
+	}
+	s.app.All("/client", s.callHandler)
+	s.app.All("/services", s.registryHandler)
+	s.app.All("/service/:name", s.registryHandler)
+
+	return s
+}
+
+func TestRegistryHandlerJSON(t *testing.T) {
+	s := testService(t)
+
+	testData := []struct {
+		path   string
+		accept string
+		json   bool
+		status int
+	}{
+		{"/services", fiber.MIMEApplicationJSON, true, 200},
+		{"/services", "text/html,application/xhtml+xml", false, 200},
+		{"/services?format=json", "text/html", true, 200},
+		{"/service/go.vine.foo", fiber.MIMEApplicationJSON, true, 200},
+		{"/service/go.vine.bar", fiber.MIMEApplicationJSON, false, 404},
+		{"/client", fiber.MIMEApplicationJSON, true, 200},
+	}
+
+	for _, d := range testData {
+		req := httptest.NewRequest("GET", d.path, nil)
+		req.Header.Set("Accept", d.accept)
+
+		rsp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rsp.StatusCode != d.status {
+			t.Fatalf("%s: expected status %d, got %d", d.path, d.status, rsp.StatusCode)
+		}
+
+		isJSON := rsp.Header.Get("Content-Type") == fiber.MIMEApplicationJSON
+		if isJSON != d.json {
+			t.Fatalf("%s: expected json %v with Accept %s, got %s", d.path, d.json, d.accept, rsp.Header.Get("Content-Type"))
+		}
+		if !isJSON {
+			continue
+		}
+
+		var body struct {
+			Services json.RawMessage `json:"services"`
+		}
+		if err := json.NewDecoder(rsp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: invalid json: %v", d.path, err)
+		}
+
+		if d.path == "/service/go.vine.foo" {
+			var services []*regpb.Service
+			if err := json.Unmarshal(body.Services, &services); err != nil {
+				t.Fatal(err)
+			}
+			if len(services) != 1 || len(services[0].Nodes) != 1 || len(services[0].Endpoints) != 1 || services[0].Metadata["foo"] != "bar" {
+				t.Fatalf("Expected the full service definition, got %s", body.Services)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package broker
+
+import (
+	"sync"
+	"time"
+)
+
+// DelayHeader is the header carrying the delay of a message to the brokers
+// which schedule the delivery themselves, e.g. "10m0s"
+const DelayHeader = "Vine-Delay"
+
+// Delayed holds the messages published with a delay until they are due.
+// The messages are kept in memory only, the pending ones are lost when the
+// process exits or the broker disconnects.
+type Delayed struct {
+	sync.Mutex
+	timers map[*time.Timer]struct{}
+}
+
+// NewDelayed returns an empty set of delayed messages
+func NewDelayed() *Delayed {
+	return &Delayed{timers: make(map[*time.Timer]struct{})}
+}
+
+// Add calls publish once the delay elapsed
+func (d *Delayed) Add(delay time.Duration, publish func()) {
+	d.Lock()
+	defer d.Unlock()
+
+	var t *time.Timer
+	t = time.AfterFunc(delay, func() {
+		d.Lock()
+		_, ok := d.timers[t]
+		delete(d.timers, t)
+		d.Unlock()
+
+		// dropped by Stop in the meantime
+		if ok {
+			publish()
+		}
+	})
+	d.timers[t] = struct{}{}
+}
+
+// Len returns the number of pending messages
+func (d *Delayed) Len() int {
+	d.Lock()
+	defer d.Unlock()
+	return len(d.timers)
+}
+
+// Stop drops the pending messages and returns their number
+func (d *Delayed) Stop() int {
+	d.Lock()
+	defer d.Unlock()
+
+	n := len(d.timers)
+	for t := range d.timers {
+		t.Stop()
+	}
+	d.timers = make(map[*time.Timer]struct{})
+	return n
+}
@@ -66,12 +66,27 @@ func (b *gRPCBroker) Options() broker.Options {
 }
 
 func (b *gRPCBroker) Publish(topic string, msg *broker.Message, opts ...broker.PublishOption) error {
+	var options broker.PublishOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	logger.Debugf("Publishing to topic %s broker %v", topic, b.Addrs)
 
+	header := msg.Header
+	// the broker service schedules the delivery
+	if options.Delay > 0 {
+		header = make(map[string]string, len(msg.Header)+1)
+		for k, v := range msg.Header {
+			header[k] = v
+		}
+		header[broker.DelayHeader] = options.Delay.String()
+	}
+
 	_, err := b.Client.Publish(context.TODO(), &pb.PublishRequest{
 		Topic: topic,
 		Message: &pb.Message{
-			Header: msg.Header,
+			Header: header,
 			Body:   msg.Body,
 		},
 	}, client.WithAddress(b.Addrs...))
@@ -44,6 +44,7 @@ import (
 	"github.com/lack-io/vine/core/codec/json"
 	"github.com/lack-io/vine/core/registry"
 	"github.com/lack-io/vine/core/registry/cache"
+	"github.com/lack-io/vine/lib/logger"
 	"github.com/lack-io/vine/proto/apis/errors"
 	regpb "github.com/lack-io/vine/proto/apis/registry"
 	maddr "github.com/lack-io/vine/util/addr"
@@ -71,6 +72,9 @@ type httpBroker struct {
 	// offline message inbox
 	mtx   sync.RWMutex
 	inbox map[string][][]byte
+
+	// messages published with a delay
+	delayed *broker.Delayed
 }
 
 type httpSubscriber struct {
@@ -159,6 +163,7 @@ func newHttpBroker(opts ...broker.Option) broker.Broker {
 		exit:        make(chan chan error),
 		mux:         http.NewServeMux(),
 		inbox:       make(map[string][][]byte),
+		delayed:     broker.NewDelayed(),
 	}
 
 	// specify the message handler
@@ -473,6 +478,10 @@ func (h *httpBroker) Disconnect() error {
 		rc.Stop()
 	}
 
+	if n := h.delayed.Stop(); n > 0 {
+		logger.Warnf("[http]: dropped %d delayed messages on disconnect", n)
+	}
+
 	// exit and return err
 	ch := make(chan error)
 	h.exit <- ch
@@ -535,6 +544,11 @@ func (h *httpBroker) Options() broker.Options {
 }
 
 func (h *httpBroker) Publish(topic string, msg *broker.Message, opts ...broker.PublishOption) error {
+	options := broker.PublishOptions{}
+	for _, o := range opts {
+		o(&options)
+	}
+
 	// create the message first
 	m := &broker.Message{
 		Header: make(map[string]string),
@@ -545,6 +559,22 @@ func (h *httpBroker) Publish(topic string, msg *broker.Message, opts ...broker.P
 		m.Header[k] = v
 	}
 
+	if options.Delay > 0 {
+		h.RLock()
+		running := h.running
+		h.RUnlock()
+		if !running {
+			return errs.New("not connected")
+		}
+
+		h.delayed.Add(options.Delay, func() {
+			if err := h.Publish(topic, m); err != nil {
+				logger.Errorf("[http]: failed to publish delayed message on topic %s: %v", topic, err)
+			}
+		})
+		return nil
+	}
+
 	m.Header["Vine-Topic"] = topic
 
 	// encode the message
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"sync"
+	"time"
+)
+
+// dedup is a bounded cache of recently seen message ids
+type dedup struct {
+	sync.Mutex
+	window time.Duration
+	size   int
+	// ring of ids in insertion order
+	ids  []string
+	next int
+	seen map[string]time.Time
+}
+
+func newDedup(window time.Duration, size int) *dedup {
+	return &dedup{
+		window: window,
+		size:   size,
+		ids:    make([]string, size),
+		seen:   make(map[string]time.Time, size),
+	}
+}
+
+// Seen records the id and reports whether it was already seen within the window
+func (d *dedup) Seen(id string) bool {
+	if len(id) == 0 {
+		return false
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	now := time.Now()
+	if t, ok := d.seen[id]; ok {
+		if d.window <= 0 || now.Sub(t) < d.window {
+			return true
+		}
+		// expired, refresh the timestamp in place
+		d.seen[id] = now
+		return false
+	}
+
+	// evict the oldest id
+	if old := d.ids[d.next]; len(old) > 0 {
+		delete(d.seen, old)
+	}
+	d.ids[d.next] = id
+	d.next = (d.next + 1) % d.size
+	d.seen[id] = now
+
+	return false
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/lack-io/vine/lib/logger"
 	maddr "github.com/lack-io/vine/util/addr"
 	mnet "github.com/lack-io/vine/util/net"
+	"github.com/lack-io/vine/util/ring"
 )
 
 type memoryBroker struct {
@@ -43,6 +44,12 @@ type memoryBroker struct {
 	sync.RWMutex
 	connected   bool
 	Subscribers map[string][]*memorySubscriber
+	// number of wildcard subscriptions
+	wildcards int
+	// ring buffers of the topics configured for replay
+	buffers map[string]*ring.Buffer
+	// messages published with a delay
+	delayed *broker.Delayed
 }
 
 func (m *memoryBroker) Options() broker.Options {
@@ -86,6 +93,10 @@ func (m *memoryBroker) Disconnect() error {
 
 	m.connected = false
 
+	if n := m.delayed.Stop(); n > 0 {
+		logger.Warnf("[memory]: dropped %d delayed messages on disconnect", n)
+	}
+
 	return nil
 }
 
@@ -93,19 +104,57 @@ func (m *memoryBroker) Init(opts ...broker.Option) error {
 	for _, o := range opts {
 		o(&m.opts)
 	}
+
+	m.Lock()
+	m.initBuffers()
+	m.Unlock()
+
 	return nil
 }
 
+// initBuffers creates the ring buffers of the topics configured for replay
+func (m *memoryBroker) initBuffers() {
+	for topic, size := range getReplayBuffers(m.opts.Context) {
+		if size <= 0 {
+			delete(m.buffers, topic)
+			continue
+		}
+		if _, ok := m.buffers[topic]; ok {
+			continue
+		}
+		m.buffers[topic] = ring.New(size)
+	}
+}
+
 func (m *memoryBroker) Publish(topic string, msg *broker.Message, opts ...broker.PublishOption) error {
+	var options broker.PublishOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	m.RLock()
 	if !m.connected {
 		m.RUnlock()
 		return errors.New("not connected")
 	}
 
-	subs, ok := m.Subscribers[topic]
-	m.RUnlock()
-	if !ok {
+	if options.Delay > 0 {
+		m.RUnlock()
+		m.delay(topic, msg, options.Delay)
+		return nil
+	}
+
+	subs := m.Subscribers[topic]
+	if m.wildcards > 0 {
+		// copy to avoid appending to the exact subscribers
+		subs = append([]*memorySubscriber{}, subs...)
+		for _, pattern := range patterns(topic) {
+			subs = append(subs, m.Subscribers[pattern]...)
+		}
+	}
+	buf := m.buffers[topic]
+	if len(subs) == 0 && buf == nil {
+		m.RUnlock()
 		return nil
 	}
 
@@ -113,6 +162,7 @@ func (m *memoryBroker) Publish(topic string, msg *broker.Message, opts ...broker
 	if m.opts.Codec != nil {
 		buf, err := m.opts.Codec.Marshal(msg)
 		if err != nil {
+			m.RUnlock()
 			return err
 		}
 		v = buf
@@ -126,7 +176,30 @@ func (m *memoryBroker) Publish(topic string, msg *broker.Message, opts ...broker
 		opts:    m.opts,
 	}
 
+	// buffer the message under the lock, a new subscriber either replays
+	// it or is one of the subscribers it is delivered to
+	if buf != nil {
+		buf.Put(p)
+	}
+	m.RUnlock()
+
+	key := msg.Header["Vine-Partition"]
+	partitioned := len(key) > 0 && getPartitionOrdering(m.opts.Context)
+
 	for _, sub := range subs {
+		if sub.dedup != nil && sub.dedup.Seen(msg.Header["Vine-Id"]) {
+			continue
+		}
+		if partitioned {
+			fn := m.dispatch(sub, p)
+			if !sub.hold(func() { sub.enqueue(key, fn) }) {
+				sub.enqueue(key, fn)
+			}
+			continue
+		}
+		if sub.hold(m.dispatch(sub, p)) {
+			continue
+		}
 		if err := sub.handler(p); err != nil {
 			p.err = err
 			if eh := m.opts.ErrorHandler; eh != nil {
@@ -140,6 +213,40 @@ func (m *memoryBroker) Publish(topic string, msg *broker.Message, opts ...broker
 	return nil
 }
 
+// delay publishes a copy of the message once the delay elapsed
+func (m *memoryBroker) delay(topic string, msg *broker.Message, d time.Duration) {
+	cp := &broker.Message{Header: make(map[string]string, len(msg.Header)), Body: msg.Body}
+	for k, v := range msg.Header {
+		cp.Header[k] = v
+	}
+
+	m.delayed.Add(d, func() {
+		if err := m.Publish(topic, cp); err != nil {
+			logger.Errorf("[memory]: failed to publish delayed message on topic %s: %v", topic, err)
+		}
+	})
+}
+
+// dispatch returns a function delivering the event to the subscriber
+// asynchronously, errors are passed to the ErrorHandler or logged.
+func (m *memoryBroker) dispatch(sub *memorySubscriber, p *memoryEvent) func() {
+	return func() {
+		if err := sub.handler(p); err != nil {
+			ev := &memoryEvent{
+				topic:   p.topic,
+				message: p.message,
+				opts:    p.opts,
+				err:     err,
+			}
+			if eh := m.opts.ErrorHandler; eh != nil {
+				eh(ev)
+				return
+			}
+			logger.Errorf("[memory]: failed to handle message on topic %s: %v", p.topic, err)
+		}
+	}
+}
+
 func (m *memoryBroker) Subscribe(topic string, handler broker.Handler, opts ...broker.SubscribeOption) (broker.Subscriber, error) {
 	m.RLock()
 	if !m.connected {
@@ -159,12 +266,38 @@ func (m *memoryBroker) Subscribe(topic string, handler broker.Handler, opts ...b
 		topic:   topic,
 		handler: handler,
 		opts:    options,
+		dedup:   getDedup(options.Context),
+		queues:  make(map[string][]func()),
 	}
 
+	var replay []func()
+
 	m.Lock()
+	if n, ok := getReplay(options.Context); ok {
+		if buf, ok := m.buffers[topic]; ok {
+			if n <= 0 {
+				n = -1
+			}
+			for _, entry := range buf.Get(n) {
+				replay = append(replay, m.dispatch(sub, entry.Value.(*memoryEvent)))
+			}
+		}
+	}
+	// hold the live messages until the buffered ones are delivered
+	if len(replay) > 0 {
+		sub.held = []func(){}
+	}
 	m.Subscribers[topic] = append(m.Subscribers[topic], sub)
+	if isPattern(topic) {
+		m.wildcards++
+	}
 	m.Unlock()
 
+	// deliver the buffered messages
+	if len(replay) > 0 {
+		go sub.replay(replay)
+	}
+
 	go func() {
 		<-sub.exit
 		m.Lock()
@@ -176,6 +309,9 @@ func (m *memoryBroker) Subscribe(topic string, handler broker.Handler, opts ...b
 			newSubscribers = append(newSubscribers, sb)
 		}
 		m.Subscribers[topic] = newSubscribers
+		if isPattern(topic) {
+			m.wildcards--
+		}
 		m.Unlock()
 	}()
 
@@ -227,6 +363,76 @@ type memorySubscriber struct {
 	exit    chan bool
 	handler broker.Handler
 	opts    broker.SubscribeOptions
+	dedup   *dedup
+
+	// per partition key queues of pending deliveries
+	mu     sync.Mutex
+	queues map[string][]func()
+	// live deliveries held while the buffered messages are replayed,
+	// nil once the replay is over
+	held []func()
+}
+
+// hold queues the delivery of a live message while the subscriber replays
+// the buffered messages, it returns false when there's no replay running.
+func (m *memorySubscriber) hold(fn func()) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.held == nil {
+		return false
+	}
+	m.held = append(m.held, fn)
+	return true
+}
+
+// replay delivers the buffered messages, then the live ones held meanwhile
+func (m *memorySubscriber) replay(fns []func()) {
+	for {
+		for _, fn := range fns {
+			fn()
+		}
+
+		m.mu.Lock()
+		fns = m.held
+		if len(fns) == 0 {
+			m.held = nil
+			m.mu.Unlock()
+			return
+		}
+		m.held = []func(){}
+		m.mu.Unlock()
+	}
+}
+
+// enqueue appends fn to the queue of the key, starting a worker
+// goroutine for the key when none is running.
+func (m *memorySubscriber) enqueue(key string, fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, running := m.queues[key]
+	m.queues[key] = append(q, fn)
+	if !running {
+		go m.drain(key)
+	}
+}
+
+func (m *memorySubscriber) drain(key string) {
+	for {
+		m.mu.Lock()
+		q := m.queues[key]
+		if len(q) == 0 {
+			delete(m.queues, key)
+			m.mu.Unlock()
+			return
+		}
+		fn := q[0]
+		m.queues[key] = q[1:]
+		m.mu.Unlock()
+
+		fn()
+	}
 }
 
 func (m *memorySubscriber) Options() broker.SubscribeOptions {
@@ -250,8 +456,13 @@ func NewBroker(opts ...broker.Option) broker.Broker {
 		o(&options)
 	}
 
-	return &memoryBroker{
+	m := &memoryBroker{
 		opts:        options,
 		Subscribers: make(map[string][]*memorySubscriber),
+		buffers:     make(map[string]*ring.Buffer),
+		delayed:     broker.NewDelayed(),
 	}
+	m.initBuffers()
+
+	return m
 }
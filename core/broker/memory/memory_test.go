@@ -24,7 +24,10 @@ package memory
 
 import (
 	"fmt"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/lack-io/vine/core/broker"
 )
@@ -70,3 +73,414 @@ func TestMemoryBroker(t *testing.T) {
 		t.Fatalf("Unexpected connect error %v", err)
 	}
 }
+
+func TestMemoryBrokerDedup(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	topic := "test"
+	count := 0
+
+	fn := func(p broker.Event) error {
+		count++
+		return nil
+	}
+
+	sub, err := b.Subscribe(topic, fn, Dedup(time.Minute, 10))
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+
+	for _, id := range []string{"1", "2", "1", "2", "3"} {
+		message := &broker.Message{
+			Header: map[string]string{
+				"Vine-Id": id,
+			},
+			Body: []byte(`hello world`),
+		}
+
+		if err := b.Publish(topic, message); err != nil {
+			t.Fatalf("Unexpected error publishing %s", id)
+		}
+	}
+
+	if count != 3 {
+		t.Fatalf("Expected handler to be invoked 3 times, got %d", count)
+	}
+
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatalf("Unexpected error unsubscribing from %s: %v", topic, err)
+	}
+
+	if err := b.Disconnect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+}
+
+func TestMemoryBrokerPartitionOrdering(t *testing.T) {
+	b := NewBroker(PartitionOrdering())
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	topic := "test"
+	keys := 5
+	count := 100
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	received := make(map[string][]int)
+	wg.Add(keys * count)
+
+	fn := func(p broker.Event) error {
+		defer wg.Done()
+		msg := p.Message()
+		i, _ := strconv.Atoi(msg.Header["seq"])
+		mu.Lock()
+		received[msg.Header["Vine-Partition"]] = append(received[msg.Header["Vine-Partition"]], i)
+		mu.Unlock()
+		return nil
+	}
+
+	sub, err := b.Subscribe(topic, fn)
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+
+	var pwg sync.WaitGroup
+	for k := 0; k < keys; k++ {
+		pwg.Add(1)
+		go func(key string) {
+			defer pwg.Done()
+			for i := 0; i < count; i++ {
+				message := &broker.Message{
+					Header: map[string]string{
+						"Vine-Partition": key,
+						"seq":            fmt.Sprintf("%d", i),
+					},
+					Body: []byte(`hello world`),
+				}
+				if err := b.Publish(topic, message); err != nil {
+					t.Errorf("Unexpected error publishing %d", i)
+				}
+			}
+		}(fmt.Sprintf("key-%d", k))
+	}
+	pwg.Wait()
+	wg.Wait()
+
+	for key, seq := range received {
+		if len(seq) != count {
+			t.Fatalf("Expected %d messages for %s, got %d", count, key, len(seq))
+		}
+		for i, v := range seq {
+			if v != i {
+				t.Fatalf("Expected message %d for %s, got %d", i, key, v)
+			}
+		}
+	}
+
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatalf("Unexpected error unsubscribing from %s: %v", topic, err)
+	}
+
+	if err := b.Disconnect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+}
+
+func TestMemoryBrokerReplay(t *testing.T) {
+	b := NewBroker(ReplayBuffer("test", 3))
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	topic := "test"
+
+	// publish before anyone subscribes
+	for i := 0; i < 5; i++ {
+		message := &broker.Message{
+			Header: map[string]string{
+				"id": fmt.Sprintf("%d", i),
+			},
+			Body: []byte(`hello world`),
+		}
+
+		if err := b.Publish(topic, message); err != nil {
+			t.Fatalf("Unexpected error publishing %d", i)
+		}
+	}
+
+	var mu sync.Mutex
+	var ids []string
+	fn := func(p broker.Event) error {
+		mu.Lock()
+		ids = append(ids, p.Message().Header["id"])
+		mu.Unlock()
+		return nil
+	}
+
+	sub, err := b.Subscribe(topic, fn, Replay(0))
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+
+	// the buffered messages are replayed in the background
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(ids)
+		mu.Unlock()
+		if n >= 3 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	mu.Lock()
+	if len(ids) != 3 || ids[0] != "2" || ids[1] != "3" || ids[2] != "4" {
+		mu.Unlock()
+		t.Fatalf("Expected buffered messages [2 3 4], got %v", ids)
+	}
+	mu.Unlock()
+
+	// subscribers without replay only get new messages
+	var count int
+	sub2, err := b.Subscribe(topic, func(p broker.Event) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("Expected no replayed messages, got %d", count)
+	}
+
+	for _, s := range []broker.Subscriber{sub, sub2} {
+		if err := s.Unsubscribe(); err != nil {
+			t.Fatalf("Unexpected error unsubscribing from %s: %v", topic, err)
+		}
+	}
+
+	if err := b.Disconnect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+}
+
+func TestMemoryBrokerReplayOrder(t *testing.T) {
+	b := NewBroker(ReplayBuffer("test", 3))
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+	defer b.Disconnect()
+
+	topic := "test"
+	publish := func(id string) {
+		if err := b.Publish(topic, &broker.Message{Header: map[string]string{"id": id}}); err != nil {
+			t.Fatalf("Unexpected error publishing %s: %v", id, err)
+		}
+	}
+
+	publish("0")
+	publish("1")
+
+	// block the replay of the first buffered message
+	release := make(chan bool)
+	received := make(chan string, 10)
+	fn := func(p broker.Event) error {
+		id := p.Message().Header["id"]
+		if id == "0" {
+			<-release
+		}
+		received <- id
+		return nil
+	}
+
+	sub, err := b.Subscribe(topic, fn, Replay(0))
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	// a message published during the replay is delivered after it
+	publish("2")
+	close(release)
+
+	var ids []string
+	for len(ids) < 3 {
+		select {
+		case id := <-received:
+			ids = append(ids, id)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected 3 messages, got %v", ids)
+		}
+	}
+	if ids[0] != "0" || ids[1] != "1" || ids[2] != "2" {
+		t.Fatalf("Expected the replayed messages before the live one [0 1 2], got %v", ids)
+	}
+
+	publish("3")
+	select {
+	case id := <-received:
+		if id != "3" {
+			t.Fatalf("Expected message 3, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected message 3")
+	}
+}
+
+func TestMemoryBrokerWildcard(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	received := make(map[string][]string)
+	subscribe := func(topic string) broker.Subscriber {
+		sub, err := b.Subscribe(topic, func(p broker.Event) error {
+			received[topic] = append(received[topic], p.Topic())
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error subscribing %v", err)
+		}
+		return sub
+	}
+
+	subs := []broker.Subscriber{
+		subscribe("runtime.*"),
+		subscribe("audit.>"),
+		subscribe("runtime.create"),
+	}
+
+	for _, topic := range []string{"runtime.create", "runtime.delete", "runtime.service.create", "audit.login", "audit.login.failed", "audit"} {
+		if err := b.Publish(topic, &broker.Message{Body: []byte(`hello world`)}); err != nil {
+			t.Fatalf("Unexpected error publishing %s", topic)
+		}
+	}
+
+	expected := map[string][]string{
+		"runtime.*":      {"runtime.create", "runtime.delete"},
+		"audit.>":        {"audit.login", "audit.login.failed"},
+		"runtime.create": {"runtime.create"},
+	}
+
+	for topic, topics := range expected {
+		if fmt.Sprint(received[topic]) != fmt.Sprint(topics) {
+			t.Fatalf("Expected %s to receive %v, got %v", topic, topics, received[topic])
+		}
+	}
+
+	for _, sub := range subs {
+		if err := sub.Unsubscribe(); err != nil {
+			t.Fatalf("Unexpected error unsubscribing from %s: %v", sub.Topic(), err)
+		}
+	}
+
+	if err := b.Disconnect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+}
+
+func benchmarkPublish(b *testing.B, wildcards bool) {
+	br := NewBroker()
+	if err := br.Connect(); err != nil {
+		b.Fatalf("Unexpected connect error %v", err)
+	}
+
+	fn := func(p broker.Event) error {
+		return nil
+	}
+
+	for i := 0; i < 2000; i++ {
+		if _, err := br.Subscribe(fmt.Sprintf("service.%d.event", i), fn); err != nil {
+			b.Fatal(err)
+		}
+		if wildcards {
+			if _, err := br.Subscribe(fmt.Sprintf("service.%d.*", i), fn); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := br.Subscribe(fmt.Sprintf("service.%d.>", i), fn); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	msg := &broker.Message{Body: []byte(`hello world`)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := br.Publish(fmt.Sprintf("service.%d.event", i%2000), msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPublishExact(b *testing.B) {
+	benchmarkPublish(b, false)
+}
+
+func BenchmarkPublishWildcard(b *testing.B) {
+	benchmarkPublish(b, true)
+}
+
+func TestMemoryBrokerDelay(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	received := make(chan time.Time, 1)
+	_, err := b.Subscribe("delay", func(p broker.Event) error {
+		received <- time.Now()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+
+	start := time.Now()
+	if err := b.Publish("delay", &broker.Message{Body: []byte(`hello`)}, broker.PublishDelay(time.Millisecond*100)); err != nil {
+		t.Fatalf("Unexpected error publishing %v", err)
+	}
+
+	select {
+	case at := <-received:
+		if d := at.Sub(start); d < time.Millisecond*100 {
+			t.Fatalf("Expected the message after the delay, got it after %v", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the delayed message to be delivered")
+	}
+
+	// the pending messages are dropped on disconnect
+	if err := b.Publish("delay", &broker.Message{Body: []byte(`hello`)}, broker.PublishDelay(time.Millisecond*100)); err != nil {
+		t.Fatalf("Unexpected error publishing %v", err)
+	}
+	if err := b.Disconnect(); err != nil {
+		t.Fatalf("Unexpected disconnect error %v", err)
+	}
+	if n := b.(*memoryBroker).delayed.Len(); n != 0 {
+		t.Fatalf("Expected no pending messages, got %d", n)
+	}
+
+	select {
+	case <-received:
+		t.Fatal("Expected the pending message to be dropped")
+	case <-time.After(time.Millisecond * 200):
+	}
+
+	if err := b.Publish("delay", &broker.Message{}, broker.PublishDelay(time.Second)); err == nil {
+		t.Fatal("Expected the delayed publish to fail when not connected")
+	}
+}
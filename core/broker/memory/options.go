@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/lack-io/vine/core/broker"
+)
+
+type dedupKey struct{}
+
+type partitionKey struct{}
+
+type replayBufferKey struct{}
+
+type replayKey struct{}
+
+type dedupOptions struct {
+	window time.Duration
+	size   int
+}
+
+// Dedup enables subscribe side deduplication of messages. Messages are
+// identified by the Vine-Id header, the last size ids are remembered and
+// a message with an id seen within the given window is dropped.
+func Dedup(window time.Duration, size int) broker.SubscribeOption {
+	return func(o *broker.SubscribeOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, dedupKey{}, dedupOptions{window: window, size: size})
+	}
+}
+
+func getDedup(ctx context.Context) *dedup {
+	if ctx == nil {
+		return nil
+	}
+	v, ok := ctx.Value(dedupKey{}).(dedupOptions)
+	if !ok || v.size <= 0 {
+		return nil
+	}
+	return newDedup(v.window, v.size)
+}
+
+// PartitionOrdering delivers messages sharing a Vine-Partition header in
+// publish order on a single goroutine per key, while messages with different
+// keys are handled in parallel. Messages without the header are delivered
+// synchronously as usual.
+func PartitionOrdering() broker.Option {
+	return func(o *broker.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, partitionKey{}, true)
+	}
+}
+
+func getPartitionOrdering(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(partitionKey{}).(bool)
+	return v
+}
+
+// ReplayBuffer keeps the last size messages published to the topic in a
+// ring buffer, so that late subscribers can replay them with Replay.
+func ReplayBuffer(topic string, size int) broker.Option {
+	return func(o *broker.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		buffers := make(map[string]int)
+		if v, ok := o.Context.Value(replayBufferKey{}).(map[string]int); ok {
+			for k, n := range v {
+				buffers[k] = n
+			}
+		}
+		buffers[topic] = size
+		o.Context = context.WithValue(o.Context, replayBufferKey{}, buffers)
+	}
+}
+
+// Replay delivers up to the last n buffered messages of the topic to the
+// subscriber in the background on subscribe, ahead of the messages published
+// meanwhile, n <= 0 replays the whole buffer. The topic must have a buffer
+// configured with ReplayBuffer.
+func Replay(n int) broker.SubscribeOption {
+	return func(o *broker.SubscribeOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, replayKey{}, n)
+	}
+}
+
+func getReplayBuffers(ctx context.Context) map[string]int {
+	if ctx == nil {
+		return nil
+	}
+	v, _ := ctx.Value(replayBufferKey{}).(map[string]int)
+	return v
+}
+
+func getReplay(ctx context.Context) (int, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	v, ok := ctx.Value(replayKey{}).(int)
+	return v, ok
+}
@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"strings"
+)
+
+// Topics are dot separated segments. A subscription topic may end with a
+// wildcard segment: "*" matches exactly one segment and ">" matches one
+// or more segments, e.g. "runtime.*" matches "runtime.create" while
+// "audit.>" matches "audit.login" and "audit.login.failed".
+
+const (
+	wildcardOne  = "*"
+	wildcardMany = ">"
+)
+
+// isPattern reports whether the subscription topic ends with a wildcard segment
+func isPattern(topic string) bool {
+	return topic == wildcardOne || topic == wildcardMany ||
+		strings.HasSuffix(topic, "."+wildcardOne) || strings.HasSuffix(topic, "."+wildcardMany)
+}
+
+// patterns returns the wildcard subscription topics matching the published
+// topic. Patterns are looked up by key, so matching is one map access per
+// segment rather than a scan of all the subscriptions.
+func patterns(topic string) []string {
+	segments := strings.Split(topic, ".")
+	keys := make([]string, 0, len(segments)+1)
+
+	// single segment wildcard on the last segment
+	if prefix := strings.Join(segments[:len(segments)-1], "."); len(prefix) > 0 {
+		keys = append(keys, prefix+"."+wildcardOne)
+	} else {
+		keys = append(keys, wildcardOne)
+	}
+
+	// multi segment wildcard on every prefix
+	keys = append(keys, wildcardMany)
+	for i := 1; i < len(segments); i++ {
+		keys = append(keys, strings.Join(segments[:i], ".")+"."+wildcardMany)
+	}
+
+	return keys
+}
@@ -25,6 +25,7 @@ package broker
 import (
 	"context"
 	"crypto/tls"
+	"time"
 
 	"github.com/lack-io/vine/core/codec"
 	"github.com/lack-io/vine/core/registry"
@@ -48,6 +49,8 @@ type Options struct {
 }
 
 type PublishOptions struct {
+	// Delay of the delivery of the message
+	Delay time.Duration
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
@@ -79,6 +82,13 @@ func PublishContext(ctx context.Context) PublishOption {
 	}
 }
 
+// PublishDelay delays the delivery of the message by d
+func PublishDelay(d time.Duration) PublishOption {
+	return func(o *PublishOptions) {
+		o.Delay = d
+	}
+}
+
 type SubscribeOption func(*SubscribeOptions)
 
 func NewSubscribeOptions(opts ...SubscribeOption) SubscribeOptions {
@@ -0,0 +1,137 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/core/client/selector"
+	"github.com/lack-io/vine/proto/apis/errors"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+// circuit is the failure state of a node
+type circuit struct {
+	// failures within the window
+	failures []time.Time
+	// openUntil is the end of the cooldown of a tripped circuit
+	openUntil time.Time
+}
+
+// breakers tracks the circuits of the nodes keyed by address
+type breakers struct {
+	sync.Mutex
+	circuits map[string]*circuit
+}
+
+func newBreakers() *breakers {
+	return &breakers{circuits: make(map[string]*circuit)}
+}
+
+func (b *breakers) open(addr string, now time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+	c, ok := b.circuits[addr]
+	return ok && now.Before(c.openUntil)
+}
+
+// mark records the result of a call to the node, a success closes its circuit
+func (b *breakers) mark(addr string, err error, cb client.CircuitBreaker) {
+	if !failed(err) {
+		b.Lock()
+		delete(b.circuits, addr)
+		b.Unlock()
+		return
+	}
+
+	now := time.Now()
+
+	b.Lock()
+	defer b.Unlock()
+
+	c, ok := b.circuits[addr]
+	if !ok {
+		c = &circuit{}
+		b.circuits[addr] = c
+	}
+
+	// the trial call after the cooldown failed
+	if !c.openUntil.IsZero() {
+		if !now.Before(c.openUntil) {
+			c.openUntil = now.Add(cb.Cooldown)
+		}
+		return
+	}
+
+	failures := c.failures[:0]
+	for _, t := range c.failures {
+		if now.Sub(t) < cb.Window {
+			failures = append(failures, t)
+		}
+	}
+	c.failures = append(failures, now)
+
+	if len(c.failures) >= cb.Threshold {
+		c.failures = nil
+		c.openUntil = now.Add(cb.Cooldown)
+	}
+}
+
+// filter is a select filter which excludes the nodes with a tripped circuit
+func (b *breakers) filter() selector.Filter {
+	return func(old []*regpb.Service) []*regpb.Service {
+		now := time.Now()
+
+		var services []*regpb.Service
+		for _, service := range old {
+			var nodes []*regpb.Node
+			for _, node := range service.Nodes {
+				if !b.open(node.Address, now) {
+					nodes = append(nodes, node)
+				}
+			}
+
+			// only add service if there's some nodes
+			if len(nodes) > 0 {
+				serv := new(regpb.Service)
+				*serv = *service
+				serv.Nodes = nodes
+				services = append(services, serv)
+			}
+		}
+
+		return services
+	}
+}
+
+// failed reports whether the error counts against the node, the errors
+// caused by the request itself don't
+func failed(err error) bool {
+	if err == nil {
+		return false
+	}
+	verr := errors.FromErr(err)
+	return verr.Code < 400 || verr.Code >= 500 || verr.Code == 408
+}
@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/proto/apis/errors"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	count := func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, node *regpb.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			mu.Lock()
+			attempts[node.Address]++
+			mu.Unlock()
+			return cf(ctx, node, req, rsp, opts)
+		}
+	}
+
+	reg, c := newTestService(t, "go.vine.delay", &Delay{},
+		client.WrapCall(count),
+		client.Retries(5),
+		client.Retry(client.RetryAlways),
+	)
+
+	// a node which refuses the connections
+	services, err := reg.GetService("go.vine.delay")
+	if err != nil || len(services) == 0 {
+		t.Fatalf("Expected the service to be registered: %v", err)
+	}
+	down := &regpb.Node{Id: "go.vine.delay-down", Address: "127.0.0.1:1", Metadata: map[string]string{"protocol": "grpc"}}
+	if err := reg.Register(&regpb.Service{Name: "go.vine.delay", Version: services[0].Version, Nodes: []*regpb.Node{down}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register(&regpb.Service{Name: "go.vine.down", Version: "latest", Nodes: []*regpb.Node{down}}); err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(service string) error {
+		req := c.NewRequest(service, "Delay.Say", &Message{Say: "0s"}, client.WithContentType("application/json"))
+		return c.Call(context.Background(), req, &Message{}, client.WithCircuitBreaker(2, time.Minute, time.Minute))
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := call("go.vine.delay"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := attempts[down.Address]; n != 2 {
+		t.Fatalf("Expected the node to be skipped after 2 failures, got %d attempts", n)
+	}
+
+	// every node of the service is tripped
+	err = call("go.vine.down")
+	if verr := errors.FromErr(err); verr == nil || verr.Code != 503 {
+		t.Fatalf("Expected the circuit to be open, got %v", err)
+	}
+	if n := attempts[down.Address]; n != 2 {
+		t.Fatalf("Expected no more attempts, got %d", n)
+	}
+}
+
+func TestBreakersMark(t *testing.T) {
+	b := newBreakers()
+	cb := client.CircuitBreaker{Threshold: 2, Window: 50 * time.Millisecond, Cooldown: 50 * time.Millisecond}
+	failure := errors.InternalServerError("go.vine.test", "failure")
+
+	// the failures outside of the window don't add up
+	b.mark("a", failure, cb)
+	time.Sleep(60 * time.Millisecond)
+	b.mark("a", failure, cb)
+	if b.open("a", time.Now()) {
+		t.Fatal("Expected the circuit to be closed")
+	}
+
+	// nor the errors caused by the request
+	b.mark("a", errors.BadRequest("go.vine.test", "bad"), cb)
+	if b.open("a", time.Now()) {
+		t.Fatal("Expected the circuit to be closed")
+	}
+
+	b.mark("a", failure, cb)
+	b.mark("a", failure, cb)
+	if !b.open("a", time.Now()) {
+		t.Fatal("Expected the circuit to be open")
+	}
+
+	// the trial after the cooldown trips the circuit again
+	time.Sleep(60 * time.Millisecond)
+	if b.open("a", time.Now()) {
+		t.Fatal("Expected the circuit to allow a trial")
+	}
+	b.mark("a", failure, cb)
+	if !b.open("a", time.Now()) {
+		t.Fatal("Expected the circuit to be open")
+	}
+
+	// a success closes it
+	time.Sleep(60 * time.Millisecond)
+	b.mark("a", nil, cb)
+	if _, ok := b.circuits["a"]; ok {
+		t.Fatal("Expected the circuit to be removed")
+	}
+}
@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lack-io/vine/core/client"
+)
+
+func TestNegotiateCodec(t *testing.T) {
+	g := newClient().(*grpcClient)
+
+	if _, _, err := g.negotiateCodec("application/x-unknown"); err == nil {
+		t.Fatal("Expected an error for an unknown content type without fallback")
+	}
+
+	g = newClient(FallbackContentType("application/json")).(*grpcClient)
+
+	_, contentType, err := g.negotiateCodec("application/x-unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "application/json" {
+		t.Fatalf("Expected fallback to application/json, got %s", contentType)
+	}
+
+	// the supported content types are kept
+	_, contentType, err = g.negotiateCodec("application/protobuf")
+	if err != nil || contentType != "application/protobuf" {
+		t.Fatalf("Expected application/protobuf, got %s: %v", contentType, err)
+	}
+}
+
+func TestCallFallbackContentType(t *testing.T) {
+	_, c := newTestService(t, "go.vine.echo", &Echo{},
+		FallbackContentType("application/json"),
+	)
+
+	req := c.NewRequest("go.vine.echo", "Echo.Say", &Message{Say: "hello"}, client.WithContentType("application/x-unknown"))
+	rsp := &Message{}
+	if err := c.Call(context.Background(), req, rsp); err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Say != "hello" {
+		t.Fatalf("Expected hello, got %q", rsp.Say)
+	}
+}
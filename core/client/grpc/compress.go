@@ -0,0 +1,51 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+
+	// the gzip compressor of the bodies
+	_ "github.com/lack-io/vine/core/codec/gzip"
+)
+
+// compressor returns the name of the compressor of the call, empty when
+// the compressor isn't registered
+func compressor(name string) string {
+	if len(name) == 0 || encoding.GetCompressor(name) == nil {
+		return ""
+	}
+	return name
+}
+
+// unsupportedCompression reports whether the server rejected the call for
+// lack of the compressor of the body
+func unsupportedCompression(err error) bool {
+	s, ok := status.FromError(err)
+	return ok && err != nil && s.Code() == codes.Unimplemented &&
+		strings.Contains(s.Message(), "Decompressor is not installed")
+}
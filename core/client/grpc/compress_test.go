@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/lack-io/vine/core/client"
+)
+
+func TestCompression(t *testing.T) {
+	_, c := newTestService(t, "go.vine.echo", &Echo{})
+
+	say := strings.Repeat("vine ", 10000)
+	for _, compression := range []string{"", "gzip", "unknown"} {
+		req := c.NewRequest("go.vine.echo", "Echo.Say", &Message{Say: say}, client.WithContentType("application/json"))
+		rsp := &Message{}
+		if err := c.Call(context.Background(), req, rsp, client.WithCompression(compression)); err != nil {
+			t.Fatalf("Call with compression %q: %v", compression, err)
+		}
+		if rsp.Say != say {
+			t.Fatalf("Unexpected response with compression %q", compression)
+		}
+	}
+}
+
+func TestCompressionFallback(t *testing.T) {
+	var calls int32
+
+	// a server which can't decompress the first call
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return status.Errorf(codes.Unimplemented, "grpc: Decompressor is not installed for grpc-encoding %q", "gzip")
+		}
+		msg := &Message{}
+		if err := stream.RecvMsg(msg); err != nil {
+			return err
+		}
+		return stream.SendMsg(msg)
+	}))
+	addr := testServe(t, srv)
+
+	c := newTestClient(newTestNode(t, "go.vine.legacy", addr))
+
+	req := c.NewRequest("go.vine.legacy", "Echo.Say", &Message{Say: "hello"}, client.WithContentType("application/json"))
+	rsp := &Message{}
+	if err := c.Call(context.Background(), req, rsp, client.WithCompression("gzip"), client.WithRetries(0)); err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Say != "hello" {
+		t.Fatalf("Unexpected response %q", rsp.Say)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("Expected the call to be sent again uncompressed, got %d calls", n)
+	}
+}
@@ -0,0 +1,122 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	gmetadata "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/proto/apis/errors"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+func TestDeadlineHeader(t *testing.T) {
+	var mu sync.Mutex
+	var timeouts []time.Duration
+
+	// a server failing every call which records the timeout header
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		md, _ := gmetadata.FromIncomingContext(stream.Context())
+		if v := md.Get("timeout"); len(v) > 0 {
+			n, _ := strconv.ParseInt(v[0], 10, 64)
+			mu.Lock()
+			timeouts = append(timeouts, time.Duration(n))
+			mu.Unlock()
+		}
+		return status.Error(codes.Unavailable, "unavailable")
+	}))
+	addr := testServe(t, srv)
+
+	c := newTestClient(newTestNode(t, "go.vine.deadline", addr))
+
+	backoff := func(ctx context.Context, req client.Request, attempts int) (time.Duration, error) {
+		return time.Duration(attempts) * 100 * time.Millisecond, nil
+	}
+
+	req := c.NewRequest("go.vine.deadline", "Test.Call", &Message{}, client.WithContentType("application/json"))
+	err := c.Call(context.Background(), req, &Message{},
+		client.WithRequestTimeout(2*time.Second),
+		client.WithRetries(2),
+		client.WithRetry(client.RetryAlways),
+		client.WithBackoff(backoff),
+	)
+	if err == nil {
+		t.Fatal("Expected the call to fail")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(timeouts) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", len(timeouts))
+	}
+	for i, to := range timeouts {
+		if to > 2*time.Second {
+			t.Fatalf("Expected the timeout within the request timeout, got %v", to)
+		}
+		if i > 0 && timeouts[i-1]-to < 100*time.Millisecond {
+			t.Fatalf("Expected the timeout to shrink across the retries, got %v", timeouts)
+		}
+	}
+}
+
+func TestDeadlinePassed(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return status.Error(codes.Unavailable, "unavailable")
+	}))
+	addr := testServe(t, srv)
+
+	c := newTestClient(newTestNode(t, "go.vine.deadline", addr))
+
+	// the deadline passes before the attempt, e.g. during its backoff
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	node := &regpb.Node{Id: "go.vine.deadline-1", Address: addr, Metadata: map[string]string{"protocol": "grpc"}}
+	req := c.NewRequest("go.vine.deadline", "Test.Call", &Message{}, client.WithContentType("application/json"))
+	err := c.(*grpcClient).call(ctx, node, req, &Message{}, c.Options().CallOptions)
+	if verr := errors.FromErr(err); verr == nil || verr.Code != 408 || !strings.Contains(verr.Detail, "before sending the request") {
+		t.Fatalf("Expected a local timeout, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 0 {
+		t.Fatalf("Expected the request not to be sent, got %d calls", calls)
+	}
+}
@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/core/client"
+)
+
+func TestDrain(t *testing.T) {
+	_, cc := newTestService(t, "go.vine.delay", &Delay{})
+	c := cc.(*grpcClient)
+
+	call := func(delay time.Duration) error {
+		req := c.NewRequest("go.vine.delay", "Delay.Say", &Message{Say: delay.String()}, client.WithContentType("application/json"))
+		return c.Call(context.Background(), req, &Message{}, client.WithRetries(0), client.WithRequestTimeout(5*time.Second))
+	}
+
+	slow := func() <-chan error {
+		ch := make(chan error, 1)
+		go func() {
+			ch <- call(300 * time.Millisecond)
+		}()
+		// let the call take a conn of the pool
+		time.Sleep(100 * time.Millisecond)
+		return ch
+	}
+
+	// replacing the pool doesn't interrupt the call
+	ch := slow()
+	old := c.pool
+	if err := c.Init(client.PoolSize(10)); err != nil {
+		t.Fatal(err)
+	}
+	if c.pool == old {
+		t.Fatal("Expected the pool to be replaced")
+	}
+	if err := <-ch; err != nil {
+		t.Fatalf("Expected the call to complete, got %v", err)
+	}
+
+	// the previous pool is closed once drained
+	time.Sleep(50 * time.Millisecond)
+	old.Lock()
+	closed := old.closed
+	old.Unlock()
+	if !closed {
+		t.Fatal("Expected the previous pool to be closed")
+	}
+
+	if err := call(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// closing waits for the call in flight
+	ch = slow()
+	start := time.Now()
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 100*time.Millisecond {
+		t.Fatalf("Expected Close to wait for the call, took %v", d)
+	}
+	if err := <-ch; err != nil {
+		t.Fatalf("Expected the call to complete, got %v", err)
+	}
+
+	if err := call(0); err == nil {
+		t.Fatal("Expected the closed client to fail the call")
+	}
+}
@@ -27,8 +27,10 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net"
+	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -37,10 +39,12 @@ import (
 	"google.golang.org/grpc/encoding"
 	gmetadata "google.golang.org/grpc/metadata"
 
+	"github.com/google/uuid"
 	"github.com/lack-io/vine/core/broker"
 	"github.com/lack-io/vine/core/client"
 	"github.com/lack-io/vine/core/client/selector"
 	"github.com/lack-io/vine/core/codec/bytes"
+	"github.com/lack-io/vine/lib/logger"
 	"github.com/lack-io/vine/proto/apis/errors"
 	regpb "github.com/lack-io/vine/proto/apis/registry"
 	"github.com/lack-io/vine/util/context/metadata"
@@ -48,9 +52,21 @@ import (
 )
 
 type grpcClient struct {
-	opts client.Options
-	pool *pool
-	once atomic.Value
+	opts    client.Options
+	streams *streams
+	once    atomic.Value
+
+	// mu guards the pool replaced by Init
+	mu     sync.RWMutex
+	pool   *pool
+	closed bool
+
+	// breakers tracks the circuits of the nodes
+	breakers *breakers
+	// budget bounds the retries of the calls with a retry policy
+	budget *client.RetryBudget
+	// wg tracks the in-flight calls and stream setups
+	wg sync.WaitGroup
 }
 
 func init() {
@@ -71,6 +87,11 @@ func (g *grpcClient) secure(addr string) grpc.DialOption {
 		}
 	}
 
+	// unix domain sockets are local, don't bother with tls
+	if mnet.IsUnix(addr) {
+		return grpc.WithInsecure()
+	}
+
 	// default config
 	tlsCfg := &tls.Config{}
 	defaultCreds := grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg))
@@ -105,22 +126,86 @@ func (g *grpcClient) next(request client.Request, opts client.CallOptions) (sele
 		}, nil
 	}
 
+	selectOptions := opts.SelectOptions
+	if opts.CircuitBreaker.Enabled() {
+		// copy the options shared with the client's call options
+		selectOptions = append(selectOptions[:len(selectOptions):len(selectOptions)], selector.WithFilter(g.breakers.filter()))
+	}
+
 	// get next nodes from the selector
-	next, err := g.opts.Selector.Select(service, opts.SelectOptions...)
+	next, err := g.opts.Selector.Select(service, selectOptions...)
 	if err != nil {
 		if err == selector.ErrNotFound {
 			return nil, errors.InternalServerError("go.vine.client", "service %s: %s", service, err.Error())
 		}
+		if err == selector.ErrNoneAvailable && len(opts.Version) > 0 {
+			return nil, errors.NotFound("go.vine.client", "service %s: version %s not found", service, opts.Version)
+		}
+		if err == selector.ErrNoneAvailable && opts.CircuitBreaker.Enabled() {
+			return nil, errors.ServiceUnavailable("go.vine.client", "service %s: circuit open for every node", service)
+		}
 		return nil, errors.InternalServerError("go.vine.client", "error selecting %s node: %s", service, err.Error())
 	}
 
 	return next, nil
 }
 
+// mark records the result of the call to the node with the selector and
+// the circuit breakers
+func (g *grpcClient) mark(service string, node *regpb.Node, err error, opts client.CallOptions) {
+	g.opts.Selector.Mark(service, node, err)
+	if opts.CircuitBreaker.Enabled() {
+		g.breakers.mark(node.Address, err, opts.CircuitBreaker)
+	}
+}
+
+// address returns the address to dial the node on, preferring the unix
+// socket advertised by nodes running on the same host
+func (g *grpcClient) address(node *regpb.Node) string {
+	path, ok := node.Metadata["unix"]
+	if !ok || len(path) == 0 {
+		return node.Address
+	}
+
+	hostname, _ := os.Hostname()
+	if node.Metadata["hostname"] != hostname {
+		return node.Address
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return node.Address
+	}
+
+	return mnet.UnixAddr(path)
+}
+
+// begin registers an in-flight call, it fails once the client is closed
+func (g *grpcClient) begin() (*pool, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.closed {
+		return nil, errors.InternalServerError("go.vine.client", "client is closed")
+	}
+	g.wg.Add(1)
+	g.pool.calls.Add(1)
+	return g.pool, nil
+}
+
+func (g *grpcClient) end(p *pool) {
+	p.calls.Done()
+	g.wg.Done()
+}
+
 func (g *grpcClient) call(ctx context.Context, node *regpb.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+	p, err := g.begin()
+	if err != nil {
+		return err
+	}
+	defer g.end(p)
+
 	var header map[string]string
 
-	address := node.Address
+	address := g.address(node)
 
 	header = make(map[string]string)
 	if md, ok := metadata.FromContext(ctx); ok {
@@ -132,19 +217,41 @@ func (g *grpcClient) call(ctx context.Context, node *regpb.Node, req client.Requ
 		header = make(map[string]string)
 	}
 
-	// set timeout in nanoseconds
-	header["timeout"] = fmt.Sprintf("%d", opts.RequestTimeout)
-	// set the content type for the request
-	header["x-content-type"] = req.ContentType()
+	// set the priority of the call
+	if opts.Priority != client.PriorityNormal {
+		header[strings.ToLower(client.PriorityHeader)] = opts.Priority.String()
+	}
 
-	md := gmetadata.New(header)
-	ctx = gmetadata.NewOutgoingContext(ctx, md)
+	if limit, ok := g.maxMetadataSizeValue(); ok {
+		if err := limitMetadata(header, limit); err != nil {
+			return err
+		}
+	}
 
-	cf, err := g.newGRPCCodec(req.ContentType())
+	// set timeout in nanoseconds, the time left until the deadline which
+	// shrinks across the retries. grpc sends the deadline as grpc-timeout.
+	timeout := opts.RequestTimeout
+	if d, ok := ctx.Deadline(); ok {
+		timeout = time.Until(d)
+		// the deadline passed, e.g. during the backoff of a retry
+		if timeout <= 0 {
+			return errors.Timeout("go.vine.client", "deadline exceeded %v ago before sending the request", -timeout)
+		}
+	}
+	if timeout > 0 {
+		header["timeout"] = fmt.Sprintf("%d", timeout)
+	}
+	cf, contentType, err := g.negotiateCodec(req.ContentType())
 	if err != nil {
 		return errors.InternalServerError("go.vine.client", err.Error())
 	}
 
+	// set the content type for the request
+	header["x-content-type"] = contentType
+
+	md := gmetadata.New(header)
+	ctx = gmetadata.NewOutgoingContext(ctx, md)
+
 	maxRecvMsgSize := g.maxRecvMsgSizeValue()
 	maxSendMsgSize := g.maxSendMsgSizeValue()
 
@@ -163,12 +270,27 @@ func (g *grpcClient) call(ctx context.Context, node *regpb.Node, req client.Requ
 		grpcDialOptions = append(grpcDialOptions, opts...)
 	}
 
-	cc, err := g.pool.getConn(address, grpcDialOptions...)
+	cc, err := p.getConn(address, grpcDialOptions...)
+	if err == ErrPoolTimeout {
+		return errors.Timeout("go.vine.client", "Error sending request: %v after %v", err, g.opts.PoolAcquireTimeout)
+	}
 	if err != nil {
 		return errors.InternalServerError("go.vine.client", fmt.Sprintf("Error sending request: %v", err))
 	}
 	// defer execution of release
-	defer g.pool.release(address, cc, grr)
+	defer p.release(address, cc, grr)
+
+	// fail early when the server doesn't respond in time
+	var firstResponse <-chan time.Time
+	if opts.ResponseTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		t := time.NewTimer(opts.ResponseTimeout)
+		defer t.Stop()
+		firstResponse = t.C
+	}
 
 	ch := make(chan error, 1)
 
@@ -181,13 +303,25 @@ func (g *grpcClient) call(ctx context.Context, node *regpb.Node, req client.Requ
 			grpcCallOptions = append(grpcCallOptions, opts...)
 		}
 
-		err := cc.Invoke(ctx, methodToGRPC(req.Service(), req.Endpoint()), req.Body(), rsp, grpcCallOptions...)
+		method := methodToGRPC(req.Service(), req.Endpoint())
+		if compression := compressor(opts.Compression); len(compression) > 0 {
+			err := cc.Invoke(ctx, method, req.Body(), rsp, append(grpcCallOptions, grpc.UseCompressor(compression))...)
+			if !unsupportedCompression(err) {
+				ch <- vineError(err)
+				return
+			}
+			// the server can't decompress the body, send it uncompressed
+		}
+
+		err := cc.Invoke(ctx, method, req.Body(), rsp, grpcCallOptions...)
 		ch <- vineError(err)
 	}()
 
 	select {
 	case err := <-ch:
 		grr = err
+	case <-firstResponse:
+		grr = errors.Timeout("go.vine.client", "no response within %v", opts.ResponseTimeout)
 	case <-ctx.Done():
 		grr = errors.Timeout("go.vine.client", "%v", ctx.Err())
 	}
@@ -196,9 +330,15 @@ func (g *grpcClient) call(ctx context.Context, node *regpb.Node, req client.Requ
 }
 
 func (g *grpcClient) stream(ctx context.Context, node *regpb.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+	p, err := g.begin()
+	if err != nil {
+		return err
+	}
+	defer g.end(p)
+
 	var header map[string]string
 
-	address := node.Address
+	address := g.address(node)
 
 	if md, ok := metadata.FromContext(ctx); ok {
 		header = make(map[string]string, len(md))
@@ -209,21 +349,32 @@ func (g *grpcClient) stream(ctx context.Context, node *regpb.Node, req client.Re
 		header = make(map[string]string)
 	}
 
+	// set the priority of the call
+	if opts.Priority != client.PriorityNormal {
+		header[strings.ToLower(client.PriorityHeader)] = opts.Priority.String()
+	}
+
+	if limit, ok := g.maxMetadataSizeValue(); ok {
+		if err := limitMetadata(header, limit); err != nil {
+			return err
+		}
+	}
+
 	// set timeout in nanoseconds
 	if opts.StreamTimeout > time.Duration(0) {
 		header["timeout"] = fmt.Sprintf("%d", opts.StreamTimeout)
 	}
+	cf, contentType, err := g.negotiateCodec(req.ContentType())
+	if err != nil {
+		return errors.InternalServerError("go.vine.client", err.Error())
+	}
+
 	// set the content type for the request
-	header["x-content-type"] = req.ContentType()
+	header["x-content-type"] = contentType
 
 	md := gmetadata.New(header)
 	ctx = gmetadata.NewOutgoingContext(ctx, md)
 
-	cf, err := g.newGRPCCodec(req.ContentType())
-	if err != nil {
-		return errors.InternalServerError("go.vine.client", err.Error())
-	}
-
 	var dialCtx context.Context
 	var cancel context.CancelFunc
 	if opts.DialTimeout >= 0 {
@@ -262,6 +413,9 @@ func (g *grpcClient) stream(ctx context.Context, node *regpb.Node, req client.Re
 	if opts := g.getGrpcCallOptions(); opts != nil {
 		grpcCallOptions = append(grpcCallOptions, opts...)
 	}
+	if compression := compressor(opts.Compression); len(compression) > 0 {
+		grpcCallOptions = append(grpcCallOptions, grpc.UseCompressor(compression))
+	}
 
 	// create a new cancelling context
 	newCtx, cancel := context.WithCancel(ctx)
@@ -300,6 +454,12 @@ func (g *grpcClient) stream(ctx context.Context, node *regpb.Node, req client.Re
 		stream: st,
 		conn:   cc,
 		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	// fail the stream when the server doesn't send a first message in time
+	if opts.ResponseTimeout > 0 {
+		stream.firstResponse = newFirstResponse(opts.ResponseTimeout, cancel)
 	}
 
 	// set the stream as the response
@@ -330,6 +490,17 @@ func (g *grpcClient) poolMaxIdle() int {
 	return v.(int)
 }
 
+func (g *grpcClient) drainTimeout() time.Duration {
+	if g.opts.Context == nil {
+		return DefaultDrainTimeout
+	}
+	v := g.opts.Context.Value(drainTimeoutKey{})
+	if v == nil {
+		return DefaultDrainTimeout
+	}
+	return v.(time.Duration)
+}
+
 func (g *grpcClient) maxRecvMsgSizeValue() int {
 	if g.opts.Context == nil {
 		return DefaultMaxRecvMsgSize
@@ -341,6 +512,14 @@ func (g *grpcClient) maxRecvMsgSizeValue() int {
 	return v.(int)
 }
 
+func (g *grpcClient) fallbackContentTypeValue() (string, bool) {
+	if g.opts.Context == nil {
+		return "", false
+	}
+	v, ok := g.opts.Context.Value(fallbackContentTypeKey{}).(string)
+	return v, ok && len(v) > 0
+}
+
 func (g *grpcClient) maxSendMsgSizeValue() int {
 	if g.opts.Context == nil {
 		return DefaultMaxSendMsgSize
@@ -368,25 +547,85 @@ func (g *grpcClient) newGRPCCodec(contentType string) (encoding.Codec, error) {
 	return nil, fmt.Errorf("unsupported Content-Type: %s", contentType)
 }
 
+// negotiateCodec returns the codec of the content type, or the codec of the
+// fallback content type when enabled and the content type is unsupported
+func (g *grpcClient) negotiateCodec(contentType string) (encoding.Codec, string, error) {
+	cf, err := g.newGRPCCodec(contentType)
+	if err == nil {
+		return cf, contentType, nil
+	}
+
+	fallback, ok := g.fallbackContentTypeValue()
+	if !ok || fallback == contentType {
+		return nil, "", err
+	}
+
+	cf, ferr := g.newGRPCCodec(fallback)
+	if ferr != nil {
+		return nil, "", err
+	}
+
+	logger.Warnf("Unsupported Content-Type %s, falling back to %s", contentType, fallback)
+	return cf, fallback, nil
+}
+
 func (g *grpcClient) Init(opts ...client.Option) error {
 	size := g.opts.PoolSize
 	ttl := g.opts.PoolTTL
+	acquire := g.opts.PoolAcquireTimeout
+	idle := g.poolMaxIdle()
+	streams := g.poolMaxStreams()
 
 	for _, o := range opts {
 		o(&g.opts)
 	}
 
-	// update pool configuration if the options changed
-	if size != g.opts.PoolSize || ttl != g.opts.PoolTTL {
-		g.pool.Lock()
-		g.pool.size = g.opts.PoolSize
-		g.pool.ttl = int64(g.opts.PoolTTL.Seconds())
-		g.pool.Unlock()
+	// replace the pool if the options changed, the previous pool is closed
+	// once the calls using it are done
+	if size != g.opts.PoolSize || ttl != g.opts.PoolTTL || acquire != g.opts.PoolAcquireTimeout ||
+		idle != g.poolMaxIdle() || streams != g.poolMaxStreams() {
+		g.mu.Lock()
+		old := g.pool
+		g.pool = newPool(g.opts.PoolSize, g.opts.PoolTTL, g.poolMaxIdle(), g.poolMaxStreams(), g.opts.PoolAcquireTimeout)
+		g.mu.Unlock()
+
+		go old.drain(g.drainTimeout())
 	}
 
 	return nil
 }
 
+// Close stops accepting calls and waits for the in-flight calls to finish,
+// up to the drain timeout, before closing the connections of the pool
+func (g *grpcClient) Close() error {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return nil
+	}
+	g.closed = true
+	p := g.pool
+	g.mu.Unlock()
+
+	timeout := g.drainTimeout()
+	drained := waitTimeout(&g.wg, timeout)
+	p.close()
+
+	if !drained {
+		return errors.Timeout("go.vine.client", "calls still in flight after %v", timeout)
+	}
+	return nil
+}
+
+// PoolStats returns the counters of the connection pool by address, the
+// counters restart when Init replaces the pool
+func (g *grpcClient) PoolStats() map[string]PoolStats {
+	g.mu.RLock()
+	p := g.pool
+	g.mu.RUnlock()
+	return p.stats()
+}
+
 func (g *grpcClient) Options() client.Options {
 	return g.opts
 }
@@ -459,8 +698,15 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 			time.Sleep(t)
 		}
 
-		// select next node
-		node, err := next()
+		// select next node, again on retries to skip the nodes tripped
+		// by the previous attempts
+		selectNext := next
+		if i > 0 && callOpts.CircuitBreaker.Enabled() {
+			if selectNext, err = g.next(req, callOpts); err != nil {
+				return err
+			}
+		}
+		node, err := selectNext()
 		service := req.Service()
 		if err != nil {
 			if err == selector.ErrNotFound {
@@ -469,9 +715,13 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 			return errors.InternalServerError("go.vine.client", "error selecting %s node: %s", service, err.Error())
 		}
 
-		// make the call
-		err = gcall(ctx, node, req, rsp, callOpts)
-		g.opts.Selector.Mark(service, node, err)
+		// make the call, hedged calls mark their nodes themselves
+		if hedged(req, rsp, callOpts) {
+			err = g.hedge(ctx, node, gcall, req, rsp, callOpts)
+		} else {
+			err = gcall(ctx, node, req, rsp, callOpts)
+			g.mark(service, node, err, callOpts)
+		}
 		if verr, ok := err.(*errors.Error); ok {
 			return verr
 		}
@@ -479,6 +729,11 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 		return err
 	}
 
+	// the call adds to the retry budget of the client
+	if p := callOpts.RetryPolicy; p != nil && p.Budget > 0 {
+		g.budget.Deposit(p.Budget)
+	}
+
 	ch := make(chan error, callOpts.Retries+1)
 	var gerr error
 
@@ -496,7 +751,7 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 				return nil
 			}
 
-			retry, rerr := callOpts.Retry(ctx, req, i, err)
+			retry, rerr := g.retry(ctx, req, i, err, callOpts)
 			if rerr != nil {
 				return rerr
 			}
@@ -512,6 +767,26 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 	return gerr
 }
 
+// retry reports whether the failed attempt is retried, by the retry policy
+// within the retry budget when set, by the Retry func otherwise
+func (g *grpcClient) retry(ctx context.Context, req client.Request, i int, err error, opts client.CallOptions) (bool, error) {
+	p := opts.RetryPolicy
+	if p == nil {
+		return opts.Retry(ctx, req, i, err)
+	}
+
+	// the last attempt or an error caused by the request
+	if i >= opts.Retries || !p.Retryable(err) {
+		return false, nil
+	}
+
+	if p.Budget > 0 && !g.budget.Withdraw() {
+		return false, client.RetryBudgetExhausted(err)
+	}
+
+	return true, nil
+}
+
 func (g *grpcClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
 	// make a copy of call opts
 	callOpts := g.opts.CallOptions
@@ -553,7 +828,14 @@ func (g *grpcClient) Stream(ctx context.Context, req client.Request, opts ...cli
 			time.Sleep(t)
 		}
 
-		node, err := next()
+		// select again on retries to skip the tripped nodes
+		selectNext := next
+		if i > 0 && callOpts.CircuitBreaker.Enabled() {
+			if selectNext, err = g.next(req, callOpts); err != nil {
+				return nil, err
+			}
+		}
+		node, err := selectNext()
 		service := req.Service()
 		if err != nil {
 			if err == selector.ErrNotFound {
@@ -565,8 +847,17 @@ func (g *grpcClient) Stream(ctx context.Context, req client.Request, opts ...cli
 		// make the call
 		stream := &grpcStream{}
 		err = g.stream(ctx, node, req, stream, callOpts)
+		if err == nil && stream.done != nil {
+			// track the stream until it's closed, releasing the connection
+			// if the context is cancelled mid-stream
+			id := g.streams.add(req.Service(), req.Endpoint(), node.Address)
+			go func() {
+				stream.watch()
+				g.streams.remove(id)
+			}()
+		}
 
-		g.opts.Selector.Mark(service, node, err)
+		g.mark(service, node, err, callOpts)
 		return stream, err
 	}
 
@@ -575,6 +866,11 @@ func (g *grpcClient) Stream(ctx context.Context, req client.Request, opts ...cli
 		err    error
 	}
 
+	// the stream adds to the retry budget of the client
+	if p := callOpts.RetryPolicy; p != nil && p.Budget > 0 {
+		g.budget.Deposit(p.Budget)
+	}
+
 	ch := make(chan response, callOpts.Retries+1)
 	var grr error
 
@@ -593,7 +889,7 @@ func (g *grpcClient) Stream(ctx context.Context, req client.Request, opts ...cli
 				return rsp.stream, nil
 			}
 
-			retry, rerr := callOpts.Retry(ctx, req, i, err)
+			retry, rerr := g.retry(ctx, req, i, rsp.err, callOpts)
 			if rerr != nil {
 				return nil, rerr
 			}
@@ -619,14 +915,17 @@ func (g *grpcClient) Publish(ctx context.Context, p client.Message, opts ...clie
 	if !ok {
 		md = make(map[string]string)
 	}
-	md["Content-Type"] = p.ContentType()
-	md["Vine-Topic"] = p.Topic()
-
-	cf, err := g.newGRPCCodec(p.ContentType())
+	cf, contentType, err := g.negotiateCodec(p.ContentType())
 	if err != nil {
 		return errors.InternalServerError("go.vine.client", err.Error())
 	}
 
+	md["Content-Type"] = contentType
+	md["Vine-Topic"] = p.Topic()
+	if _, ok := md["Vine-Id"]; !ok {
+		md["Vine-Id"] = uuid.New().String()
+	}
+
 	var body []byte
 
 	// passed in raw data
@@ -659,7 +958,12 @@ func (g *grpcClient) Publish(ctx context.Context, p client.Message, opts ...clie
 		Header: md,
 		Body:   body,
 	}
-	return g.opts.Broker.Publish(topic, msg, broker.PublishContext(options.Context))
+	pubOpts := []broker.PublishOption{broker.PublishContext(options.Context)}
+	if options.Delay > 0 {
+		pubOpts = append(pubOpts, broker.PublishDelay(options.Delay))
+	}
+
+	return g.opts.Broker.Publish(topic, msg, pubOpts...)
 }
 
 func (g *grpcClient) String() string {
@@ -710,11 +1014,14 @@ func newClient(opts ...client.Option) client.Client {
 	}
 
 	rc := &grpcClient{
-		opts: options,
+		opts:     options,
+		streams:  newStreams(),
+		breakers: newBreakers(),
+		budget:   client.NewRetryBudget(),
 	}
 	rc.once.Store(false)
 
-	rc.pool = newPool(options.PoolSize, options.PoolTTL, rc.poolMaxIdle(), rc.poolMaxStreams())
+	rc.pool = newPool(options.PoolSize, options.PoolTTL, rc.poolMaxIdle(), rc.poolMaxStreams(), options.PoolAcquireTimeout)
 
 	c := client.Client(rc)
 
@@ -23,6 +23,7 @@
 package grpc
 
 import (
+	"errors"
 	"sync"
 	"time"
 
@@ -38,11 +39,25 @@ type pool struct {
 	maxStreams int
 	// max idle conns
 	maxIdle int
+	// how long to wait for a conn when the pool is exhausted, when zero
+	// the conns exceeding the pool size are dialled without pooling
+	acquireTimeout time.Duration
 
 	sync.Mutex
 	conns map[string]*streamsPool
+	// released is closed and renewed when a conn is released
+	released chan struct{}
+	// closed pools close the conns on release
+	closed bool
+
+	// calls tracks the calls using the pool
+	calls sync.WaitGroup
 }
 
+// ErrPoolTimeout is returned when no conn of the exhausted pool was released
+// before the acquire timeout
+var ErrPoolTimeout = errors.New("timed out acquiring a connection from the pool")
+
 type streamsPool struct {
 	// head of list
 	head *poolConn
@@ -52,6 +67,26 @@ type streamsPool struct {
 	count int
 	// idle conn
 	idle int
+
+	// counters
+	dials      uint64
+	dialErrors uint64
+	evictions  uint64
+}
+
+// PoolStats are the counters of the pooled conns of an address
+type PoolStats struct {
+	// Active is the number of pooled conns with calls in flight
+	Active int `json:"active"`
+	// Idle is the number of pooled conns without calls
+	Idle int `json:"idle"`
+	// Dials is the number of conns dialled
+	Dials uint64 `json:"dials"`
+	// DialErrors is the number of failed dials
+	DialErrors uint64 `json:"dial_errors"`
+	// Evictions is the number of conns closed by the pool for being idle
+	// in excess, too old or failing
+	Evictions uint64 `json:"evictions"`
 }
 
 type poolConn struct {
@@ -72,7 +107,7 @@ type poolConn struct {
 	in   bool
 }
 
-func newPool(size int, ttl time.Duration, idle int, ms int) *pool {
+func newPool(size int, ttl time.Duration, idle int, ms int, acquire time.Duration) *pool {
 	if ms <= 0 {
 		ms = 1
 	}
@@ -80,15 +115,20 @@ func newPool(size int, ttl time.Duration, idle int, ms int) *pool {
 		idle = 0
 	}
 	return &pool{
-		size:       size,
-		ttl:        int64(ttl.Seconds()),
-		maxStreams: ms,
-		maxIdle:    idle,
-		conns:      make(map[string]*streamsPool),
+		size:           size,
+		ttl:            int64(ttl.Seconds()),
+		maxStreams:     ms,
+		maxIdle:        idle,
+		acquireTimeout: acquire,
+		conns:          make(map[string]*streamsPool),
+		released:       make(chan struct{}),
 	}
 }
 
 func (p *pool) getConn(addr string, opts ...grpc.DialOption) (*poolConn, error) {
+	var timeout <-chan time.Time
+
+retry:
 	now := time.Now().Unix()
 	p.Lock()
 	sp, ok := p.conns[addr]
@@ -111,6 +151,7 @@ func (p *pool) getConn(addr string, opts ...grpc.DialOption) (*poolConn, error)
 			if conn.streams == 0 {
 				removeConn(conn)
 				sp.idle--
+				sp.evictions++
 			}
 			conn = next
 			continue
@@ -120,6 +161,7 @@ func (p *pool) getConn(addr string, opts ...grpc.DialOption) (*poolConn, error)
 				removeConn(conn)
 				_ = conn.ClientConn.Close()
 				sp.idle--
+				sp.evictions++
 			}
 			conn = next
 			continue
@@ -134,6 +176,7 @@ func (p *pool) getConn(addr string, opts ...grpc.DialOption) (*poolConn, error)
 				removeConn(conn)
 				_ = conn.ClientConn.Close()
 				sp.idle--
+				sp.evictions++
 			}
 			conn = next
 			continue
@@ -155,17 +198,40 @@ func (p *pool) getConn(addr string, opts ...grpc.DialOption) (*poolConn, error)
 		p.Unlock()
 		return conn, nil
 	}
+
+	// wait for a conn to be released when the pool is exhausted
+	if p.acquireTimeout > 0 && sp.count >= p.size {
+		released := p.released
+		p.Unlock()
+
+		if timeout == nil {
+			t := time.NewTimer(p.acquireTimeout)
+			defer t.Stop()
+			timeout = t.C
+		}
+
+		select {
+		case <-released:
+			goto retry
+		case <-timeout:
+			return nil, ErrPoolTimeout
+		}
+	}
 	p.Unlock()
 
 	// create new conn
 	cc, err := grpc.Dial(addr, opts...)
+
+	p.Lock()
+	sp.dials++
 	if err != nil {
+		sp.dialErrors++
+		p.Unlock()
 		return nil, err
 	}
 	conn = &poolConn{cc, nil, addr, p, sp, 1, time.Now().Unix(), nil, nil, false}
 
 	// add conn to streams pool
-	p.Lock()
 	if sp.count < p.size {
 		addConnAfter(conn, sp.head)
 	}
@@ -177,6 +243,15 @@ func (p *pool) getConn(addr string, opts ...grpc.DialOption) (*poolConn, error)
 func (p *pool) release(addr string, conn *poolConn, err error) {
 	p.Lock()
 	p, sp, created := conn.pool, conn.sp, conn.created
+	// wake up the callers waiting for a conn
+	close(p.released)
+	p.released = make(chan struct{})
+	// the pool is gone, close the conn
+	if p.closed {
+		p.Unlock()
+		_ = conn.ClientConn.Close()
+		return
+	}
 	// try to add conn
 	if !conn.in && sp.count < p.size {
 		addConnAfter(conn, sp.head)
@@ -200,6 +275,7 @@ func (p *pool) release(addr string, conn *poolConn, err error) {
 		now := time.Now().Unix()
 		if err != nil || sp.idle >= p.maxIdle || now-created > p.ttl {
 			removeConn(conn)
+			sp.evictions++
 			p.Unlock()
 			_ = conn.ClientConn.Close()
 			return
@@ -210,6 +286,74 @@ func (p *pool) release(addr string, conn *poolConn, err error) {
 	return
 }
 
+// stats returns the counters of the pool by address
+func (p *pool) stats() map[string]PoolStats {
+	p.Lock()
+	defer p.Unlock()
+
+	stats := make(map[string]PoolStats, len(p.conns))
+	for addr, sp := range p.conns {
+		st := PoolStats{
+			Idle:       sp.idle,
+			Dials:      sp.dials,
+			DialErrors: sp.dialErrors,
+			Evictions:  sp.evictions,
+		}
+		for _, head := range []*poolConn{sp.head, sp.busy} {
+			for conn := head.next; conn != nil; conn = conn.next {
+				if conn.streams > 0 {
+					st.Active++
+				}
+			}
+		}
+		stats[addr] = st
+	}
+	return stats
+}
+
+// drain closes the pool once its calls are done, or after the timeout
+func (p *pool) drain(timeout time.Duration) {
+	waitTimeout(&p.calls, timeout)
+	p.close()
+}
+
+// close closes the pooled conns, the conns still in use are closed on release
+func (p *pool) close() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.closed = true
+	for _, sp := range p.conns {
+		for _, head := range []*poolConn{sp.head, sp.busy} {
+			for conn := head.next; conn != nil; conn = conn.next {
+				if conn.streams == 0 {
+					_ = conn.ClientConn.Close()
+				}
+			}
+		}
+	}
+	p.conns = make(map[string]*streamsPool)
+}
+
+// waitTimeout waits for the wait group, it reports false after the timeout
+func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-t.C:
+		return false
+	}
+}
+
 func (conn *poolConn) Close() {
 	conn.pool.release(conn.addr, conn, conn.err)
 }
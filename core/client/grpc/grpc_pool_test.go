@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func testPoolServer(t *testing.T) string {
+	return testServe(t, grpc.NewServer())
+}
+
+func TestPoolAcquireTimeout(t *testing.T) {
+	addr := testPoolServer(t)
+
+	// a single conn with a single stream
+	p := newPool(1, time.Minute, 1, 1, 100*time.Millisecond)
+
+	conn, err := p.getConn(addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := p.getConn(addr, grpc.WithInsecure(), grpc.WithBlock()); err != ErrPoolTimeout {
+		t.Fatalf("Expected ErrPoolTimeout, got %v", err)
+	}
+	if d := time.Since(start); d < 100*time.Millisecond {
+		t.Fatalf("Expected to wait for the acquire timeout, waited %v", d)
+	}
+
+	// a conn released while waiting is acquired
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		p.release(addr, conn, nil)
+	}()
+
+	next, err := p.getConn(addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != conn {
+		t.Fatal("Expected the released conn to be reused")
+	}
+	p.release(addr, next, nil)
+}
+
+func TestPoolUnbounded(t *testing.T) {
+	addr := testPoolServer(t)
+
+	// without acquire timeout the exhausted pool dials new conns
+	p := newPool(1, time.Minute, 1, 1, 0)
+
+	conn, err := p.getConn(addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := p.getConn(addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == conn {
+		t.Fatal("Expected a new conn")
+	}
+	p.release(addr, other, nil)
+	p.release(addr, conn, nil)
+}
+
+func TestPoolStats(t *testing.T) {
+	addr := testPoolServer(t)
+
+	// at most one idle conn of a single stream
+	p := newPool(5, time.Minute, 1, 1, 0)
+
+	var conns []*poolConn
+	for i := 0; i < 3; i++ {
+		conn, err := p.getConn(addr, grpc.WithInsecure(), grpc.WithBlock())
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn)
+	}
+
+	st := p.stats()[addr]
+	if st.Active != 3 || st.Idle != 0 || st.Dials != 3 {
+		t.Fatalf("Unexpected stats %+v", st)
+	}
+
+	for _, conn := range conns {
+		p.release(addr, conn, nil)
+	}
+
+	// the idle conns in excess are evicted
+	st = p.stats()[addr]
+	if st.Active != 0 || st.Idle != 1 || st.Evictions != 2 {
+		t.Fatalf("Unexpected stats %+v", st)
+	}
+
+	if _, err := p.getConn("127.0.0.1:1", grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(50*time.Millisecond)); err == nil {
+		t.Fatal("Expected the dial to fail")
+	}
+	if st := p.stats()["127.0.0.1:1"]; st.Dials != 1 || st.DialErrors != 1 {
+		t.Fatalf("Unexpected stats %+v", st)
+	}
+}
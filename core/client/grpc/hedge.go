@@ -0,0 +1,162 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/core/client/selector"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+	mnet "github.com/lack-io/vine/util/net"
+)
+
+// attempt is the result of a call to a node of a hedged request
+type attempt struct {
+	node *regpb.Node
+	rsp  interface{}
+	err  error
+}
+
+// hedged reports whether the call may be hedged. The request has to be idempotent
+// and not sent to a proxy, each attempt decodes into its own response.
+func hedged(req client.Request, rsp interface{}, opts client.CallOptions) bool {
+	if !opts.Hedging.Enabled() {
+		return false
+	}
+	if r, ok := req.(interface{ Idempotent() bool }); !ok || !r.Idempotent() {
+		return false
+	}
+	if _, address, _ := mnet.Proxy(req.Service(), opts.Address); len(address) > 0 {
+		return false
+	}
+	return reflect.TypeOf(rsp).Kind() == reflect.Ptr
+}
+
+// hedge calls the node and a further node each time none of the attempts
+// responded within the hedging delay. The first response is used and the
+// other attempts are cancelled.
+func (g *grpcClient) hedge(ctx context.Context, node *regpb.Node, gcall client.CallFunc, req client.Request, rsp interface{}, opts client.CallOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	service := req.Service()
+	ch := make(chan attempt, opts.Hedging.Max+1)
+
+	var nodes []*regpb.Node
+	send := func(node *regpb.Node) {
+		nodes = append(nodes, node)
+		r := reflect.New(reflect.TypeOf(rsp).Elem()).Interface()
+		go func() {
+			ch <- attempt{node: node, rsp: r, err: gcall(ctx, node, req, r, opts)}
+		}()
+	}
+
+	send(node)
+
+	t := time.NewTimer(opts.Hedging.Delay)
+	defer t.Stop()
+
+	var gerr error
+	for pending := 1; pending > 0; {
+		select {
+		case <-t.C:
+			if len(nodes) > opts.Hedging.Max {
+				continue
+			}
+			// no further node to call, wait for the attempts made
+			node, err := g.distinct(req, opts, nodes)
+			if err != nil {
+				continue
+			}
+			send(node)
+			pending++
+			t.Reset(opts.Hedging.Delay)
+		case a := <-ch:
+			pending--
+			g.mark(service, a.node, a.err, opts)
+			if a.err != nil {
+				gerr = a.err
+				continue
+			}
+
+			reflect.ValueOf(rsp).Elem().Set(reflect.ValueOf(a.rsp).Elem())
+
+			// the cancelled attempts aren't failures of their nodes
+			cancel()
+			go func(pending int) {
+				for i := 0; i < pending; i++ {
+					a := <-ch
+					g.opts.Selector.Mark(service, a.node, nil)
+				}
+			}(pending)
+
+			return nil
+		}
+	}
+
+	return gerr
+}
+
+// distinct selects a node which isn't one of the given nodes
+func (g *grpcClient) distinct(req client.Request, opts client.CallOptions, nodes []*regpb.Node) (*regpb.Node, error) {
+	// copy the options shared with the client's call options
+	opts.SelectOptions = append(opts.SelectOptions[:len(opts.SelectOptions):len(opts.SelectOptions)], selector.WithFilter(exclude(nodes)))
+
+	next, err := g.next(req, opts)
+	if err != nil {
+		return nil, err
+	}
+	return next()
+}
+
+// exclude is a select filter which excludes the given nodes
+func exclude(nodes []*regpb.Node) selector.Filter {
+	return func(old []*regpb.Service) []*regpb.Service {
+		var services []*regpb.Service
+		for _, service := range old {
+			var keep []*regpb.Node
+		loop:
+			for _, node := range service.Nodes {
+				for _, n := range nodes {
+					if n.Address == node.Address {
+						continue loop
+					}
+				}
+				keep = append(keep, node)
+			}
+
+			// only add service if there's some nodes
+			if len(keep) > 0 {
+				serv := new(regpb.Service)
+				*serv = *service
+				serv.Nodes = keep
+				services = append(services, serv)
+			}
+		}
+
+		return services
+	}
+}
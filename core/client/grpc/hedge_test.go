@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/core/registry/memory"
+	"github.com/lack-io/vine/core/server"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+func TestHedging(t *testing.T) {
+	reg := memory.NewRegistry()
+
+	for i := 0; i < 2; i++ {
+		startTestServer(t, reg, "go.vine.delay", &Delay{}, server.Id(fmt.Sprintf("hedge-%d", i)))
+	}
+
+	services, err := reg.GetService("go.vine.delay")
+	if err != nil || len(services) == 0 || len(services[0].Nodes) != 2 {
+		t.Fatalf("Expected two nodes to be registered: %v", err)
+	}
+
+	// the first attempt of a call hangs until it's cancelled
+	var mu sync.Mutex
+	var attempts []string
+	cancelled := make(chan struct{}, 1)
+	slow := func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, node *regpb.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			mu.Lock()
+			attempts = append(attempts, node.Address)
+			first := len(attempts) == 1
+			mu.Unlock()
+			if first {
+				<-ctx.Done()
+				cancelled <- struct{}{}
+			}
+			return cf(ctx, node, req, rsp, opts)
+		}
+	}
+
+	c := newTestClient(reg,
+		client.RequestTimeout(time.Second*2),
+		client.Retries(0),
+	)
+
+	call := func(reqOpts []client.RequestOption, opts ...client.CallOption) (*Message, error) {
+		mu.Lock()
+		attempts = nil
+		mu.Unlock()
+		reqOpts = append(reqOpts, client.WithContentType("application/json"))
+		req := c.NewRequest("go.vine.delay", "Delay.Say", &Message{Say: "0s"}, reqOpts...)
+		rsp := &Message{}
+		err := c.Call(context.TODO(), req, rsp, append(opts, client.WithCallWrapper(slow))...)
+		return rsp, err
+	}
+
+	hedging := client.WithHedging(time.Millisecond*50, 1)
+
+	start := time.Now()
+	rsp, err := call([]client.RequestOption{client.Idempotent()}, hedging)
+	if err != nil {
+		t.Fatalf("Expected the hedged call to succeed: %v", err)
+	}
+	if rsp.Say != "0s" {
+		t.Fatalf("Expected the response of the hedge, got %q", rsp.Say)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Expected the hedge to respond, took %v", d)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the first attempt to be cancelled")
+	}
+	mu.Lock()
+	if len(attempts) != 2 || attempts[0] == attempts[1] {
+		t.Fatalf("Expected two attempts to distinct nodes, got %v", attempts)
+	}
+	mu.Unlock()
+
+	// requests not marked idempotent aren't hedged
+	if _, err := call(nil, hedging, client.WithRequestTimeout(time.Millisecond*300)); err == nil {
+		t.Fatal("Expected the call which isn't idempotent to time out")
+	}
+	<-cancelled
+	mu.Lock()
+	if len(attempts) != 1 {
+		t.Fatalf("Expected a single attempt, got %v", attempts)
+	}
+	mu.Unlock()
+
+	// nor the requests sent to an address
+	address := client.WithAddress(services[0].Nodes[0].Address)
+	if _, err := call([]client.RequestOption{client.Idempotent()}, hedging, address, client.WithRequestTimeout(time.Millisecond*300)); err == nil {
+		t.Fatal("Expected the call to the address to time out")
+	}
+	<-cancelled
+	mu.Lock()
+	if len(attempts) != 1 {
+		t.Fatalf("Expected a single attempt, got %v", attempts)
+	}
+	mu.Unlock()
+}
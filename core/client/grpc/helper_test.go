@@ -0,0 +1,139 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	bmemory "github.com/lack-io/vine/core/broker/memory"
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/core/client/selector"
+	"github.com/lack-io/vine/core/registry"
+	"github.com/lack-io/vine/core/registry/memory"
+	"github.com/lack-io/vine/core/server"
+	sgrpc "github.com/lack-io/vine/core/server/grpc"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+type Message struct {
+	Say string `json:"say"`
+}
+
+// Echo responds with the request
+type Echo struct{}
+
+func (e *Echo) Say(ctx context.Context, req *Message, rsp *Message) error {
+	rsp.Say = req.Say
+	return nil
+}
+
+// Delay responds after the delay set in the request
+type Delay struct{}
+
+func (d *Delay) Say(ctx context.Context, req *Message, rsp *Message) error {
+	delay, _ := time.ParseDuration(req.Say)
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+	}
+	rsp.Say = req.Say
+	return nil
+}
+
+func (d *Delay) Stream(ctx context.Context, stream server.Stream) error {
+	req := &Message{}
+	if err := stream.Recv(req); err != nil {
+		return err
+	}
+	delay, _ := time.ParseDuration(req.Say)
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return nil
+	}
+	return stream.Send(req)
+}
+
+// newTestService starts a server of the handler registered as name in a new
+// memory registry, and returns the registry and a client created with opts
+func newTestService(t *testing.T, name string, handler interface{}, opts ...client.Option) (registry.Registry, client.Client) {
+	reg := memory.NewRegistry()
+	startTestServer(t, reg, name, handler)
+	return reg, newTestClient(reg, opts...)
+}
+
+// startTestServer starts a server of the handler registered as name in reg
+// until the end of the test
+func startTestServer(t *testing.T, reg registry.Registry, name string, handler interface{}, opts ...server.Option) {
+	srv := sgrpc.NewServer(append([]server.Option{
+		server.Name(name),
+		server.Address("127.0.0.1:0"),
+		server.Registry(reg),
+		server.Broker(bmemory.NewBroker()),
+	}, opts...)...)
+	if err := srv.Handle(srv.NewHandler(handler)); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Stop() })
+}
+
+// newTestClient returns a client selecting the nodes of reg
+func newTestClient(reg registry.Registry, opts ...client.Option) client.Client {
+	return NewClient(append([]client.Option{
+		client.Registry(reg),
+		client.Selector(selector.NewSelector(selector.Registry(reg))),
+	}, opts...)...)
+}
+
+// testServe serves s on a local port until the end of the test
+func testServe(t *testing.T, s *grpc.Server) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve(l)
+	t.Cleanup(s.Stop)
+	return l.Addr().String()
+}
+
+// newTestNode registers the address as the single node of the service in a
+// new memory registry
+func newTestNode(t *testing.T, name, address string) registry.Registry {
+	reg := memory.NewRegistry()
+	if err := reg.Register(&regpb.Service{
+		Name:    name,
+		Version: "latest",
+		Nodes:   []*regpb.Node{{Id: name + "-1", Address: address, Metadata: map[string]string{"protocol": "grpc"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
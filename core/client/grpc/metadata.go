@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/lack-io/vine/lib/logger"
+	"github.com/lack-io/vine/proto/apis/errors"
+)
+
+func (g *grpcClient) maxMetadataSizeValue() (maxMetadataSize, bool) {
+	if g.opts.Context == nil {
+		return maxMetadataSize{}, false
+	}
+	v, ok := g.opts.Context.Value(maxMetadataSizeKey{}).(maxMetadataSize)
+	if !ok || v.size <= 0 {
+		return maxMetadataSize{}, false
+	}
+	return v, true
+}
+
+// limitMetadata checks the total size of the metadata against the limit,
+// either dropping the largest entries or returning an error when exceeded
+func limitMetadata(md map[string]string, limit maxMetadataSize) error {
+	var total int
+	keys := make([]string, 0, len(md))
+	for k, v := range md {
+		total += len(k) + len(v)
+		keys = append(keys, k)
+	}
+
+	if total <= limit.size {
+		return nil
+	}
+
+	// largest entries first
+	sort.Slice(keys, func(i, j int) bool {
+		si := len(keys[i]) + len(md[keys[i]])
+		sj := len(keys[j]) + len(md[keys[j]])
+		if si == sj {
+			return keys[i] < keys[j]
+		}
+		return si > sj
+	})
+
+	// the entries which have to go to fit into the limit
+	var offending []string
+	size := total
+	for _, k := range keys {
+		if size <= limit.size {
+			break
+		}
+		size -= len(k) + len(md[k])
+		offending = append(offending, k)
+	}
+
+	if limit.mode == MetadataError {
+		return errors.BadRequest("go.vine.client", "metadata size %d exceeds limit %d: %s", total, limit.size, strings.Join(offending, ", "))
+	}
+
+	logger.Warnf("metadata size %d exceeds limit %d, dropping keys: %s", total, limit.size, strings.Join(offending, ", "))
+	for _, k := range offending {
+		delete(md, k)
+	}
+
+	return nil
+}
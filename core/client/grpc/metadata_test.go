@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLimitMetadata(t *testing.T) {
+	newMetadata := func() map[string]string {
+		return map[string]string{
+			"small": "value",
+			"large": strings.Repeat("x", 1024),
+		}
+	}
+
+	// within the limit
+	md := newMetadata()
+	if err := limitMetadata(md, maxMetadataSize{size: 4096, mode: MetadataError}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(md) != 2 {
+		t.Fatalf("Expected metadata to be untouched, got %v", md)
+	}
+
+	// drop the offending keys
+	md = newMetadata()
+	if err := limitMetadata(md, maxMetadataSize{size: 100, mode: MetadataDrop}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if _, ok := md["large"]; ok {
+		t.Fatal("Expected large key to be dropped")
+	}
+	if md["small"] != "value" {
+		t.Fatal("Expected small key to be kept")
+	}
+
+	// error out
+	md = newMetadata()
+	err := limitMetadata(md, maxMetadataSize{size: 100, mode: MetadataError})
+	if err == nil {
+		t.Fatal("Expected error for oversized metadata")
+	}
+	if !strings.Contains(err.Error(), "large") {
+		t.Fatalf("Expected error to name the offending key, got %v", err)
+	}
+}
@@ -25,6 +25,7 @@ package grpc
 import (
 	"context"
 	"crypto/tls"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/encoding"
@@ -44,16 +45,39 @@ var (
 
 	// DefaultMaxSendMsgSize maximum message that client can send
 	DefaultMaxSendMsgSize = 1024 * 1024 * 200
+
+	// DefaultDrainTimeout maximum time to wait for the in-flight calls
+	// before closing the connections of a replaced or closed pool (30s)
+	DefaultDrainTimeout = time.Second * 30
 )
 
 type poolMaxStreams struct{}
 type poolMaxIdle struct{}
+type drainTimeoutKey struct{}
 type codecsKey struct{}
 type tlsAuth struct{}
 type maxRecvMsgSizeKey struct{}
 type maxSendMsgSizeKey struct{}
 type grpcDialOptions struct{}
 type grpcCallOptions struct{}
+type maxMetadataSizeKey struct{}
+type fallbackContentTypeKey struct{}
+
+// MetadataSizeMode is the behaviour of the client when the metadata of a
+// request exceeds the configured maximum size
+type MetadataSizeMode int
+
+const (
+	// MetadataDrop drops the largest metadata entries until the metadata fits
+	MetadataDrop MetadataSizeMode = iota
+	// MetadataError fails the request
+	MetadataError
+)
+
+type maxMetadataSize struct {
+	size int
+	mode MetadataSizeMode
+}
 
 // PoolMaxStreams maximum streams on a connection
 func PoolMaxStreams(n int) client.Option {
@@ -75,6 +99,17 @@ func PoolMaxIdle(d int) client.Option {
 	}
 }
 
+// DrainTimeout maximum time to wait for the in-flight calls before closing
+// the connections when the pool is replaced by Init or the client is closed
+func DrainTimeout(d time.Duration) client.Option {
+	return func(o *client.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, drainTimeoutKey{}, d)
+	}
+}
+
 // Codec gRPC Codec to be used to encode/decode requests for a given content type
 func Codec(contentType string, c encoding.Codec) client.Option {
 	return func(o *client.Options) {
@@ -90,6 +125,18 @@ func Codec(contentType string, c encoding.Codec) client.Option {
 	}
 }
 
+// FallbackContentType enables the negotiation of the content type, the
+// requests with an unsupported content type are encoded with the fallback
+// content type instead of failing, e.g application/protobuf
+func FallbackContentType(contentType string) client.Option {
+	return func(o *client.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, fallbackContentTypeKey{}, contentType)
+	}
+}
+
 // AuthTLS should be used to setup a secure authentication using TLS
 func AuthTLS(t *tls.Config) client.Option {
 	return func(o *client.Options) {
@@ -120,6 +167,18 @@ func MaxSendMsgSize(s int) client.Option {
 	}
 }
 
+// MaxMetadataSize sets the maximum total size in bytes of the keys and values
+// of the context metadata sent with a request. Oversized metadata is dropped
+// or rejected depending on the mode
+func MaxMetadataSize(s int, mode MetadataSizeMode) client.Option {
+	return func(o *client.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, maxMetadataSizeKey{}, maxMetadataSize{size: s, mode: mode})
+	}
+}
+
 // DialOptions to be used to configure gRPC dial options
 func DialOptions(opts ...grpc.DialOption) client.CallOption {
 	return func(o *client.CallOptions) {
@@ -107,3 +107,7 @@ func (g *grpcRequest) Body() interface{} {
 func (g *grpcRequest) Stream() bool {
 	return g.opts.Stream
 }
+
+func (g *grpcRequest) Idempotent() bool {
+	return g.opts.Idempotent
+}
@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/proto/apis/errors"
+)
+
+// Overloaded fails the calls with the code of the request
+type Overloaded struct {
+	calls int32
+}
+
+func (o *Overloaded) Say(ctx context.Context, req *Message, rsp *Message) error {
+	atomic.AddInt32(&o.calls, 1)
+	if req.Say == "bad" {
+		return errors.BadRequest("go.vine.overloaded", "bad request")
+	}
+	return errors.ServiceUnavailable("go.vine.overloaded", "overloaded")
+}
+
+func TestRetryPolicy(t *testing.T) {
+	handler := &Overloaded{}
+	_, c := newTestService(t, "go.vine.overloaded", handler,
+		client.Retries(3),
+		client.Backoff(func(context.Context, client.Request, int) (time.Duration, error) {
+			return 0, nil
+		}),
+	)
+
+	call := func(say string, opts ...client.CallOption) error {
+		atomic.StoreInt32(&handler.calls, 0)
+		req := c.NewRequest("go.vine.overloaded", "Overloaded.Say", &Message{Say: say}, client.WithContentType("application/json"))
+		return c.Call(context.Background(), req, &Message{}, opts...)
+	}
+	calls := func() int32 {
+		return atomic.LoadInt32(&handler.calls)
+	}
+
+	policy := client.WithRetryPolicy(client.RetryPolicy{Budget: 0.1})
+
+	// the errors caused by the request are never retried
+	if err := call("bad", policy); err == nil || calls() != 1 {
+		t.Fatalf("Expected a single attempt, got %d: %v", calls(), err)
+	}
+
+	// the burst of the budget allows three calls to be retried three times
+	for i := 0; i < 3; i++ {
+		if err := call("", policy); err == nil || calls() != 4 {
+			t.Fatalf("Expected 4 attempts, got %d: %v", calls(), err)
+		}
+	}
+
+	// then the budget is exhausted
+	err := call("", policy)
+	if calls() != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", calls())
+	}
+	if verr := errors.FromErr(err); verr.Code != 503 || !strings.Contains(verr.Detail, "retry budget exhausted") {
+		t.Fatalf("Expected the budget to be exhausted, got %v", err)
+	}
+
+	// the Retry func still applies without policy
+	if err := call("bad", client.WithRetry(client.RetryAlways)); err == nil || calls() != 4 {
+		t.Fatalf("Expected 4 attempts, got %d: %v", calls(), err)
+	}
+}
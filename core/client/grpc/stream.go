@@ -24,12 +24,19 @@ package grpc
 
 import (
 	"context"
+	"encoding/json"
 	"io"
+	"net/http"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 
 	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/proto/apis/errors"
 )
 
 // Implements the streamer interface
@@ -43,6 +50,36 @@ type grpcStream struct {
 	response client.Response
 	ctx      context.Context
 	cancel   func()
+	// done is closed when the stream is closed
+	done chan struct{}
+	// firstResponse cancels the stream when no message is received in time
+	firstResponse *firstResponse
+}
+
+// firstResponse times the first message of a stream out, it's shared by the
+// copies of the stream
+type firstResponse struct {
+	timer    *time.Timer
+	timeout  time.Duration
+	timedOut int32
+}
+
+func newFirstResponse(timeout time.Duration, cancel func()) *firstResponse {
+	f := &firstResponse{timeout: timeout}
+	f.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&f.timedOut, 1)
+		cancel()
+	})
+	return f
+}
+
+// received stops the timer, the error is a timeout if it fired before
+func (f *firstResponse) received(err error) error {
+	f.timer.Stop()
+	if err != nil && atomic.LoadInt32(&f.timedOut) == 1 {
+		return errors.Timeout("go.vine.client", "no response within %v", f.timeout)
+	}
+	return err
 }
 
 func (g *grpcStream) Context() context.Context {
@@ -67,7 +104,11 @@ func (g *grpcStream) Send(msg interface{}) error {
 
 func (g *grpcStream) Recv(msg interface{}) (err error) {
 	defer g.setError(err)
-	if err = g.stream.RecvMsg(msg); err != nil {
+	err = g.stream.RecvMsg(msg)
+	if g.firstResponse != nil {
+		err = g.firstResponse.received(err)
+	}
+	if err != nil {
 		// #202 - inconsistent gRPC stream behavior
 		// the only way to tell if the stream is done is when we get a EOF on the Recv
 		// here we should close the underlying gRPC ClientConn
@@ -106,6 +147,119 @@ func (g *grpcStream) Close() error {
 	// cancel the context
 	g.cancel()
 	g.closed = true
+	close(g.done)
 	_ = g.stream.CloseSend()
 	return g.conn.Close()
 }
+
+// watch closes the stream, releasing its connection, when the context of
+// the stream is cancelled before the stream is closed
+func (g *grpcStream) watch() {
+	select {
+	case <-g.ctx.Done():
+		_ = g.Close()
+	case <-g.done:
+	}
+}
+
+// StreamInfo describes an open client stream
+type StreamInfo struct {
+	Id       string
+	Service  string
+	Endpoint string
+	Address  string
+	Created  time.Time
+}
+
+// Age is how long the stream has been open
+func (s StreamInfo) Age() time.Duration {
+	return time.Since(s.Created)
+}
+
+// streams tracks the open streams of a client to find the leaked ones
+type streams struct {
+	sync.RWMutex
+	open map[string]StreamInfo
+}
+
+func newStreams() *streams {
+	return &streams{open: make(map[string]StreamInfo)}
+}
+
+func (s *streams) add(service, endpoint, address string) string {
+	info := StreamInfo{
+		Id:       uuid.New().String(),
+		Service:  service,
+		Endpoint: endpoint,
+		Address:  address,
+		Created:  time.Now(),
+	}
+
+	s.Lock()
+	s.open[info.Id] = info
+	s.Unlock()
+
+	return info.Id
+}
+
+func (s *streams) remove(id string) {
+	s.Lock()
+	delete(s.open, id)
+	s.Unlock()
+}
+
+func (s *streams) list() []StreamInfo {
+	s.RLock()
+	infos := make([]StreamInfo, 0, len(s.open))
+	for _, info := range s.open {
+		infos = append(infos, info)
+	}
+	s.RUnlock()
+
+	// the oldest streams first
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Created.Before(infos[j].Created)
+	})
+
+	return infos
+}
+
+// Streams lists the open streams of a grpc client, the oldest first.
+// A long lived stream is likely leaked, never closed nor cancelled.
+func Streams(c client.Client) []StreamInfo {
+	g, ok := c.(*grpcClient)
+	if !ok {
+		return nil
+	}
+	return g.streams.list()
+}
+
+// StreamsHandler serves the open streams of a grpc client as json, the oldest
+// first, e.g. at /debug/streams of the server, see grpc.DebugHandler.
+func StreamsHandler(c client.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		type stream struct {
+			Id       string `json:"id"`
+			Service  string `json:"service"`
+			Endpoint string `json:"endpoint"`
+			Address  string `json:"address"`
+			Age      string `json:"age"`
+		}
+
+		streams := []stream{}
+		for _, info := range Streams(c) {
+			streams = append(streams, stream{
+				Id:       info.Id,
+				Service:  info.Service,
+				Endpoint: info.Endpoint,
+				Address:  info.Address,
+				Age:      info.Age().String(),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(streams); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
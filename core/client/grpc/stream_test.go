@@ -0,0 +1,136 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/core/server"
+)
+
+type Streamer struct{}
+
+// Stream blocks until the client goes away
+func (s *Streamer) Stream(ctx context.Context, stream server.Stream) error {
+	msg := &Message{}
+	if err := stream.Recv(msg); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestStreamCancel(t *testing.T) {
+	_, c := newTestService(t, "go.vine.streamer", &Streamer{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req := c.NewRequest("go.vine.streamer", "Streamer.Stream", &Message{}, client.WithContentType("application/json"), client.StreamingRequest())
+	stream, err := c.Stream(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&Message{Say: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := stream.(*grpcStream).conn
+	if state := conn.GetState(); state == connectivity.Shutdown {
+		t.Fatal("Expected the connection to be open")
+	}
+
+	// cancel the context mid-stream without closing the stream
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for conn.GetState() != connectivity.Shutdown {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the connection to be released, got %v", conn.GetState())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// closing the stream after the cancellation is a noop
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStreams(t *testing.T) {
+	_, c := newTestService(t, "go.vine.streamer", &Streamer{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var opened []client.Stream
+	for i := 0; i < 2; i++ {
+		req := c.NewRequest("go.vine.streamer", "Streamer.Stream", &Message{}, client.WithContentType("application/json"), client.StreamingRequest())
+		stream, err := c.Stream(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opened = append(opened, stream)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	streams := Streams(c)
+	if len(streams) != 2 {
+		t.Fatalf("Expected 2 open streams, got %d", len(streams))
+	}
+	for _, s := range streams {
+		if s.Service != "go.vine.streamer" || s.Endpoint != "Streamer.Stream" || len(s.Address) == 0 {
+			t.Fatalf("Unexpected stream %+v", s)
+		}
+	}
+	if streams[0].Age() <= streams[1].Age() {
+		t.Fatalf("Expected the oldest stream first, got ages %v and %v", streams[0].Age(), streams[1].Age())
+	}
+
+	// the ages grow while the streams are open
+	age := streams[0].Age()
+	time.Sleep(10 * time.Millisecond)
+	if Streams(c)[0].Age() <= age {
+		t.Fatal("Expected the age of the stream to grow")
+	}
+
+	// closed streams are no longer listed
+	for _, stream := range opened {
+		if err := stream.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(Streams(c)) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected no open streams, got %d", len(Streams(c)))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/proto/apis/errors"
+)
+
+func TestResponseTimeout(t *testing.T) {
+	_, c := newTestService(t, "go.vine.delay", &Delay{})
+
+	call := func(delay, timeout time.Duration) error {
+		req := c.NewRequest("go.vine.delay", "Delay.Say", &Message{Say: delay.String()}, client.WithContentType("application/json"))
+		return c.Call(context.Background(), req, &Message{}, client.WithResponseTimeout(timeout), client.WithRetries(0), client.WithRequestTimeout(5*time.Second))
+	}
+
+	start := time.Now()
+	err := call(time.Second, 100*time.Millisecond)
+	if verr := errors.FromErr(err); verr == nil || verr.Code != 408 {
+		t.Fatalf("Expected a timeout, got %v", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Fatalf("Expected the call to fail after the response timeout, took %v", d)
+	}
+
+	if err := call(10*time.Millisecond, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	stream := func(delay, timeout time.Duration) error {
+		req := c.NewRequest("go.vine.delay", "Delay.Stream", &Message{}, client.WithContentType("application/json"), client.StreamingRequest())
+		st, err := c.Stream(context.Background(), req, client.WithResponseTimeout(timeout), client.WithRetries(0))
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+		if err := st.Send(&Message{Say: delay.String()}); err != nil {
+			return err
+		}
+		return st.Recv(&Message{})
+	}
+
+	start = time.Now()
+	err = stream(time.Second, 100*time.Millisecond)
+	if verr := errors.FromErr(err); verr == nil || verr.Code != 408 {
+		t.Fatalf("Expected the stream to time out, got %v", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Fatalf("Expected the stream to fail after the response timeout, took %v", d)
+	}
+
+	if err := stream(10*time.Millisecond, time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
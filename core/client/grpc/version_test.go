@@ -0,0 +1,74 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/core/registry/memory"
+	"github.com/lack-io/vine/core/server"
+	"github.com/lack-io/vine/proto/apis/errors"
+)
+
+type Version struct {
+	version string
+}
+
+func (v *Version) Get(ctx context.Context, req *Message, rsp *Message) error {
+	rsp.Say = v.version
+	return nil
+}
+
+func TestCallWithVersion(t *testing.T) {
+	reg := memory.NewRegistry()
+
+	for _, version := range []string{"v1", "v2"} {
+		startTestServer(t, reg, "go.vine.version", &Version{version}, server.Version(version))
+	}
+
+	c := newTestClient(reg)
+
+	call := func(opts ...client.CallOption) (string, error) {
+		req := c.NewRequest("go.vine.version", "Version.Get", &Message{}, client.WithContentType("application/json"))
+		rsp := &Message{}
+		err := c.Call(context.Background(), req, rsp, opts...)
+		return rsp.Say, err
+	}
+
+	for i := 0; i < 10; i++ {
+		version, err := call(client.WithVersion("v2"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version != "v2" {
+			t.Fatalf("Expected the call pinned to v2, got %s", version)
+		}
+	}
+
+	_, err := call(client.WithVersion("v3"), client.WithRetries(0))
+	if verr := errors.FromErr(err); verr == nil || verr.Code != 404 {
+		t.Fatalf("Expected not found for an unknown version, got %v", err)
+	}
+}
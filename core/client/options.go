@@ -48,6 +48,9 @@ type Options struct {
 	// Connection Pool
 	PoolSize int
 	PoolTTL  time.Duration
+	// PoolAcquireTimeout bounds the pool to PoolSize connections, the calls
+	// wait up to the timeout for a connection when the pool is exhausted
+	PoolAcquireTimeout time.Duration
 
 	// Middleware for client
 	Wrappers []Wrapper
@@ -77,10 +80,27 @@ type CallOptions struct {
 	RequestTimeout time.Duration
 	// Stream timeout for the stream
 	StreamTimeout time.Duration
+	// ResponseTimeout is the time to the first response, zero waits up
+	// to the request or stream timeout
+	ResponseTimeout time.Duration
 	// Use the services own auth token
 	ServiceToken bool
 	// Duration to cache the response for
 	CacheExpiry time.Duration
+	// Tag included in the logs of the call for correlation
+	LogTag string
+	// Version of the service the call is pinned to
+	Version string
+	// Priority of the call sent to the server
+	Priority Priority
+	// CircuitBreaker skips the failing nodes, disabled when the threshold is zero
+	CircuitBreaker CircuitBreaker
+	// Compression of the request body, e.g gzip
+	Compression string
+	// RetryPolicy replaces the Retry func when set
+	RetryPolicy *RetryPolicy
+	// Hedging of the idempotent requests, disabled when the delay is zero
+	Hedging Hedging
 
 	// Middleware for low level call func
 	CallWrappers []CallWrapper
@@ -93,6 +113,8 @@ type CallOptions struct {
 type PublishOptions struct {
 	// Exchange is the routing exchange for the message
 	Exchange string
+	// Delay of the delivery of the message
+	Delay time.Duration
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
@@ -105,6 +127,8 @@ type MessageOptions struct {
 type RequestOptions struct {
 	ContentType string
 	Stream      bool
+	// Idempotent requests are safe to be sent more than once
+	Idempotent bool
 
 	// Other options for implementations of the interface
 	// can be stored in a context
@@ -173,6 +197,14 @@ func PoolTTL(d time.Duration) Option {
 	}
 }
 
+// PoolAcquireTimeout bounds the connection pool to its size, a call fails
+// when no connection is released before the timeout
+func PoolAcquireTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.PoolAcquireTimeout = d
+	}
+}
+
 // Registry to find nodes for a given service
 func Registry(r registry.Registry) Option {
 	return func(o *Options) {
@@ -189,6 +221,22 @@ func Selector(s selector.Selector) Option {
 	}
 }
 
+// Zone sets the local zone of the client, calls prefer nodes in the same zone
+func Zone(zone string) Option {
+	return func(o *Options) {
+		o.CallOptions.SelectOptions = append(o.CallOptions.SelectOptions, selector.WithFilter(selector.FilterZone(zone)))
+	}
+}
+
+// ReadinessGating excludes the nodes failing their readiness probes from
+// selection. Only nodes advertising a health endpoint in their metadata are
+// probed, by the prober shared by the process when p is nil.
+func ReadinessGating(p *selector.Prober) Option {
+	return func(o *Options) {
+		o.CallOptions.SelectOptions = append(o.CallOptions.SelectOptions, selector.WithFilter(selector.FilterReady(p)))
+	}
+}
+
 // Wrap adds a Wrapper to a list of options passed into the client
 func Wrap(w Wrapper) Option {
 	return func(o *Options) {
@@ -257,6 +305,14 @@ func WithExchange(e string) PublishOption {
 	}
 }
 
+// WithDelay delays the delivery of the message by d, see the broker for
+// the guarantees of the delayed messages
+func WithDelay(d time.Duration) PublishOption {
+	return func(o *PublishOptions) {
+		o.Delay = d
+	}
+}
+
 // PublishContext sets the context in publish options
 func PublishContext(ctx context.Context) PublishOption {
 	return func(o *PublishOptions) {
@@ -277,6 +333,32 @@ func WithSelectOption(so ...selector.SelectOption) CallOption {
 	}
 }
 
+// WithZone is a CallOption which prefers nodes in the given zone, falling
+// back to nodes in other zones when there are none in the zone
+func WithZone(zone string) CallOption {
+	return func(o *CallOptions) {
+		o.SelectOptions = append(o.SelectOptions, selector.WithFilter(selector.FilterZone(zone)))
+	}
+}
+
+// WithVersion is a CallOption which pins the call to the nodes of the
+// given version of the service, the call fails with not found when there
+// are none
+func WithVersion(v string) CallOption {
+	return func(o *CallOptions) {
+		o.Version = v
+		o.SelectOptions = append(o.SelectOptions, selector.WithFilter(selector.FilterVersion(v)))
+	}
+}
+
+// WithPriority is a CallOption which sets the priority of the call, the
+// servers limiting their concurrency admit the high priority calls first
+func WithPriority(p Priority) CallOption {
+	return func(o *CallOptions) {
+		o.Priority = p
+	}
+}
+
 // WithCallWrapper is a CallOption which adds to the existing CallFunc wrappers
 func WithCallWrapper(cw ...CallWrapper) CallOption {
 	return func(o *CallOptions) {
@@ -323,6 +405,54 @@ func WithStreamTimeout(d time.Duration) CallOption {
 	}
 }
 
+// WithResponseTimeout sets the time to the first response: the call fails
+// when the server hasn't responded in time, and the stream when it hasn't
+// sent a first message, independently of the overall timeout
+func WithResponseTimeout(d time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.ResponseTimeout = d
+	}
+}
+
+// WithCircuitBreaker is a CallOption which trips the circuit of a node after
+// threshold failures within the window, the calls skip the node until the
+// cooldown elapsed and the first failure after it trips the circuit again
+func WithCircuitBreaker(threshold int, window, cooldown time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.CircuitBreaker = CircuitBreaker{
+			Threshold: threshold,
+			Window:    window,
+			Cooldown:  cooldown,
+		}
+	}
+}
+
+// WithCompression is a CallOption which compresses the request body with
+// the named compressor, e.g gzip. The body is sent uncompressed when the
+// compressor isn't available on either side
+func WithCompression(name string) CallOption {
+	return func(o *CallOptions) {
+		o.Compression = name
+	}
+}
+
+// WithRetryPolicy is a CallOption which retries the errors with the codes of
+// the policy, within the retry budget shared by the calls of the client
+func WithRetryPolicy(p RetryPolicy) CallOption {
+	return func(o *CallOptions) {
+		o.RetryPolicy = &p
+	}
+}
+
+// WithHedging is a CallOption which sends the idempotent request to up to
+// maxHedges further nodes, one each time no response arrived within the delay.
+// The first response is used and the other attempts are cancelled
+func WithHedging(delay time.Duration, maxHedges int) CallOption {
+	return func(o *CallOptions) {
+		o.Hedging = Hedging{Delay: delay, Max: maxHedges}
+	}
+}
+
 // WithDialTimeout is a CallOption which overrides that which
 // set in Options.CallOptions
 func WithDialTimeout(d time.Duration) CallOption {
@@ -347,6 +477,14 @@ func WithCache(c time.Duration) CallOption {
 	}
 }
 
+// WithLogTag is a CallOption which tags the call, the tag is sent in the
+// metadata and included in the logs of the call by the client and the server
+func WithLogTag(tag string) CallOption {
+	return func(o *CallOptions) {
+		o.LogTag = tag
+	}
+}
+
 func WithMessageContentType(ct string) MessageOption {
 	return func(o *MessageOptions) {
 		o.ContentType = ct
@@ -367,6 +505,13 @@ func StreamingRequest() RequestOption {
 	}
 }
 
+// Idempotent marks the request as safe to be sent more than once
+func Idempotent() RequestOption {
+	return func(o *RequestOptions) {
+		o.Idempotent = true
+	}
+}
+
 // WithRouter sets the client router
 func WithRouter(r Router) Option {
 	return func(o *Options) {
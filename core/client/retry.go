@@ -24,6 +24,7 @@ package client
 
 import (
 	"context"
+	"sync"
 
 	"github.com/lack-io/vine/proto/apis/errors"
 )
@@ -55,3 +56,89 @@ func RetryOnError(ctx context.Context, req Request, retryCount int, err error) (
 		return false, nil
 	}
 }
+
+var (
+	// DefaultRetryCodes are the codes retried by the policies without codes:
+	// the timeouts and the unavailable backends
+	DefaultRetryCodes = []int32{408, 502, 503, 504}
+
+	// DefaultRetryBudgetBurst is the number of retries a budget allows
+	// before any call was made, and the most it accumulates
+	DefaultRetryBudgetBurst = 10.0
+)
+
+// RetryPolicy retries the errors with its codes while the retry budget of
+// the client allows it
+type RetryPolicy struct {
+	// Codes are the retried error codes, DefaultRetryCodes when empty
+	Codes []int32
+	// Budget is the maximum ratio of retries to calls, e.g 0.1 allows
+	// retrying 10% of the calls. Zero doesn't bound the retries
+	Budget float64
+}
+
+// Retryable reports whether the policy retries the error
+func (p *RetryPolicy) Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	codes := p.Codes
+	if len(codes) == 0 {
+		codes = DefaultRetryCodes
+	}
+
+	e := errors.FromErr(err)
+	for _, code := range codes {
+		if e.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryBudget is a token bucket shared by the calls of a client, each call
+// deposits the budget ratio of a token and each retry withdraws a token
+type RetryBudget struct {
+	sync.Mutex
+	tokens float64
+}
+
+// NewRetryBudget returns a budget holding DefaultRetryBudgetBurst tokens
+func NewRetryBudget() *RetryBudget {
+	return &RetryBudget{tokens: DefaultRetryBudgetBurst}
+}
+
+// Deposit adds the ratio of a token for a call
+func (b *RetryBudget) Deposit(ratio float64) {
+	b.Lock()
+	defer b.Unlock()
+	b.tokens += ratio
+	if b.tokens > DefaultRetryBudgetBurst {
+		b.tokens = DefaultRetryBudgetBurst
+	}
+}
+
+// Withdraw takes a token for a retry, it reports false when the budget is
+// exhausted
+func (b *RetryBudget) Withdraw() bool {
+	b.Lock()
+	defer b.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// RetryBudgetExhausted annotates the error of a call which wasn't retried
+// for lack of retry budget
+func RetryBudgetExhausted(err error) error {
+	e := errors.FromErr(err)
+	return &errors.Error{
+		Id:     e.Id,
+		Code:   e.Code,
+		Detail: "retry budget exhausted: " + e.Detail,
+		Status: e.Status,
+	}
+}
@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lack-io/vine/proto/apis/errors"
+)
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	p := &RetryPolicy{}
+
+	testData := []struct {
+		err   error
+		retry bool
+	}{
+		{nil, false},
+		{errors.Timeout("go.vine.test", "timeout"), true},
+		{errors.ServiceUnavailable("go.vine.test", "overloaded"), true},
+		{errors.InternalServerError("go.vine.test", "failure"), false},
+		{errors.BadRequest("go.vine.test", "bad"), false},
+		{errors.Unauthorized("go.vine.test", "unauthorized"), false},
+		{errors.Forbidden("go.vine.test", "forbidden"), false},
+	}
+	for _, td := range testData {
+		if retry := p.Retryable(td.err); retry != td.retry {
+			t.Fatalf("Expected retryable %v for %v", td.retry, td.err)
+		}
+	}
+
+	p = &RetryPolicy{Codes: []int32{500}}
+	if !p.Retryable(errors.InternalServerError("go.vine.test", "failure")) || p.Retryable(errors.Timeout("go.vine.test", "timeout")) {
+		t.Fatal("Expected only the codes of the policy to be retried")
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	b := NewRetryBudget()
+
+	// the burst is available at start
+	for i := 0; i < int(DefaultRetryBudgetBurst); i++ {
+		if !b.Withdraw() {
+			t.Fatalf("Expected retry %d to be allowed", i)
+		}
+	}
+	if b.Withdraw() {
+		t.Fatal("Expected the budget to be exhausted")
+	}
+
+	// 4 calls at 25% allow a retry
+	for i := 0; i < 3; i++ {
+		b.Deposit(0.25)
+	}
+	if b.Withdraw() {
+		t.Fatal("Expected the budget to be exhausted")
+	}
+	b.Deposit(0.25)
+	if !b.Withdraw() {
+		t.Fatal("Expected a retry to be allowed")
+	}
+
+	// the budget doesn't grow beyond the burst
+	for i := 0; i < 1000; i++ {
+		b.Deposit(1)
+	}
+	if b.tokens != DefaultRetryBudgetBurst {
+		t.Fatalf("Expected %v tokens, got %v", DefaultRetryBudgetBurst, b.tokens)
+	}
+
+	err := RetryBudgetExhausted(errors.ServiceUnavailable("go.vine.test", "overloaded"))
+	if verr := errors.FromErr(err); verr.Code != 503 || !strings.Contains(verr.Detail, "retry budget exhausted") {
+		t.Fatalf("Unexpected error %v", err)
+	}
+}
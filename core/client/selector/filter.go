@@ -93,3 +93,55 @@ func FilterVersion(version string) Filter {
 		return services
 	}
 }
+
+// FilterZone is a zone aware Select Filter which prefers nodes with the
+// zone metadata matching the given zone. When no node is in the zone all
+// the nodes are returned, so that calls fall back to the other zones.
+func FilterZone(zone string) Filter {
+	local := FilterLabel("zone", zone)
+	return func(old []*regpb.Service) []*regpb.Service {
+		if len(zone) == 0 {
+			return old
+		}
+
+		if services := local(old); len(services) > 0 {
+			return services
+		}
+
+		return old
+	}
+}
+
+// FilterReady is a readiness based Select Filter which excludes the nodes
+// failing the readiness probes of the Prober. The DefaultProber is used
+// when p is nil.
+func FilterReady(p *Prober) Filter {
+	if p == nil {
+		p = DefaultProber()
+	}
+
+	return func(old []*regpb.Service) []*regpb.Service {
+		var services []*regpb.Service
+
+		for _, service := range old {
+			serv := new(regpb.Service)
+			var nodes []*regpb.Node
+
+			for _, node := range service.Nodes {
+				if p.Ready(node) {
+					nodes = append(nodes, node)
+				}
+			}
+
+			// only add service if there's some nodes
+			if len(nodes) > 0 {
+				// copy
+				*serv = *service
+				serv.Nodes = nodes
+				services = append(services, serv)
+			}
+		}
+
+		return services
+	}
+}
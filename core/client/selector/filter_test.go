@@ -0,0 +1,171 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package selector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+func TestFilterZone(t *testing.T) {
+	testData := []struct {
+		services []*regpb.Service
+		zone     string
+		nodes    []string
+	}{
+		{
+			services: []*regpb.Service{
+				{
+					Name:    "test",
+					Version: "1.0.0",
+					Nodes: []*regpb.Node{
+						{Id: "a", Address: "10.0.0.1:8080", Metadata: map[string]string{"zone": "us-east-1a"}},
+						{Id: "b", Address: "10.0.0.2:8080", Metadata: map[string]string{"zone": "us-east-1b"}},
+					},
+				},
+			},
+			zone:  "us-east-1a",
+			nodes: []string{"a"},
+		},
+		{
+			services: []*regpb.Service{
+				{
+					Name:    "test",
+					Version: "1.0.0",
+					Nodes: []*regpb.Node{
+						{Id: "a", Address: "10.0.0.1:8080", Metadata: map[string]string{"zone": "us-east-1b"}},
+						{Id: "b", Address: "10.0.0.2:8080"},
+					},
+				},
+			},
+			zone:  "us-east-1a",
+			nodes: []string{"a", "b"},
+		},
+		{
+			services: []*regpb.Service{
+				{
+					Name:    "test",
+					Version: "1.0.0",
+					Nodes: []*regpb.Node{
+						{Id: "a", Address: "10.0.0.1:8080", Metadata: map[string]string{"zone": "us-east-1b"}},
+					},
+				},
+			},
+			zone:  "",
+			nodes: []string{"a"},
+		},
+	}
+
+	for _, data := range testData {
+		filter := FilterZone(data.zone)
+		services := filter(data.services)
+
+		var nodes []string
+		for _, service := range services {
+			for _, node := range service.Nodes {
+				nodes = append(nodes, node.Id)
+			}
+		}
+
+		if len(nodes) != len(data.nodes) {
+			t.Fatalf("Expected nodes %v, got %v", data.nodes, nodes)
+		}
+		for i := range nodes {
+			if nodes[i] != data.nodes[i] {
+				t.Fatalf("Expected nodes %v, got %v", data.nodes, nodes)
+			}
+		}
+	}
+}
+
+func TestFilterReady(t *testing.T) {
+	var mu sync.Mutex
+	failing := map[string]bool{"10.0.0.1:8080": true}
+
+	p := NewProber(
+		ProbeInterval(time.Millisecond*10),
+		ProbeFailureThreshold(2),
+		Probe(func(ctx context.Context, node *regpb.Node) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if failing[node.Address] {
+				return errors.New("not ready")
+			}
+			return nil
+		}),
+	)
+	defer p.Stop()
+
+	services := []*regpb.Service{
+		{
+			Name:    "test",
+			Version: "1.0.0",
+			Nodes: []*regpb.Node{
+				{Id: "a", Address: "10.0.0.1:8080", Metadata: map[string]string{"health": "grpc"}},
+				{Id: "b", Address: "10.0.0.2:8080", Metadata: map[string]string{"health": "grpc"}},
+				{Id: "c", Address: "10.0.0.3:8080"},
+			},
+		},
+	}
+
+	filter := FilterReady(p)
+	nodes := func() []string {
+		var ids []string
+		for _, service := range filter(services) {
+			for _, node := range service.Nodes {
+				ids = append(ids, node.Id)
+			}
+		}
+		return ids
+	}
+
+	// nodes are ready until they failed enough probes
+	if ids := nodes(); len(ids) != 3 {
+		t.Fatalf("Expected all nodes to be selected, got %v", ids)
+	}
+
+	wait := func(expected string) {
+		for i := 0; i < 100; i++ {
+			if fmt.Sprint(nodes()) == expected {
+				return
+			}
+			time.Sleep(time.Millisecond * 10)
+		}
+		t.Fatalf("Expected nodes %s, got %v", expected, nodes())
+	}
+
+	wait("[b c]")
+
+	// recovered nodes are selected again
+	mu.Lock()
+	failing["10.0.0.1:8080"] = false
+	mu.Unlock()
+
+	wait("[a b c]")
+}
@@ -0,0 +1,242 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package selector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+var (
+	// DefaultProbeInterval is the time between two readiness probes of a node
+	DefaultProbeInterval = time.Second * 10
+	// DefaultProbeTimeout is the timeout of a single readiness probe
+	DefaultProbeTimeout = time.Second * 2
+	// DefaultProbeFailureThreshold is the number of consecutive failed
+	// probes after which a node is excluded from selection
+	DefaultProbeFailureThreshold = 3
+
+	defaultProber     *Prober
+	defaultProberOnce sync.Once
+)
+
+// ProbeFunc checks the readiness of the node
+type ProbeFunc func(ctx context.Context, node *regpb.Node) error
+
+// ProberOptions configures the Prober
+type ProberOptions struct {
+	Interval         time.Duration
+	Timeout          time.Duration
+	FailureThreshold int
+	Probe            ProbeFunc
+}
+
+// ProberOption sets an option of the Prober
+type ProberOption func(*ProberOptions)
+
+// ProbeInterval sets the time between two probes of a node
+func ProbeInterval(d time.Duration) ProberOption {
+	return func(o *ProberOptions) {
+		o.Interval = d
+	}
+}
+
+// ProbeTimeout sets the timeout of a single probe
+func ProbeTimeout(d time.Duration) ProberOption {
+	return func(o *ProberOptions) {
+		o.Timeout = d
+	}
+}
+
+// ProbeFailureThreshold sets the number of consecutive failed probes after
+// which a node is considered not ready
+func ProbeFailureThreshold(n int) ProberOption {
+	return func(o *ProberOptions) {
+		o.FailureThreshold = n
+	}
+}
+
+// Probe sets the function used to probe the nodes, defaults to the grpc health check
+func Probe(fn ProbeFunc) ProberOption {
+	return func(o *ProberOptions) {
+		o.Probe = fn
+	}
+}
+
+type probeState struct {
+	node     *regpb.Node
+	failures int
+	lastSeen time.Time
+}
+
+// Prober probes the readiness of the nodes advertising a health endpoint in
+// their "health" metadata. Probe results are cached per node address, so a
+// single Prober is shared by every service calling the same node.
+type Prober struct {
+	opts ProberOptions
+
+	sync.RWMutex
+	nodes   map[string]*probeState
+	started bool
+	exit    chan bool
+}
+
+// NewProber returns a Prober, the probes start with the first watched node
+func NewProber(opts ...ProberOption) *Prober {
+	options := ProberOptions{
+		Interval:         DefaultProbeInterval,
+		Timeout:          DefaultProbeTimeout,
+		FailureThreshold: DefaultProbeFailureThreshold,
+		Probe:            grpcHealthProbe,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &Prober{
+		opts:  options,
+		nodes: make(map[string]*probeState),
+		exit:  make(chan bool),
+	}
+}
+
+// DefaultProber returns the Prober shared by the process
+func DefaultProber() *Prober {
+	defaultProberOnce.Do(func() {
+		defaultProber = NewProber()
+	})
+	return defaultProber
+}
+
+// Ready reports whether the node is ready to serve requests and watches
+// the node for subsequent probes. Nodes without health metadata are always
+// ready, as are nodes which haven't failed enough probes yet.
+func (p *Prober) Ready(node *regpb.Node) bool {
+	if len(node.Metadata["health"]) == 0 {
+		return true
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.started {
+		p.started = true
+		go p.run()
+	}
+
+	state, ok := p.nodes[node.Address]
+	if !ok {
+		state = &probeState{node: node}
+		p.nodes[node.Address] = state
+	}
+	state.lastSeen = time.Now()
+
+	return state.failures < p.opts.FailureThreshold
+}
+
+// Stop stops probing the nodes
+func (p *Prober) Stop() {
+	p.Lock()
+	defer p.Unlock()
+
+	select {
+	case <-p.exit:
+	default:
+		close(p.exit)
+	}
+}
+
+func (p *Prober) run() {
+	t := time.NewTicker(p.opts.Interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.exit:
+			return
+		case <-t.C:
+			p.probe()
+		}
+	}
+}
+
+func (p *Prober) probe() {
+	p.Lock()
+	states := make([]*probeState, 0, len(p.nodes))
+	for addr, state := range p.nodes {
+		// forget nodes nobody asked about for a while
+		if time.Since(state.lastSeen) > p.opts.Interval*time.Duration(p.opts.FailureThreshold+10) {
+			delete(p.nodes, addr)
+			continue
+		}
+		states = append(states, state)
+	}
+	p.Unlock()
+
+	var wg sync.WaitGroup
+	for _, state := range states {
+		wg.Add(1)
+		go func(state *probeState) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), p.opts.Timeout)
+			err := p.opts.Probe(ctx, state.node)
+			cancel()
+
+			p.Lock()
+			if err != nil {
+				state.failures++
+			} else {
+				state.failures = 0
+			}
+			p.Unlock()
+		}(state)
+	}
+	wg.Wait()
+}
+
+// grpcHealthProbe probes the node with the grpc health checking protocol
+func grpcHealthProbe(ctx context.Context, node *regpb.Node) error {
+	cc, err := grpc.DialContext(ctx, node.Address, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	rsp, err := grpc_health_v1.NewHealthClient(cc).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if rsp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("node %s is %s", node.Address, rsp.Status)
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ package selector
 
 import (
 	"math/rand"
+	"strconv"
 	"sync"
 	"time"
 
@@ -76,3 +77,73 @@ func RoundRobin(services []*regpb.Service) Next {
 		return node, nil
 	}
 }
+
+// WeightedRoundRobin is a smooth weighted roundrobin strategy algorithm for
+// node selection, a node gets a share of the selections proportional to the
+// "weight" of its metadata, the nodes without a valid weight weigh 1
+func WeightedRoundRobin(services []*regpb.Service) Next {
+	nodes := make([]*regpb.Node, 0, len(services))
+
+	for _, service := range services {
+		nodes = append(nodes, service.Nodes...)
+	}
+
+	weights := make([]int, len(nodes))
+	current := make([]int, len(nodes))
+	total := 0
+	for i, node := range nodes {
+		weights[i] = weightOf(node)
+		total += weights[i]
+	}
+
+	// start with a node drawn at random by weight, the strategy is applied
+	// to each selection and would otherwise always start with the heaviest node
+	start := -1
+	if total > 0 {
+		n := rand.Intn(total)
+		for i, weight := range weights {
+			if n < weight {
+				start = i
+				break
+			}
+			n -= weight
+		}
+	}
+
+	var mtx sync.Mutex
+
+	next := func() *regpb.Node {
+		best := 0
+		for i := range nodes {
+			current[i] += weights[i]
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+		if start >= 0 {
+			best, start = start, -1
+		}
+		current[best] -= total
+		return nodes[best]
+	}
+
+	return func() (*regpb.Node, error) {
+		if len(nodes) == 0 {
+			return nil, ErrNoneAvailable
+		}
+
+		mtx.Lock()
+		node := next()
+		mtx.Unlock()
+
+		return node, nil
+	}
+}
+
+func weightOf(node *regpb.Node) int {
+	weight, err := strconv.Atoi(node.Metadata["weight"])
+	if err != nil || weight < 1 {
+		return 1
+	}
+	return weight
+}
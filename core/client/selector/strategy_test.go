@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package selector
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+func TestWeightedRoundRobin(t *testing.T) {
+	services := []*regpb.Service{
+		{
+			Name:    "test",
+			Version: "1.0.0",
+			Nodes: []*regpb.Node{
+				{Id: "heavy", Address: "10.0.0.1:8080", Metadata: map[string]string{"weight": "5"}},
+				{Id: "light", Address: "10.0.0.2:8080", Metadata: map[string]string{"weight": "1"}},
+			},
+		},
+		{
+			Name:    "test",
+			Version: "1.0.1",
+			Nodes: []*regpb.Node{
+				{Id: "malformed", Address: "10.0.0.3:8080", Metadata: map[string]string{"weight": "heavy"}},
+				{Id: "negative", Address: "10.0.0.4:8080", Metadata: map[string]string{"weight": "-3"}},
+				{Id: "missing", Address: "10.0.0.5:8080"},
+			},
+		},
+	}
+	expected := map[string]int{"heavy": 5, "light": 1, "malformed": 1, "negative": 1, "missing": 1}
+
+	// a selection cycles through the nodes proportionally
+	next := WeightedRoundRobin(services)
+	counts := map[string]int{}
+	for i := 0; i < 9*100; i++ {
+		node, err := next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[node.Id]++
+	}
+	for id, weight := range expected {
+		if counts[id] != weight*100 {
+			t.Fatalf("Expected %d selections of %s, got %d", weight*100, id, counts[id])
+		}
+	}
+
+	// and so do the first nodes of the separate selections
+	counts = map[string]int{}
+	for i := 0; i < 9*1000; i++ {
+		node, err := WeightedRoundRobin(services)()
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[node.Id]++
+	}
+	for id, weight := range expected {
+		if c := counts[id]; c < weight*800 || c > weight*1200 {
+			t.Fatalf("Expected about %d selections of %s, got %d", weight*1000, id, c)
+		}
+	}
+
+	if _, err := WeightedRoundRobin(nil)(); err != ErrNoneAvailable {
+		t.Fatalf("Expected ErrNoneAvailable, got %v", err)
+	}
+}
+
+func TestWeightedRoundRobinLargeWeights(t *testing.T) {
+	service := &regpb.Service{Name: "test", Version: "1.0.0"}
+	for i := 0; i < 48; i++ {
+		service.Nodes = append(service.Nodes, &regpb.Node{
+			Id:       fmt.Sprintf("node-%d", i),
+			Address:  fmt.Sprintf("10.0.0.%d:8080", i+1),
+			Metadata: map[string]string{"weight": "100000"},
+		})
+	}
+	services := []*regpb.Service{service}
+
+	// a selection costs a draw over the nodes, not a walk of the total weight
+	counts := map[string]int{}
+	start := time.Now()
+	for i := 0; i < 48*100; i++ {
+		node, err := WeightedRoundRobin(services)()
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[node.Id]++
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Expected the selections to take less than a second, took %v", d)
+	}
+	if len(counts) != len(service.Nodes) {
+		t.Fatalf("Expected the selections to start at all %d nodes, started at %d", len(service.Nodes), len(counts))
+	}
+
+	// the following selections cycle through the equally weighted nodes
+	next := WeightedRoundRobin(services)
+	seen := map[string]bool{}
+	for i := 0; i < len(service.Nodes); i++ {
+		node, err := next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[node.Id] {
+			t.Fatalf("Expected %s to be selected once per cycle", node.Id)
+		}
+		seen[node.Id] = true
+	}
+}
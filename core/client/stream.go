@@ -0,0 +1,306 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/lack-io/vine/proto/apis/errors"
+	"github.com/lack-io/vine/util/backoff"
+)
+
+// StreamState is the connection state of a reconnecting stream
+type StreamState int
+
+const (
+	// StreamConnected the stream is established
+	StreamConnected StreamState = iota
+	// StreamReconnecting the stream failed and is being re-established
+	StreamReconnecting
+	// StreamClosed the stream is closed, either by the caller or by a permanent error
+	StreamClosed
+)
+
+func (s StreamState) String() string {
+	switch s {
+	case StreamConnected:
+		return "connected"
+	case StreamReconnecting:
+		return "reconnecting"
+	case StreamClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// StreamFactory opens a stream and sends the initial request if any. token is
+// the resume token of the last message received, empty the first time.
+type StreamFactory func(ctx context.Context, token string) (Stream, error)
+
+type ReconnectOptions struct {
+	// Token extracts the resume token of a received message e.g the last event id
+	Token func(msg interface{}) string
+	// Retry checks if the stream is re-established after the error
+	Retry func(err error) bool
+	// Backoff returns the wait before the given reconnection attempt
+	Backoff func(attempts int) time.Duration
+	// Attempts is the number of reconnection attempts, 0 for no limit
+	Attempts int
+	// StateChange is called when the connection state changes
+	StateChange func(state StreamState, err error)
+}
+
+type ReconnectOption func(*ReconnectOptions)
+
+// ResumeToken sets the function extracting the resume token of the messages
+func ResumeToken(fn func(msg interface{}) string) ReconnectOption {
+	return func(o *ReconnectOptions) {
+		o.Token = fn
+	}
+}
+
+// ReconnectRetry sets the function checking if the stream is re-established
+func ReconnectRetry(fn func(err error) bool) ReconnectOption {
+	return func(o *ReconnectOptions) {
+		o.Retry = fn
+	}
+}
+
+// ReconnectBackoff sets the backoff between the reconnection attempts
+func ReconnectBackoff(fn func(attempts int) time.Duration) ReconnectOption {
+	return func(o *ReconnectOptions) {
+		o.Backoff = fn
+	}
+}
+
+// ReconnectAttempts sets the number of reconnection attempts, 0 for no limit
+func ReconnectAttempts(n int) ReconnectOption {
+	return func(o *ReconnectOptions) {
+		o.Attempts = n
+	}
+}
+
+// OnStateChange sets the callback of the connection state e.g for logging
+func OnStateChange(fn func(state StreamState, err error)) ReconnectOption {
+	return func(o *ReconnectOptions) {
+		o.StateChange = fn
+	}
+}
+
+// RetryStream is the default check of reconnecting streams. The end of the
+// stream, a done context and the client errors are permanent, anything else
+// e.g a network error or a server error is retried.
+func RetryStream(err error) bool {
+	if err == nil || err == io.EOF || err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+
+	e := errors.Parse(err.Error())
+	if e.Code >= 400 && e.Code < 500 && e.Code != 408 {
+		return false
+	}
+
+	return true
+}
+
+type reconnectStream struct {
+	sync.RWMutex
+	ctx     context.Context
+	factory StreamFactory
+	opts    ReconnectOptions
+	stream  Stream
+	token   string
+	err     error
+	closed  bool
+}
+
+// NewReconnectingStream opens a stream with the factory and transparently
+// re-opens it when it fails with a retryable error, passing the resume token
+// of the last message received so the server can resume where it stopped.
+// Send is not retried, the requests needed to set up the stream belong in
+// the factory.
+func NewReconnectingStream(ctx context.Context, fn StreamFactory, opts ...ReconnectOption) (Stream, error) {
+	options := ReconnectOptions{
+		Retry:   RetryStream,
+		Backoff: backoff.Do,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	stream, err := fn(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &reconnectStream{
+		ctx:     ctx,
+		factory: fn,
+		opts:    options,
+		stream:  stream,
+	}
+	s.state(StreamConnected, nil)
+
+	return s, nil
+}
+
+func (s *reconnectStream) state(state StreamState, err error) {
+	if s.opts.StateChange != nil {
+		s.opts.StateChange(state, err)
+	}
+}
+
+func (s *reconnectStream) current() Stream {
+	s.RLock()
+	defer s.RUnlock()
+	return s.stream
+}
+
+func (s *reconnectStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *reconnectStream) Request() Request {
+	return s.current().Request()
+}
+
+func (s *reconnectStream) Response() Response {
+	return s.current().Response()
+}
+
+func (s *reconnectStream) Send(msg interface{}) error {
+	return s.current().Send(msg)
+}
+
+func (s *reconnectStream) Recv(msg interface{}) error {
+	for {
+		stream := s.current()
+
+		err := stream.Recv(msg)
+		if err == nil {
+			if s.opts.Token != nil {
+				if token := s.opts.Token(msg); len(token) > 0 {
+					s.Lock()
+					s.token = token
+					s.Unlock()
+				}
+			}
+			return nil
+		}
+
+		if err := s.reconnect(stream, err); err != nil {
+			return err
+		}
+	}
+}
+
+// reconnect replaces the failed stream, it returns the error to surface to
+// the caller when the stream can't be re-established
+func (s *reconnectStream) reconnect(failed Stream, err error) error {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return io.EOF
+	}
+	s.Unlock()
+
+	if s.ctx.Err() != nil || !s.opts.Retry(err) {
+		return s.fail(err)
+	}
+
+	s.state(StreamReconnecting, err)
+	failed.Close()
+
+	for attempt := 1; s.opts.Attempts == 0 || attempt <= s.opts.Attempts; attempt++ {
+		select {
+		case <-s.ctx.Done():
+			return s.fail(s.ctx.Err())
+		case <-time.After(s.opts.Backoff(attempt)):
+		}
+
+		s.RLock()
+		token := s.token
+		s.RUnlock()
+
+		stream, nerr := s.factory(s.ctx, token)
+		if nerr != nil {
+			err = nerr
+			if !s.opts.Retry(err) {
+				return s.fail(err)
+			}
+			s.state(StreamReconnecting, err)
+			continue
+		}
+
+		s.Lock()
+		if s.closed {
+			s.Unlock()
+			stream.Close()
+			return io.EOF
+		}
+		s.stream = stream
+		s.Unlock()
+
+		s.state(StreamConnected, nil)
+		return nil
+	}
+
+	return s.fail(err)
+}
+
+func (s *reconnectStream) fail(err error) error {
+	s.Lock()
+	s.err = err
+	s.closed = true
+	s.Unlock()
+
+	s.state(StreamClosed, err)
+	return err
+}
+
+func (s *reconnectStream) Error() error {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return s.err
+	}
+	return s.stream.Error()
+}
+
+func (s *reconnectStream) Close() error {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return nil
+	}
+	s.closed = true
+	stream := s.stream
+	s.Unlock()
+
+	s.state(StreamClosed, nil)
+	return stream.Close()
+}
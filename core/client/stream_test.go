@@ -0,0 +1,164 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	verrors "github.com/lack-io/vine/proto/apis/errors"
+)
+
+type testStream struct {
+	Stream
+	msgs   []string
+	err    error
+	closed bool
+}
+
+func (t *testStream) Recv(msg interface{}) error {
+	if len(t.msgs) == 0 {
+		return t.err
+	}
+	*(msg.(*string)) = t.msgs[0]
+	t.msgs = t.msgs[1:]
+	return nil
+}
+
+func (t *testStream) Close() error {
+	t.closed = true
+	return nil
+}
+
+func TestReconnectingStream(t *testing.T) {
+	streams := []*testStream{
+		{msgs: []string{"1", "2"}, err: errors.New("connection reset")},
+		{msgs: []string{"3"}, err: io.EOF},
+	}
+	var tokens []string
+	var states []StreamState
+
+	fn := func(ctx context.Context, token string) (Stream, error) {
+		tokens = append(tokens, token)
+		s := streams[0]
+		streams = streams[1:]
+		return s, nil
+	}
+
+	first := streams[0]
+	s, err := NewReconnectingStream(context.TODO(), fn,
+		ResumeToken(func(msg interface{}) string { return *(msg.(*string)) }),
+		ReconnectBackoff(func(int) time.Duration { return 0 }),
+		OnStateChange(func(state StreamState, err error) { states = append(states, state) }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for {
+		var msg string
+		if err := s.Recv(&msg); err != nil {
+			if err != io.EOF {
+				t.Fatalf("Expected io.EOF, got %v", err)
+			}
+			break
+		}
+		got = append(got, msg)
+	}
+
+	if len(got) != 3 || got[2] != "3" {
+		t.Fatalf("Expected messages of both streams, got %v", got)
+	}
+	if len(tokens) != 2 || tokens[0] != "" || tokens[1] != "2" {
+		t.Fatalf("Expected the resume token of the last message, got %v", tokens)
+	}
+	if !first.closed {
+		t.Fatal("Expected the failed stream to be closed")
+	}
+	expected := []StreamState{StreamConnected, StreamReconnecting, StreamConnected, StreamClosed}
+	if len(states) != len(expected) {
+		t.Fatalf("Expected states %v, got %v", expected, states)
+	}
+	for i := range expected {
+		if states[i] != expected[i] {
+			t.Fatalf("Expected states %v, got %v", expected, states)
+		}
+	}
+}
+
+func TestReconnectingStreamPermanentError(t *testing.T) {
+	var opened int
+	fn := func(ctx context.Context, token string) (Stream, error) {
+		opened++
+		return &testStream{err: verrors.Forbidden("go.vine.client", "denied")}, nil
+	}
+
+	s, err := NewReconnectingStream(context.TODO(), fn, ReconnectBackoff(func(int) time.Duration { return 0 }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var msg string
+	err = s.Recv(&msg)
+	if e, ok := err.(*verrors.Error); !ok || e.Code != 403 {
+		t.Fatalf("Expected the permanent error, got %v", err)
+	}
+	if opened != 1 {
+		t.Fatalf("Expected no reconnection, got %d streams", opened)
+	}
+	if s.Error() != err {
+		t.Fatalf("Expected stream error %v, got %v", err, s.Error())
+	}
+}
+
+func TestReconnectingStreamAttempts(t *testing.T) {
+	fail := errors.New("connection refused")
+	var opened int
+	fn := func(ctx context.Context, token string) (Stream, error) {
+		opened++
+		if opened > 1 {
+			return nil, fail
+		}
+		return &testStream{err: fail}, nil
+	}
+
+	s, err := NewReconnectingStream(context.TODO(), fn,
+		ReconnectAttempts(3),
+		ReconnectBackoff(func(int) time.Duration { return 0 }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var msg string
+	if err := s.Recv(&msg); err != fail {
+		t.Fatalf("Expected %v, got %v", fail, err)
+	}
+	if opened != 4 {
+		t.Fatalf("Expected 3 reconnection attempts, got %d", opened-1)
+	}
+}
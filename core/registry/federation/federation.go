@@ -0,0 +1,450 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package federation mirrors a subset of the services of the local registry into the registries of other regions
+package federation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/lack-io/vine/core/registry"
+	log "github.com/lack-io/vine/lib/logger"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+	"github.com/lack-io/vine/util/backoff"
+)
+
+// FederatedFrom is the metadata of the mirrored nodes holding the region they come from.
+// Nodes carrying it are never federated again.
+const FederatedFrom = "federated-from"
+
+// Status is the health of the federation with a remote registry
+type Status struct {
+	// Remote is the name of the remote registry
+	Remote string
+	// Healthy is false when the last sync failed
+	Healthy bool
+	// Services is the number of services mirrored into the remote
+	Services int
+	// Syncs is the number of successful syncs
+	Syncs int64
+	// Failures is the number of failed syncs
+	Failures int64
+	// LastSync is the time of the last successful sync
+	LastSync time.Time
+	// Error is the error of the last failed sync
+	Error string
+}
+
+// Federation watches the local registry and mirrors the matching services
+// into the remote registries with a short ttl it keeps refreshing
+type Federation struct {
+	opts  Options
+	local registry.Registry
+
+	sync.RWMutex
+	// services to mirror by name and version
+	services map[string]*regpb.Service
+	// mirrored services per remote
+	mirrored map[string]map[string]*regpb.Service
+	status   map[string]*Status
+	// last successful read of the local registry
+	seen time.Time
+
+	running bool
+	exit    chan bool
+	done    chan bool
+	trigger chan bool
+}
+
+// NewFederation returns a federation of the local registry
+func NewFederation(local registry.Registry, opts ...Option) (*Federation, error) {
+	options := newOptions(opts...)
+	if options.TTL <= 0 {
+		return nil, fmt.Errorf("invalid federation ttl %v, expected a positive duration", options.TTL)
+	}
+
+	f := &Federation{
+		opts:     options,
+		local:    local,
+		services: make(map[string]*regpb.Service),
+		mirrored: make(map[string]map[string]*regpb.Service),
+		status:   make(map[string]*Status),
+	}
+
+	for _, r := range f.opts.Remotes {
+		f.mirrored[r.Name] = make(map[string]*regpb.Service)
+		f.status[r.Name] = &Status{Remote: r.Name}
+	}
+
+	return f, nil
+}
+
+func key(s *regpb.Service) string {
+	return s.Name + ":" + s.Version
+}
+
+// match checks if the service is federated by name
+func (f *Federation) match(name string) bool {
+	if len(f.opts.Prefixes) == 0 {
+		return true
+	}
+	for _, p := range f.opts.Prefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Federation) labeled(md map[string]string) bool {
+	if len(f.opts.Labels) == 0 {
+		return true
+	}
+	for k, v := range f.opts.Labels {
+		if md[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// federate returns the copy of the service to mirror, nil when none of its
+// nodes are federated. Nodes federated from another region are skipped.
+func (f *Federation) federate(s *regpb.Service) *regpb.Service {
+	fs := proto.Clone(s).(*regpb.Service)
+	fs.Nodes = nil
+
+	labeled := f.labeled(s.Metadata)
+
+	for _, n := range s.Nodes {
+		if _, ok := n.Metadata[FederatedFrom]; ok {
+			continue
+		}
+		if !labeled && !f.labeled(n.Metadata) {
+			continue
+		}
+		node := proto.Clone(n).(*regpb.Node)
+		if node.Metadata == nil {
+			node.Metadata = make(map[string]string)
+		}
+		node.Metadata[FederatedFrom] = f.opts.Region
+		fs.Nodes = append(fs.Nodes, node)
+	}
+
+	if len(fs.Nodes) == 0 {
+		return nil
+	}
+
+	return fs
+}
+
+// read returns the services of the local registry to mirror
+func (f *Federation) read() (map[string]*regpb.Service, error) {
+	list, err := f.local.ListServices()
+	if err != nil {
+		return nil, err
+	}
+
+	services := make(map[string]*regpb.Service)
+	seen := make(map[string]bool)
+
+	for _, s := range list {
+		if seen[s.Name] || !f.match(s.Name) {
+			continue
+		}
+		seen[s.Name] = true
+
+		versions, err := f.local.GetService(s.Name)
+		if err == registry.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, v := range versions {
+			if fs := f.federate(v); fs != nil {
+				services[key(fs)] = fs
+			}
+		}
+	}
+
+	return services, nil
+}
+
+// removed returns the nodes of prev which are not in cur, nil if there are none
+func removed(prev, cur *regpb.Service) *regpb.Service {
+	nodes := make(map[string]bool)
+	if cur != nil {
+		for _, n := range cur.Nodes {
+			nodes[n.Id] = true
+		}
+	}
+
+	rs := proto.Clone(prev).(*regpb.Service)
+	rs.Nodes = nil
+	for _, n := range prev.Nodes {
+		if !nodes[n.Id] {
+			rs.Nodes = append(rs.Nodes, n)
+		}
+	}
+
+	if len(rs.Nodes) == 0 {
+		return nil
+	}
+	return rs
+}
+
+// Sync mirrors the services of the local registry into the remotes once.
+// When the local registry is unreachable the services read last are
+// refreshed until the grace period is over, they are removed after it.
+func (f *Federation) Sync() error {
+	services, err := f.read()
+
+	f.Lock()
+	if err == nil {
+		f.services = services
+		f.seen = time.Now()
+	} else if time.Since(f.seen) > f.opts.GracePeriod {
+		f.services = make(map[string]*regpb.Service)
+	}
+	desired := f.services
+	f.Unlock()
+
+	for _, r := range f.opts.Remotes {
+		f.syncRemote(r, desired)
+	}
+
+	return err
+}
+
+func (f *Federation) syncRemote(r Remote, desired map[string]*regpb.Service) {
+	f.RLock()
+	prev := f.mirrored[r.Name]
+	f.RUnlock()
+
+	mirrored := make(map[string]*regpb.Service, len(desired))
+	var errs []string
+
+	for k, s := range desired {
+		if err := r.Registry.Register(s, registry.RegisterTTL(f.opts.TTL)); err != nil {
+			errs = append(errs, fmt.Sprintf("register %s: %v", s.Name, err))
+			// keep what was mirrored so it's removed later
+			if p, ok := prev[k]; ok {
+				mirrored[k] = p
+			}
+			continue
+		}
+		mirrored[k] = s
+	}
+
+	for k, p := range prev {
+		rs := removed(p, desired[k])
+		if rs == nil {
+			continue
+		}
+		if err := r.Registry.Deregister(rs); err != nil {
+			errs = append(errs, fmt.Sprintf("deregister %s: %v", p.Name, err))
+			if _, ok := mirrored[k]; !ok {
+				mirrored[k] = p
+			}
+		}
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	f.mirrored[r.Name] = mirrored
+	st := f.status[r.Name]
+	st.Services = len(desired)
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		st.Healthy = false
+		st.Failures++
+		st.Error = strings.Join(errs, "; ")
+		log.Warnf("Federation sync to %s failed: %s", r.Name, st.Error)
+		return
+	}
+
+	st.Healthy = true
+	st.Syncs++
+	st.LastSync = time.Now()
+	st.Error = ""
+}
+
+// Status returns the health of the federation with each remote
+func (f *Federation) Status() []Status {
+	f.RLock()
+	defer f.RUnlock()
+
+	status := make([]Status, 0, len(f.opts.Remotes))
+	for _, r := range f.opts.Remotes {
+		status = append(status, *f.status[r.Name])
+	}
+	return status
+}
+
+func (f *Federation) quit() bool {
+	select {
+	case <-f.exit:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait backs off the retry of the watch, it returns false when the federation stops
+func (f *Federation) wait(attempts int) bool {
+	select {
+	case <-f.exit:
+		return false
+	case <-time.After(backoff.Do(attempts)):
+		return true
+	}
+}
+
+// watch triggers a sync on every change of the local registry
+func (f *Federation) watch() {
+	var attempts int
+
+	for {
+		if f.quit() {
+			return
+		}
+
+		w, err := f.local.Watch()
+		if err != nil {
+			attempts++
+			log.Debugf("Federation watch error: %v", err)
+			if !f.wait(attempts) {
+				return
+			}
+			continue
+		}
+
+		// stop the watcher on exit, or once it failed
+		done := make(chan bool)
+		go func() {
+			select {
+			case <-f.exit:
+			case <-done:
+			}
+			w.Stop()
+		}()
+
+		for {
+			res, err := w.Next()
+			if err != nil {
+				log.Debugf("Federation watch error: %v", err)
+				break
+			}
+			attempts = 0
+			if res.Service == nil || !f.match(res.Service.Name) {
+				continue
+			}
+			select {
+			case f.trigger <- true:
+			default:
+			}
+		}
+		close(done)
+
+		attempts++
+		if !f.wait(attempts) {
+			return
+		}
+	}
+}
+
+func (f *Federation) run() {
+	// refresh the mirrored services every third of their ttl
+	interval := f.opts.TTL / 3
+	if interval <= 0 {
+		interval = f.opts.TTL
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	if err := f.Sync(); err != nil {
+		log.Warnf("Federation read of the local registry failed: %v", err)
+	}
+
+	for {
+		select {
+		case <-f.exit:
+			// remove the mirrored services from the remotes
+			for _, r := range f.opts.Remotes {
+				f.syncRemote(r, map[string]*regpb.Service{})
+			}
+			close(f.done)
+			return
+		case <-t.C:
+		case <-f.trigger:
+		}
+
+		if err := f.Sync(); err != nil {
+			log.Warnf("Federation read of the local registry failed: %v", err)
+		}
+	}
+}
+
+// Start starts mirroring the services
+func (f *Federation) Start() error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.running {
+		return nil
+	}
+
+	f.running = true
+	f.exit = make(chan bool)
+	f.done = make(chan bool)
+	f.trigger = make(chan bool, 1)
+
+	go f.watch()
+	go f.run()
+
+	return nil
+}
+
+// Stop stops mirroring the services and removes them from the remotes
+func (f *Federation) Stop() error {
+	f.Lock()
+	if !f.running {
+		f.Unlock()
+		return nil
+	}
+	f.running = false
+	close(f.exit)
+	f.Unlock()
+
+	<-f.done
+
+	return nil
+}
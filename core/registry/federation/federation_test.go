@@ -0,0 +1,264 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package federation
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/core/registry"
+	"github.com/lack-io/vine/core/registry/memory"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+type failRegistry struct {
+	registry.Registry
+	err error
+}
+
+func (r *failRegistry) ListServices(opts ...registry.ListOption) ([]*regpb.Service, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.Registry.ListServices(opts...)
+}
+
+// brokenWatchRegistry returns watchers failing on the first Next
+type brokenWatchRegistry struct {
+	registry.Registry
+
+	sync.Mutex
+	watches int
+}
+
+func (r *brokenWatchRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	r.Lock()
+	r.watches++
+	r.Unlock()
+	return &brokenWatcher{}, nil
+}
+
+type brokenWatcher struct{}
+
+func (w *brokenWatcher) Next() (*regpb.Result, error) {
+	return nil, errors.New("watch failed")
+}
+
+func (w *brokenWatcher) Stop() {}
+
+func testService(name, id string, md map[string]string) *regpb.Service {
+	return &regpb.Service{
+		Name:    name,
+		Version: "latest",
+		Nodes:   []*regpb.Node{{Id: id, Address: "127.0.0.1:8080", Metadata: md}},
+	}
+}
+
+func nodes(t *testing.T, r registry.Registry, name string) []*regpb.Node {
+	services, err := r.GetService(name)
+	if err == registry.ErrNotFound {
+		return nil
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	var nodes []*regpb.Node
+	for _, s := range services {
+		nodes = append(nodes, s.Nodes...)
+	}
+	return nodes
+}
+
+func TestFederationSync(t *testing.T) {
+	local := memory.NewRegistry()
+	remote := memory.NewRegistry()
+
+	foo := testService("go.vine.api.foo", "foo-1", nil)
+	for _, s := range []*regpb.Service{
+		foo,
+		testService("go.vine.service.bar", "bar-1", nil),
+		testService("go.vine.api.baz", "baz-1", map[string]string{FederatedFrom: "us"}),
+	} {
+		if err := local.Register(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := NewFederation(local, Region("eu"), Prefix("go.vine.api."), WithRemote("us", remote))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	n := nodes(t, remote, "go.vine.api.foo")
+	if len(n) != 1 || n[0].Metadata[FederatedFrom] != "eu" {
+		t.Fatalf("Expected foo mirrored from eu, got %v", n)
+	}
+	if n := nodes(t, remote, "go.vine.service.bar"); len(n) != 0 {
+		t.Fatalf("Expected bar not to be federated, got %v", n)
+	}
+	if n := nodes(t, remote, "go.vine.api.baz"); len(n) != 0 {
+		t.Fatalf("Expected federated baz not to be federated again, got %v", n)
+	}
+
+	st := f.Status()
+	if len(st) != 1 || !st[0].Healthy || st[0].Services != 1 || st[0].Syncs != 1 {
+		t.Fatalf("Unexpected status %+v", st)
+	}
+
+	if err := local.Deregister(foo); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if n := nodes(t, remote, "go.vine.api.foo"); len(n) != 0 {
+		t.Fatalf("Expected foo removed from the remote, got %v", n)
+	}
+}
+
+func TestFederationLabel(t *testing.T) {
+	local := memory.NewRegistry()
+	remote := memory.NewRegistry()
+
+	for _, s := range []*regpb.Service{
+		testService("foo", "foo-1", map[string]string{"global": "true"}),
+		testService("foo", "foo-2", nil),
+	} {
+		if err := local.Register(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := NewFederation(local, Region("eu"), Label("global", "true"), WithRemote("us", remote))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	n := nodes(t, remote, "foo")
+	if len(n) != 1 || n[0].Id != "foo-1" {
+		t.Fatalf("Expected the labeled node only, got %v", n)
+	}
+}
+
+func TestFederationGracePeriod(t *testing.T) {
+	local := &failRegistry{Registry: memory.NewRegistry()}
+	remote := memory.NewRegistry()
+
+	if err := local.Register(testService("foo", "foo-1", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewFederation(local, Region("eu"), GracePeriod(time.Hour), WithRemote("us", remote))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	local.err = errors.New("unreachable")
+	if err := f.Sync(); err == nil {
+		t.Fatal("Expected the local registry error")
+	}
+	if n := nodes(t, remote, "foo"); len(n) != 1 {
+		t.Fatalf("Expected foo kept during the grace period, got %v", n)
+	}
+
+	f.opts.GracePeriod = 0
+	f.Sync()
+	if n := nodes(t, remote, "foo"); len(n) != 0 {
+		t.Fatalf("Expected foo removed after the grace period, got %v", n)
+	}
+}
+
+func TestFederationStartStop(t *testing.T) {
+	local := memory.NewRegistry()
+	remote := memory.NewRegistry()
+
+	f, err := NewFederation(local, Region("eu"), WithRemote("us", remote))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := local.Register(testService("foo", "foo-1", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(nodes(t, remote, "foo")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected foo mirrored after the watch event")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := f.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if n := nodes(t, remote, "foo"); len(n) != 0 {
+		t.Fatalf("Expected foo removed on stop, got %v", n)
+	}
+}
+
+func TestFederationInvalidTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{0, -time.Second} {
+		if _, err := NewFederation(memory.NewRegistry(), TTL(ttl)); err == nil {
+			t.Fatalf("Expected an error for the ttl %v", ttl)
+		}
+	}
+}
+
+func TestFederationWatchBackoff(t *testing.T) {
+	local := &brokenWatchRegistry{Registry: memory.NewRegistry()}
+
+	f, err := NewFederation(local, Region("eu"), WithRemote("us", memory.NewRegistry()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	if err := f.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first retry waits 100ms, the second over 600ms
+	local.Lock()
+	defer local.Unlock()
+	if local.watches > 2 {
+		t.Fatalf("Expected the failed watches to back off, watched %d times", local.watches)
+	}
+}
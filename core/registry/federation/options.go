@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package federation
+
+import (
+	"context"
+	"time"
+
+	"github.com/lack-io/vine/core/registry"
+)
+
+var (
+	// DefaultTTL is the ttl of the mirrored services in the remote registries
+	DefaultTTL = time.Second * 30
+	// DefaultGracePeriod is how long the mirrored services are kept when the local registry is unreachable
+	DefaultGracePeriod = time.Minute
+)
+
+type Options struct {
+	// Region is set as the federated-from metadata of the mirrored nodes
+	Region string
+	// Remotes are the registries the services are mirrored into
+	Remotes []Remote
+	// Prefixes of the names of the federated services
+	Prefixes []string
+	// Labels are the metadata of the federated services
+	Labels map[string]string
+	// TTL of the mirrored services, refreshed every third of it
+	TTL time.Duration
+	// GracePeriod before the mirrored services are removed when the local registry is unreachable
+	GracePeriod time.Duration
+
+	// Other options for implementations of the interface
+	// can be stored in a context
+	Context context.Context
+}
+
+// Remote is a registry of another region
+type Remote struct {
+	Name     string
+	Registry registry.Registry
+}
+
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		TTL:         DefaultTTL,
+		GracePeriod: DefaultGracePeriod,
+		Labels:      make(map[string]string),
+		Context:     context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return options
+}
+
+// Region sets the region of the local registry
+func Region(r string) Option {
+	return func(o *Options) {
+		o.Region = r
+	}
+}
+
+// WithRemote adds a registry the services are mirrored into
+func WithRemote(name string, r registry.Registry) Option {
+	return func(o *Options) {
+		o.Remotes = append(o.Remotes, Remote{Name: name, Registry: r})
+	}
+}
+
+// Prefix federates the services whose name starts with one of the prefixes
+func Prefix(p ...string) Option {
+	return func(o *Options) {
+		o.Prefixes = append(o.Prefixes, p...)
+	}
+}
+
+// Label federates the services or nodes with the metadata
+func Label(key, val string) Option {
+	return func(o *Options) {
+		o.Labels[key] = val
+	}
+}
+
+// TTL sets the ttl of the mirrored services
+func TTL(t time.Duration) Option {
+	return func(o *Options) {
+		o.TTL = t
+	}
+}
+
+// GracePeriod sets how long the mirrored services are kept when the local registry is unreachable
+func GracePeriod(t time.Duration) Option {
+	return func(o *Options) {
+		o.GracePeriod = t
+	}
+}
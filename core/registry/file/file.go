@@ -0,0 +1,503 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package file provides a registry persisted to a json file, for single host
+// deployments where mdns is blocked and etcd is overkill
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/uuid"
+
+	"github.com/lack-io/vine/core/registry"
+	"github.com/lack-io/vine/lib/logger"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+var (
+	// DefaultPath is the path of the registry file
+	DefaultPath = filepath.Join(os.TempDir(), "vine", "registry.json")
+
+	sendEventTime = 10 * time.Millisecond
+	ttlPruneTime  = time.Second
+)
+
+type node struct {
+	Node *regpb.Node `json:"node"`
+	// Expiry is the unix nano time the node expires, zero never
+	Expiry int64 `json:"expiry,omitempty"`
+}
+
+type record struct {
+	// Service is the service without its nodes
+	Service *regpb.Service   `json:"service"`
+	Nodes   map[string]*node `json:"nodes"`
+}
+
+// records are indexed by service name and version
+type records map[string]map[string]*record
+
+type fileRegistry struct {
+	options registry.Options
+
+	sync.RWMutex
+	path     string
+	records  records
+	watchers map[string]*Watcher
+	exit     chan bool
+}
+
+// NewRegistry returns a registry persisting the registrations to a json file.
+// The file is watched so that the changes of the other processes of the host
+// are seen by the watchers.
+func NewRegistry(opts ...registry.Option) registry.Registry {
+	options := registry.Options{
+		Context: context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	f := &fileRegistry{
+		options:  options,
+		records:  make(records),
+		watchers: make(map[string]*Watcher),
+	}
+	f.start(getPath(options))
+
+	return f
+}
+
+// getPath returns the path of the registry file of the options
+func getPath(options registry.Options) string {
+	if options.Context != nil {
+		if p, ok := options.Context.Value(pathKey{}).(string); ok && len(p) > 0 {
+			return p
+		}
+	}
+	if len(options.Addrs) > 0 && len(options.Addrs[0]) > 0 {
+		return options.Addrs[0]
+	}
+	return DefaultPath
+}
+
+// start loads the registry file and watches it
+func (f *fileRegistry) start(path string) {
+	f.Lock()
+	f.path = filepath.Clean(path)
+	f.exit = make(chan bool)
+	f.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		logger.Errorf("Error creating registry directory: %v", err)
+	}
+
+	if _, err := f.refresh(); err != nil {
+		logger.Errorf("Error loading registry file %s: %v", path, err)
+	}
+
+	go f.run(f.path, f.exit)
+}
+
+// run refreshes the records when the registry file changes and when the
+// nodes may have expired
+func (f *fileRegistry) run(path string, exit chan bool) {
+	var events chan fsnotify.Event
+	var errs chan error
+
+	// the directory is watched since the file is replaced on each write
+	fw, err := fsnotify.NewWatcher()
+	if err == nil {
+		err = fw.Add(filepath.Dir(path))
+	}
+	if err != nil {
+		logger.Errorf("Error watching registry file %s: %v", path, err)
+	} else {
+		defer fw.Close()
+		events = fw.Events
+		errs = fw.Errors
+	}
+
+	prune := time.NewTicker(ttlPruneTime)
+	defer prune.Stop()
+
+	for {
+		select {
+		case <-exit:
+			return
+		case ev := <-events:
+			if filepath.Clean(ev.Name) != path {
+				continue
+			}
+		case err := <-errs:
+			logger.Errorf("Error watching registry file %s: %v", path, err)
+			continue
+		case <-prune.C:
+		}
+
+		if _, err := f.refresh(); err != nil {
+			logger.Errorf("Error loading registry file %s: %v", path, err)
+		}
+	}
+}
+
+// read returns the records of the registry file without the expired nodes
+func read(path string) (records, error) {
+	rs := make(records)
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return rs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &rs); err != nil {
+			return nil, err
+		}
+	}
+
+	prune(rs)
+	return rs, nil
+}
+
+// write replaces the registry file so the readers never see a partial file
+func write(path string, rs records) error {
+	b, err := json.Marshal(rs)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// prune removes the expired nodes and the services without nodes
+func prune(rs records) {
+	now := time.Now().UnixNano()
+
+	for name, versions := range rs {
+		for version, r := range versions {
+			for id, n := range r.Nodes {
+				if n.Expiry > 0 && now > n.Expiry {
+					logger.Debugf("Registry TTL expired for node %s of service %s", id, name)
+					delete(r.Nodes, id)
+				}
+			}
+			if len(r.Nodes) == 0 {
+				delete(versions, version)
+			}
+		}
+		if len(versions) == 0 {
+			delete(rs, name)
+		}
+	}
+}
+
+// refresh reads the registry file and sends the events of the changes
+func (f *fileRegistry) refresh() (records, error) {
+	f.Lock()
+	rs, err := read(f.path)
+	if err != nil {
+		f.Unlock()
+		return nil, err
+	}
+	results := diff(f.records, rs)
+	f.records = rs
+	f.Unlock()
+
+	if len(results) > 0 {
+		go f.sendEvents(results)
+	}
+
+	return rs, nil
+}
+
+// update applies the change to the registry file, holding the lock of the
+// file so the changes of the processes of the host are not lost
+func (f *fileRegistry) update(fn func(records)) error {
+	f.Lock()
+
+	unlock, err := lock(f.path)
+	if err != nil {
+		f.Unlock()
+		return err
+	}
+
+	rs, err := read(f.path)
+	if err == nil {
+		fn(rs)
+		prune(rs)
+		err = write(f.path, rs)
+	}
+	unlock()
+
+	if err != nil {
+		f.Unlock()
+		return err
+	}
+
+	results := diff(f.records, rs)
+	f.records = rs
+	f.Unlock()
+
+	if len(results) > 0 {
+		go f.sendEvents(results)
+	}
+
+	return nil
+}
+
+// toService returns the service of the record with its nodes sorted by id
+func toService(r *record) *regpb.Service {
+	s := *r.Service
+
+	s.Metadata = make(map[string]string, len(r.Service.Metadata))
+	for k, v := range r.Service.Metadata {
+		s.Metadata[k] = v
+	}
+
+	s.Nodes = make([]*regpb.Node, 0, len(r.Nodes))
+	for _, n := range r.Nodes {
+		nd := *n.Node
+		s.Nodes = append(s.Nodes, &nd)
+	}
+	sort.Slice(s.Nodes, func(i, j int) bool {
+		return s.Nodes[i].Id < s.Nodes[j].Id
+	})
+
+	return &s
+}
+
+// diff returns the events of the changes between the records, the TTL
+// refreshes are not changes
+func diff(old, rs records) []*regpb.Result {
+	var results []*regpb.Result
+	now := time.Now().Unix()
+
+	for name, versions := range rs {
+		for version, r := range versions {
+			o, ok := old[name][version]
+			if !ok {
+				results = append(results, &regpb.Result{Action: "create", Service: toService(r), Timestamp: now})
+				continue
+			}
+
+			ob, _ := json.Marshal(toService(o))
+			nb, _ := json.Marshal(toService(r))
+			if string(ob) != string(nb) {
+				results = append(results, &regpb.Result{Action: "update", Service: toService(r), Timestamp: now})
+			}
+		}
+	}
+
+	for name, versions := range old {
+		for version, o := range versions {
+			if _, ok := rs[name][version]; !ok {
+				results = append(results, &regpb.Result{Action: "delete", Service: toService(o), Timestamp: now})
+			}
+		}
+	}
+
+	return results
+}
+
+func (f *fileRegistry) sendEvents(results []*regpb.Result) {
+	f.RLock()
+	watchers := make([]*Watcher, 0, len(f.watchers))
+	for _, w := range f.watchers {
+		watchers = append(watchers, w)
+	}
+	f.RUnlock()
+
+	for _, r := range results {
+		for _, w := range watchers {
+			select {
+			case <-w.exit:
+				f.Lock()
+				delete(f.watchers, w.id)
+				f.Unlock()
+			default:
+				select {
+				case w.res <- r:
+				case <-time.After(sendEventTime):
+				}
+			}
+		}
+	}
+}
+
+func (f *fileRegistry) Init(opts ...registry.Option) error {
+	for _, o := range opts {
+		o(&f.options)
+	}
+
+	// watch the new registry file
+	path := filepath.Clean(getPath(f.options))
+	f.RLock()
+	changed := path != f.path
+	f.RUnlock()
+
+	if changed {
+		close(f.exit)
+		f.start(path)
+	}
+
+	return nil
+}
+
+func (f *fileRegistry) Options() registry.Options {
+	return f.options
+}
+
+func (f *fileRegistry) Register(s *regpb.Service, opts ...registry.RegisterOption) error {
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	var expiry int64
+	if options.TTL > 0 {
+		expiry = time.Now().Add(options.TTL).UnixNano()
+	}
+
+	return f.update(func(rs records) {
+		versions, ok := rs[s.Name]
+		if !ok {
+			versions = make(map[string]*record)
+			rs[s.Name] = versions
+		}
+
+		r, ok := versions[s.Version]
+		if !ok {
+			r = &record{Nodes: make(map[string]*node)}
+			versions[s.Version] = r
+			logger.Debugf("Registry added new service: %s, version: %s", s.Name, s.Version)
+		}
+
+		service := *s
+		service.Nodes = nil
+		r.Service = &service
+
+		for _, n := range s.Nodes {
+			r.Nodes[n.Id] = &node{Node: n, Expiry: expiry}
+		}
+	})
+}
+
+func (f *fileRegistry) Deregister(s *regpb.Service, opts ...registry.DeregisterOption) error {
+	return f.update(func(rs records) {
+		r, ok := rs[s.Name][s.Version]
+		if !ok {
+			return
+		}
+
+		for _, n := range s.Nodes {
+			logger.Debugf("Registry removed node from service: %s, version: %s", s.Name, s.Version)
+			delete(r.Nodes, n.Id)
+		}
+	})
+}
+
+func (f *fileRegistry) GetService(name string, opts ...registry.GetOption) ([]*regpb.Service, error) {
+	rs, err := f.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, ok := rs[name]
+	if !ok {
+		return nil, registry.ErrNotFound
+	}
+
+	services := make([]*regpb.Service, 0, len(versions))
+	for _, r := range versions {
+		services = append(services, toService(r))
+	}
+
+	return services, nil
+}
+
+func (f *fileRegistry) ListServices(opts ...registry.ListOption) ([]*regpb.Service, error) {
+	rs, err := f.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	var services []*regpb.Service
+	for _, versions := range rs {
+		for _, r := range versions {
+			services = append(services, toService(r))
+		}
+	}
+
+	return services, nil
+}
+
+func (f *fileRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	var wo registry.WatchOptions
+	for _, o := range opts {
+		o(&wo)
+	}
+
+	w := &Watcher{
+		exit: make(chan bool),
+		res:  make(chan *regpb.Result),
+		id:   uuid.New().String(),
+		wo:   wo,
+	}
+
+	f.Lock()
+	f.watchers[w.id] = w
+	f.Unlock()
+
+	return w, nil
+}
+
+func (f *fileRegistry) String() string {
+	return "file"
+}
@@ -0,0 +1,162 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package file
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/core/registry"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+func testService(nodes ...string) *regpb.Service {
+	s := &regpb.Service{
+		Name:     "foo",
+		Version:  "1.0.0",
+		Metadata: map[string]string{"foo": "bar"},
+	}
+	for _, id := range nodes {
+		s.Nodes = append(s.Nodes, &regpb.Node{Id: id, Address: "127.0.0.1:9090"})
+	}
+	return s
+}
+
+func next(t *testing.T, w registry.Watcher) *regpb.Result {
+	ch := make(chan *regpb.Result, 1)
+	go func() {
+		r, err := w.Next()
+		if err == nil {
+			ch <- r
+		}
+	}()
+
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for a registry event")
+	}
+	return nil
+}
+
+func TestFileRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	r := NewRegistry(Path(path))
+
+	if err := r.Register(testService("foo-1", "foo-2")); err != nil {
+		t.Fatal(err)
+	}
+
+	services, err := r.GetService("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || len(services[0].Nodes) != 2 {
+		t.Fatalf("Expected 1 service with 2 nodes, got %v", services)
+	}
+
+	// the registrations are persisted
+	services, err = NewRegistry(registry.Addrs(path)).GetService("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services[0].Metadata["foo"] != "bar" || len(services[0].Nodes) != 2 {
+		t.Fatalf("Expected the persisted service, got %v", services)
+	}
+
+	if err := r.Deregister(testService("foo-1", "foo-2")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetService("foo"); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", registry.ErrNotFound, err)
+	}
+}
+
+func TestFileRegistryTTL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	r := NewRegistry(Path(path))
+
+	if err := r.Register(testService("foo-1"), registry.RegisterTTL(time.Millisecond*50)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(&regpb.Service{Name: "bar", Nodes: []*regpb.Node{{Id: "bar-1"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	if _, err := r.GetService("foo"); err != registry.ErrNotFound {
+		t.Fatalf("Expected the service to expire, got %v", err)
+	}
+
+	// the expired nodes are removed from the file on the next write
+	if err := r.Register(&regpb.Service{Name: "baz", Nodes: []*regpb.Node{{Id: "baz-1"}}}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "foo-1") || !strings.Contains(string(b), "bar-1") {
+		t.Fatalf("Expected only the expired nodes removed from the file, got %s", b)
+	}
+}
+
+func TestFileRegistryWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+
+	// the registries stand for two processes sharing the file
+	writer := NewRegistry(Path(path))
+	reader := NewRegistry(Path(path))
+
+	w, err := reader.Watch(registry.WatchService("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	if err := writer.Register(testService("foo-1")); err != nil {
+		t.Fatal(err)
+	}
+	if r := next(t, w); r.Action != "create" || len(r.Service.Nodes) != 1 {
+		t.Fatalf("Expected create event with 1 node, got %v", r)
+	}
+
+	if err := writer.Register(testService("foo-1", "foo-2")); err != nil {
+		t.Fatal(err)
+	}
+	if r := next(t, w); r.Action != "update" || len(r.Service.Nodes) != 2 {
+		t.Fatalf("Expected update event with 2 nodes, got %v", r)
+	}
+
+	if err := writer.Deregister(testService("foo-1", "foo-2")); err != nil {
+		t.Fatal(err)
+	}
+	if r := next(t, w); r.Action != "delete" || r.Service.Name != "foo" {
+		t.Fatalf("Expected delete event, got %v", r)
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/lack-io/vine/core/client"
 	"github.com/lack-io/vine/core/client/grpc"
 	"github.com/lack-io/vine/core/registry"
+	log "github.com/lack-io/vine/lib/logger"
 	"github.com/lack-io/vine/proto/apis/errors"
 	regpb "github.com/lack-io/vine/proto/apis/registry"
 	regSvc "github.com/lack-io/vine/proto/services/registry"
@@ -48,6 +49,8 @@ type gRPCRegistry struct {
 	address []string
 	// client to call registry
 	client regSvc.RegistryService
+	// cli opens the watch streams
+	cli client.Client
 }
 
 func (s *gRPCRegistry) callOpts() []client.CallOption {
@@ -83,6 +86,7 @@ func (s *gRPCRegistry) Init(opts ...registry.Option) error {
 		cli = grpc.NewClient()
 	}
 
+	s.cli = cli
 	s.client = regSvc.NewRegistryService(DefaultService, cli)
 
 	return nil
@@ -169,7 +173,28 @@ func (s *gRPCRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher, er
 		options.Context = context.TODO()
 	}
 
-	stream, err := s.client.Watch(options.Context, &regSvc.WatchRequest{Service: options.Service}, s.callOpts()...)
+	// open the watch stream, it's re-established after a network failure
+	watch := func(ctx context.Context, _ string) (client.Stream, error) {
+		req := s.cli.NewRequest(s.name, "Registry.Watch", &regSvc.WatchRequest{})
+		stream, err := s.cli.Stream(ctx, req, s.callOpts()...)
+		if err != nil {
+			return nil, err
+		}
+		if err := stream.Send(&regSvc.WatchRequest{Service: options.Service}); err != nil {
+			stream.Close()
+			return nil, err
+		}
+		return stream, nil
+	}
+
+	stream, err := client.NewReconnectingStream(options.Context, watch,
+		client.ReconnectAttempts(5),
+		client.OnStateChange(func(state client.StreamState, err error) {
+			if state == client.StreamReconnecting {
+				log.Debugf("Registry watch reconnecting: %v", err)
+			}
+		}),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -214,5 +239,6 @@ func NewRegistry(opts ...registry.Option) registry.Registry {
 		name:    name,
 		address: addrs,
 		client:  regSvc.NewRegistryService(name, cli),
+		cli:     cli,
 	}
 }
@@ -23,13 +23,13 @@
 package grpc
 
 import (
+	"github.com/lack-io/vine/core/client"
 	"github.com/lack-io/vine/core/registry"
 	regpb "github.com/lack-io/vine/proto/apis/registry"
-	regsvcpb "github.com/lack-io/vine/proto/services/registry"
 )
 
 type serviceWatcher struct {
-	stream regsvcpb.Registry_WatchService
+	stream client.Stream
 	closed chan bool
 }
 
@@ -41,9 +41,14 @@ func (s *serviceWatcher) Next() (*regpb.Result, error) {
 	default:
 	}
 
-	r, err := s.stream.Recv()
-	if err != nil {
-		return nil, err
+	r := new(regpb.Result)
+	if err := s.stream.Recv(r); err != nil {
+		select {
+		case <-s.closed:
+			return nil, registry.ErrWatcherStopped
+		default:
+			return nil, err
+		}
 	}
 
 	return &regpb.Result{
@@ -62,7 +67,7 @@ func (s *serviceWatcher) Stop() {
 	}
 }
 
-func newWatcher(stream regsvcpb.Registry_WatchService) registry.Watcher {
+func newWatcher(stream client.Stream) registry.Watcher {
 	return &serviceWatcher{
 		stream: stream,
 		closed: make(chan bool),
@@ -102,8 +102,8 @@ func (m *Registry) ttlPrune() {
 					for id, n := range record.Nodes {
 						if n.TTL != 0 && time.Since(n.LastSeen) > n.TTL {
 							logger.Debugf("Registry TTL expired for node %s of service %s", n.Id, name)
+							delete(m.records[name][version].Nodes, id)
 						}
-						delete(m.records[name][version].Nodes, id)
 					}
 				}
 			}
@@ -253,14 +253,32 @@ func (m *Registry) Deregister(s *regpb.Service, opts ...registry.DeregisterOptio
 }
 
 func (m *Registry) GetService(name string, opts ...registry.GetOption) ([]*regpb.Service, error) {
-	m.RLock()
-	defer m.RUnlock()
+	// keeping the service warm updates the nodes, so it needs the write lock
+	refresh := getRefreshOnRead(m.options.Context)
+	if refresh {
+		m.Lock()
+		defer m.Unlock()
+	} else {
+		m.RLock()
+		defer m.RUnlock()
+	}
 
 	records, ok := m.records[name]
 	if !ok {
 		return nil, registry.ErrNotFound
 	}
 
+	if refresh {
+		now := time.Now()
+		for _, record := range records {
+			for _, n := range record.Nodes {
+				if n.TTL != 0 {
+					n.LastSeen = now
+				}
+			}
+		}
+	}
+
 	services := make([]*regpb.Service, len(m.records[name]))
 	i := 0
 	for _, record := range records {
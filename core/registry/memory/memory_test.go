@@ -267,3 +267,42 @@ func TestMemoryRegisterTTLConcurrent(t *testing.T) {
 		}
 	}
 }
+
+func TestMemoryRefreshOnRead(t *testing.T) {
+	ttl := ttlPruneTime * 2
+
+	for _, refresh := range []bool{true, false} {
+		m := NewRegistry(RefreshOnRead(refresh))
+
+		service := testData["foo"][0]
+		if err := m.Register(service, registry.RegisterTTL(ttl)); err != nil {
+			t.Fatal(err)
+		}
+
+		// read the service for longer than the TTL
+		deadline := time.Now().Add(ttl + ttlPruneTime)
+		for time.Now().Before(deadline) {
+			if _, err := m.GetService(service.Name); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(ttlPruneTime / 4)
+		}
+
+		svcs, err := m.GetService(service.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var nodes int
+		for _, svc := range svcs {
+			nodes += len(svc.Nodes)
+		}
+
+		if refresh && nodes == 0 {
+			t.Fatalf("Expected service %q to be kept alive when refreshed on read", service.Name)
+		}
+		if !refresh && nodes > 0 {
+			t.Fatalf("Expected service %q to expire without refresh on read", service.Name)
+		}
+	}
+}
@@ -30,6 +30,7 @@ import (
 )
 
 type serviceKey struct{}
+type refreshOnReadKey struct{}
 
 func getServiceRecords(ctx context.Context) map[string]map[string]*record {
 	memServices, ok := ctx.Value(serviceKey{}).(map[string][]*regpb.Service)
@@ -61,3 +62,22 @@ func Services(s map[string][]*regpb.Service) registry.Option {
 		o.Context = context.WithValue(o.Context, serviceKey{}, s)
 	}
 }
+
+// RefreshOnRead is an option that bumps the TTL of the nodes of a service
+// whenever it's read, keeping busy services with short TTLs alive
+func RefreshOnRead(b bool) registry.Option {
+	return func(o *registry.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, refreshOnReadKey{}, b)
+	}
+}
+
+func getRefreshOnRead(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	b, _ := ctx.Value(refreshOnReadKey{}).(bool)
+	return b
+}
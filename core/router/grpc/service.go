@@ -134,12 +134,13 @@ func (s *svc) advertiseEvents(advertChan chan *rr.Advert, stream pb.Router_Adver
 		events := make([]*rr.Event, len(resp.Events))
 		for i, event := range resp.Events {
 			route := rr.Route{
-				Service: event.Route.Service,
-				Address: event.Route.Address,
-				Gateway: event.Route.Gateway,
-				Network: event.Route.Network,
-				Link:    event.Route.Link,
-				Metric:  event.Route.Metric,
+				Service:  event.Route.Service,
+				Address:  event.Route.Address,
+				Gateway:  event.Route.Gateway,
+				Network:  event.Route.Network,
+				Link:     event.Route.Link,
+				Metric:   event.Route.Metric,
+				Metadata: event.Route.Metadata,
 			}
 
 			events[i] = &rr.Event{
@@ -194,12 +195,13 @@ func (s *svc) Process(advert *rr.Advert) error {
 	events := make([]*pb.Event, 0, len(advert.Events))
 	for _, event := range advert.Events {
 		route := &pb.Route{
-			Service: event.Route.Service,
-			Address: event.Route.Address,
-			Gateway: event.Route.Gateway,
-			Network: event.Route.Network,
-			Link:    event.Route.Link,
-			Metric:  event.Route.Metric,
+			Service:  event.Route.Service,
+			Address:  event.Route.Address,
+			Gateway:  event.Route.Gateway,
+			Network:  event.Route.Network,
+			Link:     event.Route.Link,
+			Metric:   event.Route.Metric,
+			Metadata: event.Route.Metadata,
 		}
 		e := &pb.Event{
 			Id:        event.Id,
@@ -246,9 +248,10 @@ func (s *svc) Lookup(q ...rr.QueryOption) ([]rr.Route, error) {
 
 	resp, err := s.router.Lookup(context.Background(), &pb.LookupRequest{
 		Query: &pb.Query{
-			Service: query.Service,
-			Gateway: query.Gateway,
-			Network: query.Network,
+			Service:  query.Service,
+			Gateway:  query.Gateway,
+			Network:  query.Network,
+			Metadata: query.Metadata,
 		},
 	}, s.callOpts...)
 
@@ -260,12 +263,13 @@ func (s *svc) Lookup(q ...rr.QueryOption) ([]rr.Route, error) {
 	routes := make([]rr.Route, len(resp.Routes))
 	for i, route := range resp.Routes {
 		routes[i] = rr.Route{
-			Service: route.Service,
-			Address: route.Address,
-			Gateway: route.Gateway,
-			Network: route.Network,
-			Link:    route.Link,
-			Metric:  route.Metric,
+			Service:  route.Service,
+			Address:  route.Address,
+			Gateway:  route.Gateway,
+			Network:  route.Network,
+			Link:     route.Link,
+			Metric:   route.Metric,
+			Metadata: route.Metadata,
 		}
 	}
 
@@ -287,6 +291,26 @@ func (s *svc) Watch(opts ...rr.WatchOption) (rr.Watcher, error) {
 	return newWatcher(rsp, options)
 }
 
+// Stats returns the statistics of the remote router
+func (s *svc) Stats() (*rr.Stats, error) {
+	rsp, err := s.router.Stats(context.Background(), &pb.StatsRequest{}, s.callOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &rr.Stats{
+		Routes:          int(rsp.Routes),
+		Events:          rsp.Events,
+		AdvertsSent:     rsp.AdvertsSent,
+		AdvertsReceived: rsp.AdvertsReceived,
+	}
+	if rsp.LastSync > 0 {
+		stats.LastSync = time.Unix(0, rsp.LastSync)
+	}
+
+	return stats, nil
+}
+
 // Returns the router implementation
 func (s *svc) String() string {
 	return "service"
@@ -38,12 +38,13 @@ type table struct {
 // Create new route in the routing table
 func (t *table) Create(r rr.Route) error {
 	route := &pb.Route{
-		Service: r.Service,
-		Address: r.Address,
-		Gateway: r.Gateway,
-		Network: r.Network,
-		Link:    r.Link,
-		Metric:  r.Metric,
+		Service:  r.Service,
+		Address:  r.Address,
+		Gateway:  r.Gateway,
+		Network:  r.Network,
+		Link:     r.Link,
+		Metric:   r.Metric,
+		Metadata: r.Metadata,
 	}
 
 	if _, err := t.table.Create(context.Background(), route, t.callOpts...); err != nil {
@@ -56,12 +57,13 @@ func (t *table) Create(r rr.Route) error {
 // Delete deletes existing route from the routing table
 func (t *table) Delete(r rr.Route) error {
 	route := &pb.Route{
-		Service: r.Service,
-		Address: r.Address,
-		Gateway: r.Gateway,
-		Network: r.Network,
-		Link:    r.Link,
-		Metric:  r.Metric,
+		Service:  r.Service,
+		Address:  r.Address,
+		Gateway:  r.Gateway,
+		Network:  r.Network,
+		Link:     r.Link,
+		Metric:   r.Metric,
+		Metadata: r.Metadata,
 	}
 
 	if _, err := t.table.Delete(context.Background(), route, t.callOpts...); err != nil {
@@ -74,12 +76,13 @@ func (t *table) Delete(r rr.Route) error {
 // Update updates route in the routing table
 func (t *table) Update(r rr.Route) error {
 	route := &pb.Route{
-		Service: r.Service,
-		Address: r.Address,
-		Gateway: r.Gateway,
-		Network: r.Network,
-		Link:    r.Link,
-		Metric:  r.Metric,
+		Service:  r.Service,
+		Address:  r.Address,
+		Gateway:  r.Gateway,
+		Network:  r.Network,
+		Link:     r.Link,
+		Metric:   r.Metric,
+		Metadata: r.Metadata,
 	}
 
 	if _, err := t.table.Update(context.Background(), route, t.callOpts...); err != nil {
@@ -99,12 +102,13 @@ func (t *table) List() ([]rr.Route, error) {
 	routes := make([]rr.Route, len(resp.Routes))
 	for i, route := range resp.Routes {
 		routes[i] = rr.Route{
-			Service: route.Service,
-			Address: route.Address,
-			Gateway: route.Gateway,
-			Network: route.Network,
-			Link:    route.Link,
-			Metric:  route.Metric,
+			Service:  route.Service,
+			Address:  route.Address,
+			Gateway:  route.Gateway,
+			Network:  route.Network,
+			Link:     route.Link,
+			Metric:   route.Metric,
+			Metadata: route.Metadata,
 		}
 	}
 
@@ -118,9 +122,10 @@ func (t *table) Query(q ...rr.QueryOption) ([]rr.Route, error) {
 	// call the router
 	resp, err := t.table.Query(context.Background(), &pb.QueryRequest{
 		Query: &pb.Query{
-			Service: query.Service,
-			Gateway: query.Gateway,
-			Network: query.Network,
+			Service:  query.Service,
+			Gateway:  query.Gateway,
+			Network:  query.Network,
+			Metadata: query.Metadata,
 		},
 	}, t.callOpts...)
 
@@ -132,12 +137,13 @@ func (t *table) Query(q ...rr.QueryOption) ([]rr.Route, error) {
 	routes := make([]rr.Route, len(resp.Routes))
 	for i, route := range resp.Routes {
 		routes[i] = rr.Route{
-			Service: route.Service,
-			Address: route.Address,
-			Gateway: route.Gateway,
-			Network: route.Network,
-			Link:    route.Link,
-			Metric:  route.Metric,
+			Service:  route.Service,
+			Address:  route.Address,
+			Gateway:  route.Gateway,
+			Network:  route.Network,
+			Link:     route.Link,
+			Metric:   route.Metric,
+			Metadata: route.Metadata,
 		}
 	}
 
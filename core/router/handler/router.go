@@ -39,7 +39,7 @@ type Router struct {
 
 // Lookup looks up routes in the routing table and returns them
 func (r *Router) Lookup(ctx context.Context, req *pb.LookupRequest, resp *pb.LookupResponse) error {
-	routes, err := r.Router.Lookup(rr.QueryService(req.Query.Service))
+	routes, err := r.Router.Lookup(rr.QueryService(req.Query.Service), rr.QueryMetadata(req.Query.Metadata))
 	if err != nil {
 		return errors.InternalServerError("go.vine.router", "failed to lookup routes: %v", err)
 	}
@@ -47,13 +47,14 @@ func (r *Router) Lookup(ctx context.Context, req *pb.LookupRequest, resp *pb.Loo
 	respRoutes := make([]*pb.Route, 0, len(routes))
 	for _, route := range routes {
 		respRoute := &pb.Route{
-			Service: route.Service,
-			Address: route.Address,
-			Gateway: route.Gateway,
-			Network: route.Network,
-			Router:  route.Router,
-			Link:    route.Link,
-			Metric:  route.Metric,
+			Service:  route.Service,
+			Address:  route.Address,
+			Gateway:  route.Gateway,
+			Network:  route.Network,
+			Router:   route.Router,
+			Link:     route.Link,
+			Metric:   route.Metric,
+			Metadata: route.Metadata,
 		}
 		respRoutes = append(respRoutes, respRoute)
 	}
@@ -74,13 +75,14 @@ func (r *Router) Advertise(ctx context.Context, req *pb.Request, stream pb.Route
 		var events []*pb.Event
 		for _, event := range advert.Events {
 			route := &pb.Route{
-				Service: event.Route.Service,
-				Address: event.Route.Address,
-				Gateway: event.Route.Gateway,
-				Network: event.Route.Network,
-				Router:  event.Route.Router,
-				Link:    event.Route.Link,
-				Metric:  event.Route.Metric,
+				Service:  event.Route.Service,
+				Address:  event.Route.Address,
+				Gateway:  event.Route.Gateway,
+				Network:  event.Route.Network,
+				Router:   event.Route.Router,
+				Link:     event.Route.Link,
+				Metric:   event.Route.Metric,
+				Metadata: event.Route.Metadata,
 			}
 			e := &pb.Event{
 				Id:        event.Id,
@@ -116,13 +118,14 @@ func (r *Router) Process(ctx context.Context, req *pb.Advert, rsp *pb.ProcessRes
 	events := make([]*rr.Event, len(req.Events))
 	for i, event := range req.Events {
 		route := rr.Route{
-			Service: event.Route.Service,
-			Address: event.Route.Address,
-			Gateway: event.Route.Gateway,
-			Network: event.Route.Network,
-			Router:  event.Route.Router,
-			Link:    event.Route.Link,
-			Metric:  event.Route.Metric,
+			Service:  event.Route.Service,
+			Address:  event.Route.Address,
+			Gateway:  event.Route.Gateway,
+			Network:  event.Route.Network,
+			Router:   event.Route.Router,
+			Link:     event.Route.Link,
+			Metric:   event.Route.Metric,
+			Metadata: event.Route.Metadata,
 		}
 
 		events[i] = &rr.Event{
@@ -168,13 +171,14 @@ func (r *Router) Watch(ctx context.Context, req *pb.WatchRequest, stream pb.Rout
 		}
 
 		route := &pb.Route{
-			Service: event.Route.Service,
-			Address: event.Route.Address,
-			Gateway: event.Route.Gateway,
-			Network: event.Route.Network,
-			Router:  event.Route.Router,
-			Link:    event.Route.Link,
-			Metric:  event.Route.Metric,
+			Service:  event.Route.Service,
+			Address:  event.Route.Address,
+			Gateway:  event.Route.Gateway,
+			Network:  event.Route.Network,
+			Router:   event.Route.Router,
+			Link:     event.Route.Link,
+			Metric:   event.Route.Metric,
+			Metadata: event.Route.Metadata,
 		}
 
 		tableEvent := &pb.Event{
@@ -189,3 +193,77 @@ func (r *Router) Watch(ctx context.Context, req *pb.WatchRequest, stream pb.Rout
 		}
 	}
 }
+
+// Table streams the routes of the routing table with their age
+func (r *Router) Table(ctx context.Context, req *pb.TableRequest, stream pb.Router_TableStream) error {
+	defer stream.Close()
+
+	var entries []rr.Entry
+	if t, ok := r.Router.Table().(rr.Entries); ok {
+		var err error
+		if entries, err = t.Entries(); err != nil {
+			return errors.InternalServerError("go.vine.router", "failed to list routes: %v", err)
+		}
+	} else {
+		routes, err := r.Router.Table().List()
+		if err != nil {
+			return errors.InternalServerError("go.vine.router", "failed to list routes: %v", err)
+		}
+		for _, route := range routes {
+			entries = append(entries, rr.Entry{Route: route})
+		}
+	}
+
+	match := func(a, b string) bool {
+		return len(a) == 0 || a == "*" || a == b
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		if q := req.Query; q != nil {
+			if !match(q.Service, entry.Service) || !match(q.Gateway, entry.Gateway) || !match(q.Network, entry.Network) || !entry.HasMetadata(q.Metadata) {
+				continue
+			}
+		}
+
+		route := &pb.TableRoute{
+			Route: &pb.Route{
+				Service:  entry.Service,
+				Address:  entry.Address,
+				Gateway:  entry.Gateway,
+				Network:  entry.Network,
+				Router:   entry.Router,
+				Link:     entry.Link,
+				Metric:   entry.Metric,
+				Metadata: entry.Metadata,
+			},
+		}
+		if !entry.Updated.IsZero() {
+			route.Age = int64(now.Sub(entry.Updated))
+		}
+
+		if err := stream.Send(route); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stats returns the router statistics
+func (r *Router) Stats(ctx context.Context, req *pb.StatsRequest, rsp *pb.StatsResponse) error {
+	stats, err := r.Router.Stats()
+	if err != nil {
+		return errors.InternalServerError("go.vine.router", "failed to get stats: %v", err)
+	}
+
+	rsp.Routes = int64(stats.Routes)
+	rsp.Events = stats.Events
+	rsp.AdvertsSent = stats.AdvertsSent
+	rsp.AdvertsReceived = stats.AdvertsReceived
+	if !stats.LastSync.IsZero() {
+		rsp.LastSync = stats.LastSync.UnixNano()
+	}
+
+	return nil
+}
@@ -36,13 +36,14 @@ type Table struct {
 
 func (t *Table) Create(ctx context.Context, route *pb.Route, resp *pb.CreateResponse) error {
 	err := t.Router.Table().Create(rr.Route{
-		Service: route.Service,
-		Address: route.Address,
-		Gateway: route.Gateway,
-		Network: route.Network,
-		Router:  route.Router,
-		Link:    route.Link,
-		Metric:  route.Metric,
+		Service:  route.Service,
+		Address:  route.Address,
+		Gateway:  route.Gateway,
+		Network:  route.Network,
+		Router:   route.Router,
+		Link:     route.Link,
+		Metric:   route.Metric,
+		Metadata: route.Metadata,
 	})
 	if err != nil {
 		return errors.InternalServerError("go.vine.router", "failed to create route: %s", err)
@@ -53,13 +54,14 @@ func (t *Table) Create(ctx context.Context, route *pb.Route, resp *pb.CreateResp
 
 func (t *Table) Update(ctx context.Context, route *pb.Route, resp *pb.UpdateResponse) error {
 	err := t.Router.Table().Update(rr.Route{
-		Service: route.Service,
-		Address: route.Address,
-		Gateway: route.Gateway,
-		Network: route.Network,
-		Router:  route.Router,
-		Link:    route.Link,
-		Metric:  route.Metric,
+		Service:  route.Service,
+		Address:  route.Address,
+		Gateway:  route.Gateway,
+		Network:  route.Network,
+		Router:   route.Router,
+		Link:     route.Link,
+		Metric:   route.Metric,
+		Metadata: route.Metadata,
 	})
 	if err != nil {
 		return errors.InternalServerError("go.vine.router", "failed to update route: %s", err)
@@ -70,13 +72,14 @@ func (t *Table) Update(ctx context.Context, route *pb.Route, resp *pb.UpdateResp
 
 func (t *Table) Delete(ctx context.Context, route *pb.Route, resp *pb.DeleteResponse) error {
 	err := t.Router.Table().Delete(rr.Route{
-		Service: route.Service,
-		Address: route.Address,
-		Gateway: route.Gateway,
-		Network: route.Network,
-		Router:  route.Router,
-		Link:    route.Link,
-		Metric:  route.Metric,
+		Service:  route.Service,
+		Address:  route.Address,
+		Gateway:  route.Gateway,
+		Network:  route.Network,
+		Router:   route.Router,
+		Link:     route.Link,
+		Metric:   route.Metric,
+		Metadata: route.Metadata,
 	})
 	if err != nil {
 		return errors.InternalServerError("go.vine.router", "failed to delete route: %s", err)
@@ -95,13 +98,14 @@ func (t *Table) List(ctx context.Context, req *pb.Request, resp *pb.ListResponse
 	respRoutes := make([]*pb.Route, 0, len(routes))
 	for _, route := range routes {
 		respRoute := &pb.Route{
-			Service: route.Service,
-			Address: route.Address,
-			Gateway: route.Gateway,
-			Network: route.Network,
-			Router:  route.Router,
-			Link:    route.Link,
-			Metric:  route.Metric,
+			Service:  route.Service,
+			Address:  route.Address,
+			Gateway:  route.Gateway,
+			Network:  route.Network,
+			Router:   route.Router,
+			Link:     route.Link,
+			Metric:   route.Metric,
+			Metadata: route.Metadata,
 		}
 		respRoutes = append(respRoutes, respRoute)
 	}
@@ -112,7 +116,7 @@ func (t *Table) List(ctx context.Context, req *pb.Request, resp *pb.ListResponse
 }
 
 func (t *Table) Query(ctx context.Context, req *pb.QueryRequest, resp *pb.QueryResponse) error {
-	routes, err := t.Router.Table().Query(rr.QueryService(req.Query.Service))
+	routes, err := t.Router.Table().Query(rr.QueryService(req.Query.Service), rr.QueryMetadata(req.Query.Metadata))
 	if err != nil {
 		return errors.InternalServerError("go.vine.router", "failed to lookup routes: %s", err)
 	}
@@ -120,13 +124,14 @@ func (t *Table) Query(ctx context.Context, req *pb.QueryRequest, resp *pb.QueryR
 	respRoutes := make([]*pb.Route, 0, len(routes))
 	for _, route := range routes {
 		respRoute := &pb.Route{
-			Service: route.Service,
-			Address: route.Address,
-			Gateway: route.Gateway,
-			Network: route.Network,
-			Router:  route.Router,
-			Link:    route.Link,
-			Metric:  route.Metric,
+			Service:  route.Service,
+			Address:  route.Address,
+			Gateway:  route.Gateway,
+			Network:  route.Network,
+			Router:   route.Router,
+			Link:     route.Link,
+			Metric:   route.Metric,
+			Metadata: route.Metadata,
 		}
 		respRoutes = append(respRoutes, respRoute)
 	}
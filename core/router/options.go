@@ -23,6 +23,9 @@
 package router
 
 import (
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/lack-io/vine/core/client"
 	"github.com/lack-io/vine/core/registry"
@@ -42,8 +45,21 @@ type Options struct {
 	Registry registry.Registry
 	// Advertise is the advertising strategy
 	Advertise Strategy
+	// AdvertiseFilter only advertises the routes of the services it accepts,
+	// nil advertises all of them
+	AdvertiseFilter func(service string) bool
 	// Client for calling router
 	Client client.Client
+	// BackoffBase is the first wait before retrying a failed watch
+	BackoffBase time.Duration
+	// BackoffMax is the longest wait before retrying a failed watch
+	BackoffMax time.Duration
+	// Sleep waits out the backoff of a failed watch, it returns early
+	// when exit is closed on the router stop
+	Sleep func(d time.Duration, exit <-chan bool)
+	// RouteTTL is how long the registry routes live without being refreshed,
+	// zero never expires them
+	RouteTTL time.Duration
 }
 
 // Id sets Router Id
@@ -95,13 +111,72 @@ func Advertise(a Strategy) Option {
 	}
 }
 
+// AdvertiseFilter only advertises the routes of the services accepted by fn
+func AdvertiseFilter(fn func(service string) bool) Option {
+	return func(o *Options) {
+		o.AdvertiseFilter = fn
+	}
+}
+
+// AdvertisePrefix only advertises the routes of the services starting with
+// one of the prefixes e.g go.vine.srv.
+func AdvertisePrefix(prefixes ...string) Option {
+	return AdvertiseFilter(func(service string) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(service, p) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// WatchBackoff sets the exponential backoff of the retries of the failed
+// registry and table watches
+func WatchBackoff(base, max time.Duration) Option {
+	return func(o *Options) {
+		o.BackoffBase = base
+		o.BackoffMax = max
+	}
+}
+
+// WatchSleep sets the func waiting out the backoff of the failed watches
+func WatchSleep(fn func(d time.Duration, exit <-chan bool)) Option {
+	return func(o *Options) {
+		o.Sleep = fn
+	}
+}
+
+// RouteTTL sets how long the routes of the registry services live without
+// being refreshed, e.g. the services which crashed without deregistering
+func RouteTTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.RouteTTL = ttl
+	}
+}
+
 // DefaultOptions returns router default options
 func DefaultOptions() Options {
 	return Options{
-		Id:        uuid.New().String(),
-		Address:   DefaultAddress,
-		Network:   DefaultNetwork,
-		Registry:  registry.DefaultRegistry,
-		Advertise: AdvertiseLocal,
+		Id:          uuid.New().String(),
+		Address:     DefaultAddress,
+		Network:     DefaultNetwork,
+		Registry:    registry.DefaultRegistry,
+		Advertise:   AdvertiseLocal,
+		BackoffBase: DefaultBackoffBase,
+		BackoffMax:  DefaultBackoffMax,
+		Sleep:       sleep,
+		RouteTTL:    DefaultRouteTTL,
+	}
+}
+
+// sleep waits for the duration or until exit is closed
+func sleep(d time.Duration, exit <-chan bool) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-exit:
 	}
 }
@@ -39,6 +39,8 @@ type QueryOptions struct {
 	Router string
 	// Strategy is routing strategy
 	Strategy Strategy
+	// Metadata the routes must have
+	Metadata map[string]string
 }
 
 // QueryService sets service to query
@@ -83,6 +85,13 @@ func QueryStrategy(s Strategy) QueryOption {
 	}
 }
 
+// QueryMetadata sets the metadata key values the routes must have
+func QueryMetadata(md map[string]string) QueryOption {
+	return func(o *QueryOptions) {
+		o.Metadata = md
+	}
+}
+
 // NewQuery creates new query and returns it
 func NewQuery(opts ...QueryOption) QueryOptions {
 	// default options
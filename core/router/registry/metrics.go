@@ -0,0 +1,56 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// RoutesGauge is the number of routes in the routing tables
+	RoutesGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "vine",
+		Subsystem: "router",
+		Name:      "routes",
+		Help:      "Number of routes in the routing table.",
+	})
+
+	// AdvertsPublished counts the adverts published by the router
+	AdvertsPublished = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "vine",
+		Subsystem: "router",
+		Name:      "adverts_published_total",
+		Help:      "Total number of adverts published by the router.",
+	})
+
+	// AdvertsProcessed counts the adverts processed by the router
+	AdvertsProcessed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "vine",
+		Subsystem: "router",
+		Name:      "adverts_processed_total",
+		Help:      "Total number of adverts processed by the router.",
+	})
+)
+
+func init() {
+	// served on the /metrics endpoint of the server
+	prometheus.MustRegister(RoutesGauge, AdvertsPublished, AdvertsProcessed)
+}
@@ -28,6 +28,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -35,6 +36,7 @@ import (
 	rr "github.com/lack-io/vine/core/router"
 	log "github.com/lack-io/vine/lib/logger"
 	regpb "github.com/lack-io/vine/proto/apis/registry"
+	"github.com/lack-io/vine/util/backoff"
 )
 
 var (
@@ -46,6 +48,11 @@ var (
 
 // router implements default router
 type router struct {
+	// stats counters, first for the 64-bit alignment
+	advertsSent     uint64
+	advertsReceived uint64
+	lastSync        int64
+
 	sync.RWMutex
 
 	running   bool
@@ -53,6 +60,8 @@ type router struct {
 	options   rr.Options
 	exit      chan bool
 	eventChan chan *rr.Event
+	// wg tracks the watch goroutines Stop waits for
+	wg sync.WaitGroup
 
 	// advert subscribers
 	sub         sync.RWMutex
@@ -143,9 +152,26 @@ func (r *router) manageRoutes(service *regpb.Service, action string) error {
 			Metric:  rr.DefaultLocalMetric,
 		}
 
+		if len(node.Metadata) > 0 {
+			route.Metadata = make(map[string]string, len(node.Metadata))
+			for k, v := range node.Metadata {
+				route.Metadata[k] = v
+			}
+		}
+
 		if err := r.manageRoute(route, action); err != nil {
 			return err
 		}
+
+		// the updated node replaces its routes with the previous metadata
+		if action == "update" {
+			r.table.deleteStale(route)
+		}
+
+		// the registry routes expire unless the registry refreshes them
+		if action != "delete" {
+			r.table.expire(route)
+		}
 	}
 
 	return nil
@@ -174,12 +200,72 @@ func (r *router) manageRegistryRoutes(reg registry.Registry, action string) erro
 		}
 	}
 
+	atomic.StoreInt64(&r.lastSync, time.Now().UnixNano())
+
 	return nil
 }
 
+// retry backs off the retries of a failing watch loop
+type retry struct {
+	base     time.Duration
+	max      time.Duration
+	sleep    func(time.Duration, <-chan bool)
+	attempts int
+}
+
+func (r *router) newRetry() *retry {
+	sleep := r.options.Sleep
+	if sleep == nil {
+		sleep = rr.DefaultOptions().Sleep
+	}
+	return &retry{base: r.options.BackoffBase, max: r.options.BackoffMax, sleep: sleep}
+}
+
+// wait sleeps for an exponentially growing interval with jitter, so that
+// the routers don't reconnect in sync to a flapping registry
+func (b *retry) wait(exit chan bool) {
+	b.sleep(backoff.Exponential(b.attempts, b.base, b.max), exit)
+	b.attempts++
+}
+
+// reset starts over from the base interval once the watch works again
+func (b *retry) reset() {
+	b.attempts = 0
+}
+
+// pruneRoutes periodically deletes the registry routes not refreshed within
+// the route TTL, e.g. of the services which crashed without deregistering.
+// The routes still in the registry are refreshed before each run since not
+// every registry notifies the renewed registrations.
+func (r *router) pruneRoutes(exit chan bool) {
+	ttl := r.options.RouteTTL
+	if ttl <= 0 {
+		return
+	}
+
+	t := time.NewTicker(ttl / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-exit:
+			return
+		case <-t.C:
+			start := time.Now()
+			if err := r.manageRegistryRoutes(r.options.Registry, "update"); err != nil {
+				log.Errorf("Error refreshing the registry routes: %v", err)
+				continue
+			}
+			if n := r.table.prune(start.Add(-ttl)); n > 0 {
+				log.Debugf("Router pruned %d expired routes", n)
+			}
+		}
+	}
+}
+
 // watchRegistry watches registry and updates routing table based on the received events.
 // It returns error if either the registry watcher fails with error or if the routing table update fails.
-func (r *router) watchRegistry(w registry.Watcher) error {
+func (r *router) watchRegistry(w registry.Watcher, b *retry) error {
 	exit := make(chan bool)
 
 	defer func() {
@@ -209,6 +295,9 @@ func (r *router) watchRegistry(w registry.Watcher) error {
 		if err := r.manageRoutes(res.Service, res.Action); err != nil {
 			return err
 		}
+
+		atomic.StoreInt64(&r.lastSync, time.Now().UnixNano())
+		b.reset()
 	}
 
 	return nil
@@ -216,7 +305,7 @@ func (r *router) watchRegistry(w registry.Watcher) error {
 
 // watchTable watches routing table entries and either adds or deletes locally registered service to/from network registry
 // It returns error if the locally registered services either fails to be added/deleted to/from network registry.
-func (r *router) watchTable(w rr.Watcher) error {
+func (r *router) watchTable(w rr.Watcher, b *retry, eventChan chan *rr.Event) error {
 	exit := make(chan bool)
 
 	defer func() {
@@ -247,10 +336,11 @@ func (r *router) watchTable(w rr.Watcher) error {
 
 		select {
 		case <-r.exit:
-			close(r.eventChan)
+			close(eventChan)
 			return nil
-		case r.eventChan <- event:
+		case eventChan <- event:
 			// process event
+			b.reset()
 		}
 	}
 
@@ -267,6 +357,9 @@ func (r *router) publishAdvert(advType rr.AdvertType, events []*rr.Event) {
 		Events:    events,
 	}
 
+	atomic.AddUint64(&r.advertsSent, 1)
+	AdvertsPublished.Inc()
+
 	r.sub.RLock()
 	for _, sub := range r.subscribers {
 		// now send the message
@@ -285,7 +378,8 @@ type adverts map[uint64]*rr.Event
 
 // advertiseEvents advertises routing table events
 // It suppresses unhealthy flapping events and advertises healthy events upstream.
-func (r *router) advertiseEvents() error {
+// The event channel is passed in since Stop resets the one of the router.
+func (r *router) advertiseEvents(eventChan chan *rr.Event) error {
 	// ticker to periodically scan event for advertising
 	ticker := time.NewTicker(AdvertiseEventsTick)
 	defer ticker.Stop()
@@ -293,19 +387,25 @@ func (r *router) advertiseEvents() error {
 	// adverts is a map of advert events
 	adverts := make(adverts)
 
-	// routing table watcher
+	// routing table watcher, owned by the watch goroutine from now on
 	w, err := r.Watch()
 	if err != nil {
 		return err
 	}
-	defer w.Stop()
 
+	r.wg.Add(1)
 	go func() {
+		defer r.wg.Done()
+
 		var err error
+		b := r.newRetry()
 
 		for {
 			select {
 			case <-r.exit:
+				if w != nil {
+					w.Stop()
+				}
 				return
 			default:
 				if w == nil {
@@ -313,14 +413,14 @@ func (r *router) advertiseEvents() error {
 					w, err = r.Watch()
 					if err != nil {
 						log.Errorf("Error creating watcher: %v", err)
-						time.Sleep(time.Second)
+						b.wait(r.exit)
 						continue
 					}
 				}
 
-				if err := r.watchTable(w); err != nil {
+				if err := r.watchTable(w, b, eventChan); err != nil {
 					log.Errorf("Error watching table: %v", err)
-					time.Sleep(time.Second)
+					b.wait(r.exit)
 				}
 
 				if w != nil {
@@ -352,7 +452,7 @@ func (r *router) advertiseEvents() error {
 				// copy the event and append
 				e := new(rr.Event)
 				// this is ok, because router.Event only contains builtin types
-				// and no references so this creates a deep copy of struct Event
+				// and the route metadata which is never modified once created
 				*e = *event
 				events = append(events, e)
 				// delete the advert from adverts
@@ -364,7 +464,7 @@ func (r *router) advertiseEvents() error {
 				log.Debugf("Router publishing %d events", len(events))
 				go r.publishAdvert(rr.RouteUpdate, events)
 			}
-		case e := <-r.eventChan:
+		case e := <-eventChan:
 			// if event is nil, continue
 			if e == nil {
 				continue
@@ -380,6 +480,11 @@ func (r *router) advertiseEvents() error {
 				continue
 			}
 
+			// skip the services filtered out
+			if !r.advertises(e.Route.Service) {
+				continue
+			}
+
 			log.Debugf("Router processing table event %s for service %s %s", e.Type, e.Route.Service, e.Route.Address)
 
 			// check if we have already registered the route
@@ -396,9 +501,6 @@ func (r *router) advertiseEvents() error {
 				ev = e
 			}
 		case <-r.exit:
-			if w != nil {
-				w.Stop()
-			}
 			return nil
 		}
 	}
@@ -449,18 +551,29 @@ func (r *router) Start() error {
 	// create error and exit channels
 	r.exit = make(chan bool)
 
+	// delete the stale registry routes
+	r.wg.Add(1)
+	go func(exit chan bool) {
+		defer r.wg.Done()
+		r.pruneRoutes(exit)
+	}(r.exit)
+
 	// registry watcher
 	w, err := r.options.Registry.Watch()
 	if err != nil {
 		return fmt.Errorf("failed creating registry watcher: %v", err)
 	}
 
-	go func() {
+	r.wg.Add(1)
+	go func(exit chan bool) {
+		defer r.wg.Done()
+
 		var err error
+		b := r.newRetry()
 
 		for {
 			select {
-			case <-r.exit:
+			case <-exit:
 				if w != nil {
 					w.Stop()
 				}
@@ -470,14 +583,14 @@ func (r *router) Start() error {
 					w, err = r.options.Registry.Watch()
 					if err != nil {
 						log.Errorf("failed creating registry watcher: %v", err)
-						time.Sleep(time.Second)
+						b.wait(exit)
 						continue
 					}
 				}
 
-				if err := r.watchRegistry(w); err != nil {
+				if err := r.watchRegistry(w, b); err != nil {
 					log.Errorf("Error watching the registry: %v", err)
-					time.Sleep(time.Second)
+					b.wait(exit)
 				}
 
 				if w != nil {
@@ -486,7 +599,7 @@ func (r *router) Start() error {
 				}
 			}
 		}
-	}()
+	}(r.exit)
 
 	r.running = true
 
@@ -519,6 +632,7 @@ func (r *router) Advertise() (<-chan *rr.Advert, error) {
 
 	// create event channels
 	r.eventChan = make(chan *rr.Event)
+	eventChan := r.eventChan
 
 	// create advert channel
 	advertChan := make(chan *rr.Advert, 128)
@@ -527,12 +641,15 @@ func (r *router) Advertise() (<-chan *rr.Advert, error) {
 	// advertise your presence
 	go r.publishAdvert(rr.Announce, events)
 
+	r.wg.Add(1)
 	go func() {
+		defer r.wg.Done()
+
 		select {
 		case <-r.exit:
 			return
 		default:
-			if err := r.advertiseEvents(); err != nil {
+			if err := r.advertiseEvents(eventChan); err != nil {
 				log.Errorf("Error adveritising events: %v", err)
 			}
 		}
@@ -555,6 +672,9 @@ func (r *router) Process(a *rr.Advert) error {
 
 	log.Debugf("Router %s processing advert from: %s", r.options.Id, a.Id)
 
+	atomic.AddUint64(&r.advertsReceived, 1)
+	AdvertsProcessed.Inc()
+
 	for _, event := range events {
 		// skip if the router is the origin of this route
 		if event.Route.Router == r.options.Id {
@@ -595,6 +715,10 @@ func (r *router) flushRouteEvents(evType rr.EventType) ([]*rr.Event, error) {
 	var i int
 
 	for _, route := range routes {
+		// skip the services filtered out
+		if !r.advertises(route.Service) {
+			continue
+		}
 		event := &rr.Event{
 			Type:      evType,
 			Timestamp: time.Now(),
@@ -604,7 +728,12 @@ func (r *router) flushRouteEvents(evType rr.EventType) ([]*rr.Event, error) {
 		i++
 	}
 
-	return events, nil
+	return events[:i], nil
+}
+
+// advertises returns whether the routes of the service are advertised
+func (r *router) advertises(service string) bool {
+	return r.options.AdvertiseFilter == nil || r.options.AdvertiseFilter(service)
 }
 
 // Lookup routes in the routing table
@@ -617,13 +746,28 @@ func (r *router) Watch(opts ...rr.WatchOption) (rr.Watcher, error) {
 	return r.table.Watch(opts...)
 }
 
-// Stop stops the router
+// Stats returns the router statistics
+func (r *router) Stats() (*rr.Stats, error) {
+	stats := &rr.Stats{
+		Routes:          r.table.size(),
+		Events:          atomic.LoadUint64(&r.table.events),
+		AdvertsSent:     atomic.LoadUint64(&r.advertsSent),
+		AdvertsReceived: atomic.LoadUint64(&r.advertsReceived),
+	}
+	if ts := atomic.LoadInt64(&r.lastSync); ts > 0 {
+		stats.LastSync = time.Unix(0, ts)
+	}
+
+	return stats, nil
+}
+
+// Stop stops the router and waits for its watch goroutines to exit
 func (r *router) Stop() error {
 	r.Lock()
-	defer r.Unlock()
 
 	select {
 	case <-r.exit:
+		r.Unlock()
 		return nil
 	default:
 		close(r.exit)
@@ -644,6 +788,10 @@ func (r *router) Stop() error {
 
 	// remove event chan
 	r.eventChan = nil
+	r.Unlock()
+
+	// the watchers stop on exit, wait outside the lock for them to return
+	r.wg.Wait()
 
 	return nil
 }
@@ -0,0 +1,471 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/core/registry"
+	"github.com/lack-io/vine/core/registry/memory"
+	rr "github.com/lack-io/vine/core/router"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestStats(t *testing.T) {
+	reg := memory.NewRegistry()
+	if err := reg.Register(&regpb.Service{
+		Name:  "go.vine.foo",
+		Nodes: []*regpb.Node{{Id: "foo-1", Address: "10.0.0.1:8080"}, {Id: "foo-2", Address: "10.0.0.2:8080"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRouter(rr.Registry(reg))
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	stats, err := r.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Routes != 2 {
+		t.Fatalf("Expected 2 routes, got %d", stats.Routes)
+	}
+	if stats.LastSync.IsZero() {
+		t.Fatal("Expected the last sync to be set")
+	}
+
+	if err := r.Process(&rr.Advert{Id: "remote", Events: []*rr.Event{
+		{Type: rr.Create, Route: rr.Route{Service: "go.vine.bar", Address: "10.0.1.1:8080", Router: "remote", Link: "remote"}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, _ = r.Stats()
+	if stats.Routes != 3 || stats.AdvertsReceived != 1 {
+		t.Fatalf("Expected 3 routes and 1 advert received, got %+v", stats)
+	}
+
+	entries, err := r.Table().(rr.Entries).Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for _, e := range entries {
+		if e.Updated.IsZero() {
+			t.Fatalf("Expected the update time of %s to be set", e.Address)
+		}
+	}
+}
+
+func TestLookupMetadata(t *testing.T) {
+	reg := memory.NewRegistry()
+	if err := reg.Register(&regpb.Service{
+		Name: "go.vine.foo",
+		Nodes: []*regpb.Node{
+			{Id: "foo-1", Address: "10.0.0.1:8080", Metadata: map[string]string{"version": "stable"}},
+			{Id: "foo-2", Address: "10.0.0.2:8080", Metadata: map[string]string{"version": "canary"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRouter(rr.Registry(reg))
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	routes, err := r.Lookup(rr.QueryService("go.vine.foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %d", len(routes))
+	}
+
+	routes, err = r.Lookup(rr.QueryService("go.vine.foo"), rr.QueryMetadata(map[string]string{"version": "canary"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 || routes[0].Address != "10.0.0.2:8080" {
+		t.Fatalf("Expected the canary route, got %+v", routes)
+	}
+
+	routes, err = r.Lookup(rr.QueryService("go.vine.foo"), rr.QueryMetadata(map[string]string{"version": "beta"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 0 {
+		t.Fatalf("Expected no routes, got %d", len(routes))
+	}
+}
+
+func TestUpdateMetadata(t *testing.T) {
+	service := &regpb.Service{
+		Name: "go.vine.foo",
+		Nodes: []*regpb.Node{
+			{Id: "foo-1", Address: "10.0.0.1:8080", Metadata: map[string]string{"version": "stable"}},
+			{Id: "foo-2", Address: "10.0.0.2:8080", Metadata: map[string]string{"version": "stable"}},
+		},
+	}
+	reg := memory.NewRegistry()
+	if err := reg.Register(service); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRouter(rr.Registry(reg))
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	// the update of the metadata of a node replaces its route
+	service.Nodes[0].Metadata = map[string]string{"version": "canary"}
+	if err := r.(*router).manageRoutes(service, "update"); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := r.Lookup(rr.QueryService("go.vine.foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %+v", routes)
+	}
+
+	routes, err = r.Lookup(rr.QueryService("go.vine.foo"), rr.QueryAddress("10.0.0.1:8080"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 || routes[0].Metadata["version"] != "canary" {
+		t.Fatalf("Expected the canary route of the updated node, got %+v", routes)
+	}
+}
+
+func metricValue(t *testing.T, m prometheus.Metric) float64 {
+	var v dto.Metric
+	if err := m.Write(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Gauge != nil {
+		return v.Gauge.GetValue()
+	}
+	return v.Counter.GetValue()
+}
+
+func TestMetrics(t *testing.T) {
+	routes := metricValue(t, RoutesGauge)
+	processed := metricValue(t, AdvertsProcessed)
+
+	reg := memory.NewRegistry()
+	if err := reg.Register(&regpb.Service{
+		Name:  "go.vine.foo",
+		Nodes: []*regpb.Node{{Id: "foo-1", Address: "10.0.0.1:8080"}, {Id: "foo-2", Address: "10.0.0.2:8080"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRouter(rr.Registry(reg))
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	if v := metricValue(t, RoutesGauge) - routes; v != 2 {
+		t.Fatalf("Expected the routes gauge to grow by 2, got %v", v)
+	}
+
+	route := rr.Route{Service: "go.vine.bar", Address: "10.0.1.1:8080", Router: "remote", Link: "remote"}
+	if err := r.Process(&rr.Advert{Id: "remote", Events: []*rr.Event{{Type: rr.Create, Route: route}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Process(&rr.Advert{Id: "remote", Events: []*rr.Event{{Type: rr.Delete, Route: route}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := metricValue(t, RoutesGauge) - routes; v != 2 {
+		t.Fatalf("Expected the routes gauge to grow by 2, got %v", v)
+	}
+	if v := metricValue(t, AdvertsProcessed) - processed; v != 2 {
+		t.Fatalf("Expected 2 adverts processed, got %v", v)
+	}
+}
+
+// failingRegistry returns a watcher failing right away, then fails to watch
+type failingRegistry struct {
+	registry.Registry
+
+	sync.Mutex
+	watches int
+}
+
+type failingWatcher struct{}
+
+func (failingWatcher) Next() (*regpb.Result, error) {
+	return nil, errors.New("watch failed")
+}
+
+func (failingWatcher) Stop() {}
+
+func (f *failingRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.watches++
+	if f.watches == 1 {
+		return failingWatcher{}, nil
+	}
+	return nil, errors.New("registry unavailable")
+}
+
+func TestWatchBackoff(t *testing.T) {
+	var mu sync.Mutex
+	var waits []time.Duration
+	done := make(chan bool)
+
+	sleep := func(d time.Duration, exit <-chan bool) {
+		mu.Lock()
+		waits = append(waits, d)
+		n := len(waits)
+		mu.Unlock()
+
+		if n == 6 {
+			close(done)
+		}
+		if n >= 6 {
+			<-exit
+		}
+	}
+
+	base, max := time.Millisecond*10, time.Millisecond*200
+	r := NewRouter(
+		rr.Registry(&failingRegistry{Registry: memory.NewRegistry()}),
+		rr.WatchBackoff(base, max),
+		rr.WatchSleep(sleep),
+	)
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Expected the watch to be retried")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// each wait is within the upper half of the doubled interval, up to max
+	interval := base
+	for i, d := range waits[:6] {
+		if d < interval/2 || d > interval {
+			t.Fatalf("Expected wait %d within [%v, %v], got %v", i, interval/2, interval, d)
+		}
+		if interval *= 2; interval > max {
+			interval = max
+		}
+	}
+}
+
+func TestAdvertiseFilter(t *testing.T) {
+	defer func(tick time.Duration) {
+		AdvertiseEventsTick = tick
+	}(AdvertiseEventsTick)
+	AdvertiseEventsTick = time.Millisecond * 10
+
+	register := func(reg registry.Registry, name, address string) {
+		if err := reg.Register(&regpb.Service{
+			Name:  name,
+			Nodes: []*regpb.Node{{Id: name + "-1", Address: address}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reg := memory.NewRegistry()
+	register(reg, "go.vine.srv.foo", "10.0.0.1:8080")
+	register(reg, "go.vine.api.foo", "10.0.0.2:8080")
+
+	r := NewRouter(rr.Registry(reg), rr.Advertise(rr.AdvertiseAll), rr.AdvertisePrefix("go.vine.srv."))
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	ch, err := r.Advertise()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the table watcher of the adverts starts in the background, the
+	// registrations made before it runs would never be advertised
+	tb := r.(*router).table
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		tb.RLock()
+		n := len(tb.watchers)
+		tb.RUnlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the routing table to be watched")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// the later registrations are advertised as events
+	register(reg, "go.vine.api.bar", "10.0.0.3:8080")
+	register(reg, "go.vine.srv.bar", "10.0.0.4:8080")
+
+	advertised := make(map[string]bool)
+	timeout := time.After(time.Second * 5)
+	for !advertised["go.vine.srv.bar"] {
+		select {
+		case a := <-ch:
+			for _, e := range a.Events {
+				advertised[e.Route.Service] = true
+			}
+		case <-timeout:
+			t.Fatalf("Expected go.vine.srv.bar to be advertised, got %v", advertised)
+		}
+	}
+
+	if !advertised["go.vine.srv.foo"] {
+		t.Fatalf("Expected go.vine.srv.foo to be advertised, got %v", advertised)
+	}
+	for service := range advertised {
+		if !strings.HasPrefix(service, "go.vine.srv.") {
+			t.Fatalf("Expected %s to be filtered out of the adverts", service)
+		}
+	}
+}
+
+// silentRegistry never notifies the changes of the services
+type silentRegistry struct {
+	registry.Registry
+}
+
+type silentWatcher struct {
+	once sync.Once
+	exit chan struct{}
+}
+
+func (w *silentWatcher) Next() (*regpb.Result, error) {
+	<-w.exit
+	return nil, registry.ErrWatcherStopped
+}
+
+func (w *silentWatcher) Stop() {
+	w.once.Do(func() { close(w.exit) })
+}
+
+func (s *silentRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
+	return &silentWatcher{exit: make(chan struct{})}, nil
+}
+
+func TestRouteTTL(t *testing.T) {
+	reg := memory.NewRegistry()
+	foo := &regpb.Service{
+		Name:  "go.vine.foo",
+		Nodes: []*regpb.Node{{Id: "foo-1", Address: "10.0.0.1:8080"}},
+	}
+	bar := &regpb.Service{
+		Name:  "go.vine.bar",
+		Nodes: []*regpb.Node{{Id: "bar-1", Address: "10.0.0.2:8080"}},
+	}
+	for _, s := range []*regpb.Service{foo, bar} {
+		if err := reg.Register(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ttl := 100 * time.Millisecond
+	r := NewRouter(rr.Registry(&silentRegistry{reg}), rr.Gateway("10.0.0.254:8080"), rr.RouteTTL(ttl))
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	static := rr.Route{Service: "go.vine.static", Address: "10.0.0.3:8080", Link: rr.DefaultLink}
+	if err := r.Table().Create(static); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := r.Watch(rr.WatchService("go.vine.foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// foo crashes, the registry expires it without telling the router
+	if err := reg.Deregister(foo); err != nil {
+		t.Fatal(err)
+	}
+
+	// skip the events of the routes created on start
+	for {
+		event, err := w.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Type == rr.Delete {
+			if event.Route.Address != "10.0.0.1:8080" {
+				t.Fatalf("Expected the delete of the foo route, got %+v", event.Route)
+			}
+			break
+		}
+	}
+
+	// bar is refreshed from the registry, past its TTL as well
+	time.Sleep(2 * ttl)
+
+	routes, err := r.Table().List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]bool)
+	for _, route := range routes {
+		found[route.Service] = true
+	}
+	if found["go.vine.foo"] {
+		t.Fatal("Expected the foo route to be pruned")
+	}
+	for _, service := range []string{"go.vine.bar", "go.vine.static", "*"} {
+		if !found[service] {
+			t.Fatalf("Expected the %s route to be kept, got %+v", service, routes)
+		}
+	}
+}
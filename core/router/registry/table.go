@@ -25,6 +25,7 @@ package registry
 import (
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -41,9 +42,16 @@ var (
 
 // table is an in-memory routing table
 type table struct {
+	// events is the number of events sent, first for the 64-bit alignment
+	events uint64
+
 	sync.RWMutex
 	// routes stores service routes
 	routes map[string]map[uint64]rr.Route
+	// updated stores the time the routes were last created or updated
+	updated map[uint64]time.Time
+	// expiring stores the routes pruned when they are not refreshed in time
+	expiring map[uint64]struct{}
 	// watchers stores table watchers
 	watchers map[string]*tableWatcher
 }
@@ -52,6 +60,8 @@ type table struct {
 func newTable(opts ...rr.Option) *table {
 	return &table{
 		routes:   make(map[string]map[uint64]rr.Route),
+		updated:  make(map[uint64]time.Time),
+		expiring: make(map[uint64]struct{}),
 		watchers: make(map[string]*tableWatcher),
 	}
 }
@@ -65,6 +75,8 @@ func (t *table) sendEvent(e *rr.Event) {
 		e.Id = uuid.New().String()
 	}
 
+	atomic.AddUint64(&t.events, 1)
+
 	for _, w := range t.watchers {
 		select {
 		case w.resChan <- e:
@@ -91,6 +103,8 @@ func (t *table) Create(route rr.Route) error {
 	// add new route to the table for the route destination
 	if _, ok := t.routes[service][sum]; !ok {
 		t.routes[service][sum] = route
+		t.updated[sum] = time.Now()
+		RoutesGauge.Inc()
 		log.Debugf("Router emitting %s for route: %s", rr.Create, rr.Address)
 		go t.sendEvent(&rr.Event{Type: rr.Create, Timestamp: time.Now(), Route: route})
 		return nil
@@ -116,6 +130,9 @@ func (t *table) Delete(route rr.Route) error {
 	}
 
 	delete(t.routes[service], sum)
+	delete(t.updated, sum)
+	delete(t.expiring, sum)
+	RoutesGauge.Dec()
 	log.Debugf("Router emitting %s for route: %s", rr.Delete, rr.Address)
 	go t.sendEvent(&rr.Event{Type: rr.Delete, Timestamp: time.Now(), Route: route})
 
@@ -135,8 +152,11 @@ func (t *table) Update(route rr.Route) error {
 		t.routes[service] = make(map[uint64]rr.Route)
 	}
 
+	t.updated[sum] = time.Now()
+
 	if _, ok := t.routes[service][sum]; !ok {
 		t.routes[service][sum] = route
+		RoutesGauge.Inc()
 		log.Debugf("Router emitting %s for route: %s", rr.Update, rr.Address)
 		go t.sendEvent(&rr.Event{Type: rr.Update, Timestamp: time.Now(), Route: route})
 		return nil
@@ -148,6 +168,75 @@ func (t *table) Update(route rr.Route) error {
 	return nil
 }
 
+// deleteStale deletes the routes of the service at the address of the route
+// other than the route itself, e.g. the ones with the metadata of the node
+// before it was updated, and returns the number of routes deleted
+func (t *table) deleteStale(route rr.Route) int {
+	sum := route.Hash()
+
+	t.Lock()
+	defer t.Unlock()
+
+	var deleted int
+	for hash, r := range t.routes[route.Service] {
+		if hash == sum || r.Address != route.Address || r.Gateway != route.Gateway ||
+			r.Network != route.Network || r.Router != route.Router || r.Link != route.Link {
+			continue
+		}
+
+		delete(t.routes[route.Service], hash)
+		delete(t.updated, hash)
+		delete(t.expiring, hash)
+		RoutesGauge.Dec()
+		deleted++
+		log.Debugf("Router emitting %s for stale route: %s", rr.Delete, r.Address)
+		go t.sendEvent(&rr.Event{Type: rr.Delete, Timestamp: time.Now(), Route: r})
+	}
+
+	return deleted
+}
+
+// expire marks the route to be pruned when it's not refreshed in time
+func (t *table) expire(route rr.Route) {
+	sum := route.Hash()
+
+	t.Lock()
+	defer t.Unlock()
+
+	if _, ok := t.routes[route.Service][sum]; ok {
+		t.expiring[sum] = struct{}{}
+	}
+}
+
+// prune deletes the expiring routes last refreshed before the given time
+// and returns the number of routes deleted
+func (t *table) prune(before time.Time) int {
+	t.Lock()
+	defer t.Unlock()
+
+	var pruned int
+	for _, routes := range t.routes {
+		for sum, route := range routes {
+			if _, ok := t.expiring[sum]; !ok {
+				continue
+			}
+			if !t.updated[sum].Before(before) {
+				continue
+			}
+
+			delete(routes, sum)
+			delete(t.updated, sum)
+			delete(t.expiring, sum)
+			RoutesGauge.Dec()
+			pruned++
+			log.Debugf("Router emitting %s for expired route: %s", rr.Delete, route.Address)
+			go t.sendEvent(&rr.Event{Type: rr.Delete, Timestamp: time.Now(), Route: route})
+		}
+	}
+
+	return pruned
+}
+
 // List returns a list of all routes in the table
 func (t *table) List() ([]rr.Route, error) {
 	t.RLock()
@@ -163,8 +252,35 @@ func (t *table) List() ([]rr.Route, error) {
 	return routes, nil
 }
 
+// Entries returns all the routes in the table with the time they were last updated
+func (t *table) Entries() ([]rr.Entry, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	var entries []rr.Entry
+	for _, rmap := range t.routes {
+		for sum, route := range rmap {
+			entries = append(entries, rr.Entry{Route: route, Updated: t.updated[sum]})
+		}
+	}
+
+	return entries, nil
+}
+
+// size returns the number of routes in the table
+func (t *table) size() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	var n int
+	for _, rmap := range t.routes {
+		n += len(rmap)
+	}
+	return n
+}
+
 // isMatch checks if the route matches given query options
-func isMatch(route rr.Route, address, gateway, network, router string, strategy rr.Strategy) bool {
+func isMatch(route rr.Route, address, gateway, network, router string, metadata map[string]string, strategy rr.Strategy) bool {
 	// matches the values provided
 	match := func(a, b string) bool {
 		return a == "*" || a == b
@@ -199,16 +315,16 @@ func isMatch(route rr.Route, address, gateway, network, router string, strategy
 		}
 	}
 
-	return true
+	return route.HasMetadata(metadata)
 }
 
 // findRoutes finds all the routes for given network and router and returns them
-func findRoutes(routes map[uint64]rr.Route, address, gateway, network, router string, strategy rr.Strategy) []rr.Route {
+func findRoutes(routes map[uint64]rr.Route, address, gateway, network, router string, metadata map[string]string, strategy rr.Strategy) []rr.Route {
 	// routeMap stores the routes we're going to advertise
 	routeMap := make(map[string][]rr.Route)
 
 	for _, route := range routes {
-		if isMatch(route, address, gateway, network, router, strategy) {
+		if isMatch(route, address, gateway, network, router, metadata, strategy) {
 			// add matching route to the routeMap
 			routeKey := route.Service + "@" + route.Network
 			// append the first found route to routeMap
@@ -266,12 +382,12 @@ func (t *table) Query(q ...rr.QueryOption) ([]rr.Route, error) {
 		if _, ok := t.routes[opts.Service]; !ok {
 			return nil, ErrRouteNotFound
 		}
-		return findRoutes(t.routes[opts.Service], opts.Address, opts.Gateway, opts.Network, opts.Router, opts.Strategy), nil
+		return findRoutes(t.routes[opts.Service], opts.Address, opts.Gateway, opts.Network, opts.Router, opts.Metadata, opts.Strategy), nil
 	}
 
 	// search through all destinations
 	for _, routes := range t.routes {
-		results = append(results, findRoutes(routes, opts.Address, opts.Gateway, opts.Network, opts.Router, opts.Strategy)...)
+		results = append(results, findRoutes(routes, opts.Address, opts.Gateway, opts.Network, opts.Router, opts.Metadata, opts.Strategy)...)
 	}
 
 	return results, nil
@@ -22,7 +22,10 @@
 
 package router
 
-import "hash/fnv"
+import (
+	"hash/fnv"
+	"sort"
+)
 
 var (
 	// DefaultLink is default network link
@@ -47,12 +50,35 @@ type Route struct {
 	Link string
 	// Metric is the route cost metric
 	Metric int64
+	// Metadata is the metadata of the service node
+	Metadata map[string]string
 }
 
-// Hash returns route hash sum
+// Hash returns route hash sum, the nodes on the same address with different
+// metadata are different routes
 func (r *Route) Hash() uint64 {
 	h := fnv.New64()
 	h.Reset()
 	h.Write([]byte(r.Service + r.Address + r.Gateway + r.Network + r.Router + r.Link))
+
+	keys := make([]string, 0, len(r.Metadata))
+	for k := range r.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		h.Write([]byte(k + "=" + r.Metadata[k]))
+	}
+
 	return h.Sum64()
 }
+
+// HasMetadata returns whether the route has all the metadata key values
+func (r *Route) HasMetadata(md map[string]string) bool {
+	for k, v := range md {
+		if val, ok := r.Metadata[k]; !ok || val != v {
+			return false
+		}
+	}
+	return true
+}
@@ -44,3 +44,30 @@ func TestHash(t *testing.T) {
 		t.Errorf("identical routes result in different hashes")
 	}
 }
+
+func TestHashMetadata(t *testing.T) {
+	route1 := Route{
+		Service:  "dest.svc",
+		Address:  "10.0.0.1:8080",
+		Metadata: map[string]string{"version": "stable", "zone": "a"},
+	}
+
+	route2 := route1
+	route2.Metadata = map[string]string{"zone": "a", "version": "stable"}
+
+	if route1.Hash() != route2.Hash() {
+		t.Errorf("routes with the same metadata result in different hashes")
+	}
+
+	route2.Metadata = map[string]string{"version": "canary", "zone": "a"}
+	if route1.Hash() == route2.Hash() {
+		t.Errorf("routes with different metadata result in the same hash")
+	}
+
+	if !route1.HasMetadata(map[string]string{"version": "stable"}) {
+		t.Errorf("expected route to have the version metadata")
+	}
+	if route1.HasMetadata(map[string]string{"version": "canary"}) {
+		t.Errorf("expected route not to match a different version")
+	}
+}
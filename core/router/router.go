@@ -33,6 +33,13 @@ var (
 	DefaultNetwork = "go.vine"
 	// DefaultRouter is default network router
 	DefaultRouter Router
+	// DefaultBackoffBase is the first wait before retrying a failed watch
+	DefaultBackoffBase = time.Second
+	// DefaultBackoffMax is the longest wait before retrying a failed watch
+	DefaultBackoffMax = 30 * time.Second
+	// DefaultRouteTTL is how long the registry routes live without being
+	// refreshed, the same as the default registration TTL of the servers
+	DefaultRouteTTL = 90 * time.Second
 )
 
 // Router is an interface for a routing control plane
@@ -53,6 +60,8 @@ type Router interface {
 	Watch(opts ...WatchOption) (Watcher, error)
 	// Start starts the router
 	Start() error
+	// Stats returns the router statistics
+	Stats() (*Stats, error)
 	// Stop stops the router
 	Stop() error
 	// String returns the router implementation
@@ -73,6 +82,34 @@ type Table interface {
 	Query(...QueryOption) ([]Route, error)
 }
 
+// Entries is implemented by the routing tables which keep track of when
+// their routes were last created or updated
+type Entries interface {
+	// Entries returns all the routes in the table
+	Entries() ([]Entry, error)
+}
+
+// Entry is a route of the routing table
+type Entry struct {
+	Route
+	// Updated is the time the route was last created or updated
+	Updated time.Time
+}
+
+// Stats are the statistics of a router
+type Stats struct {
+	// Routes is the number of routes in the table
+	Routes int
+	// Events is the number of routing table events processed
+	Events uint64
+	// AdvertsSent is the number of adverts sent
+	AdvertsSent uint64
+	// AdvertsReceived is the number of adverts received
+	AdvertsReceived uint64
+	// LastSync is the time of the last registry sync
+	LastSync time.Time
+}
+
 type Option func(*Options)
 
 // StatusCode defines router status
@@ -24,6 +24,7 @@ package grpc
 
 import (
 	b "bytes"
+	"context"
 	"strings"
 
 	"github.com/gogo/protobuf/proto"
@@ -34,6 +35,8 @@ import (
 
 	"github.com/lack-io/vine/core/codec"
 	"github.com/lack-io/vine/core/codec/bytes"
+	"github.com/lack-io/vine/lib/api/marshal"
+	meta "github.com/lack-io/vine/util/context/metadata"
 	"github.com/lack-io/vine/util/jsonpb"
 )
 
@@ -114,6 +117,28 @@ func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
 	return json.Marshal(v)
 }
 
+// marshalReply renders a json reply with the options requested through the
+// request metadata, typically by the api gateway. The reply is returned as is
+// when there are none so the codec renders it.
+func marshalReply(ctx context.Context, ct string, reply interface{}) (interface{}, error) {
+	if ct != "application/json" && ct != "application/grpc+json" {
+		return reply, nil
+	}
+	v, ok := meta.Get(ctx, marshal.MetadataKey)
+	if !ok || len(v) == 0 {
+		return reply, nil
+	}
+	opts, err := marshal.Parse(v)
+	if err != nil {
+		return reply, nil
+	}
+	data, err := opts.Marshal(reply)
+	if err != nil {
+		return nil, err
+	}
+	return &bytes.Frame{Data: data}, nil
+}
+
 func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
 	if len(data) == 0 {
 		return nil
@@ -31,6 +31,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"os"
 	"reflect"
 	"runtime/debug"
 	"sort"
@@ -51,6 +52,8 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/lack-io/vine/core/broker"
+	// decompress the gzip compressed calls
+	_ "github.com/lack-io/vine/core/codec/gzip"
 	"github.com/lack-io/vine/core/registry"
 	"github.com/lack-io/vine/core/server"
 	log "github.com/lack-io/vine/lib/logger"
@@ -176,6 +179,35 @@ func (g *grpcServer) getMaxMsgSize() int {
 	return s
 }
 
+// checkHeader rejects the metadata exceeding the maximum header size or count
+func (g *grpcServer) checkHeader(md metadata.MD) error {
+	if g.opts.Context == nil {
+		return nil
+	}
+	limit, ok := g.opts.Context.Value(maxHeaderKey{}).(maxHeader)
+	if !ok {
+		return nil
+	}
+
+	if limit.count > 0 && len(md) > limit.count {
+		return status.Errorf(codes.ResourceExhausted, "request has %d headers, exceeding the limit of %d", len(md), limit.count)
+	}
+
+	if limit.size > 0 {
+		size := 0
+		for k, vals := range md {
+			for _, v := range vals {
+				size += len(k) + len(v)
+			}
+		}
+		if size > limit.size {
+			return status.Errorf(codes.ResourceExhausted, "request headers of %d bytes exceed the limit of %d bytes", size, limit.size)
+		}
+	}
+
+	return nil
+}
+
 func (g *grpcServer) getCredentials() credentials.TransportCredentials {
 	if g.opts.Context != nil {
 		if v, ok := g.opts.Context.Value(tlsAuth{}).(*tls.Config); ok && v != nil {
@@ -233,6 +265,16 @@ func (g *grpcServer) getListener() net.Listener {
 	return nil
 }
 
+func (g *grpcServer) getUnixSocket() (string, os.FileMode) {
+	if g.opts.Context == nil {
+		return "", 0
+	}
+
+	path, _ := g.opts.Context.Value(unixSocketKey{}).(string)
+	mode, _ := g.opts.Context.Value(unixSocketModeKey{}).(os.FileMode)
+	return path, mode
+}
+
 func (g *grpcServer) handler(svc interface{}, stream grpc.ServerStream) error {
 	if g.wg != nil {
 		g.wg.Add(1)
@@ -255,6 +297,10 @@ func (g *grpcServer) handler(svc interface{}, stream grpc.ServerStream) error {
 		gmd = metadata.MD{}
 	}
 
+	if err := g.checkHeader(gmd); err != nil {
+		return err
+	}
+
 	// copy the metadata to vine.metadata
 	md := meta.Metadata{}
 	for k, v := range gmd {
@@ -483,7 +529,12 @@ func (g *grpcServer) processRequest(stream grpc.ServerStream, service *service,
 			return errStatus.Err()
 		}
 
-		if err := stream.SendMsg(replyv.Interface()); err != nil {
+		reply, err := marshalReply(ctx, ct, replyv.Interface())
+		if err != nil {
+			return err
+		}
+
+		if err := stream.SendMsg(reply); err != nil {
 			return err
 		}
 
@@ -574,6 +625,25 @@ func (g *grpcServer) newGRPCCodec(contentType string) (encoding.Codec, error) {
 	return nil, fmt.Errorf("unsupported Content-Type: %s", contentType)
 }
 
+// debugMux serves the metrics and the debug endpoints next to the grpc service
+func (g *grpcServer) debugMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	if handlers, ok := g.Options().Context.Value(debugHandlersKey{}).(map[string]http.Handler); ok {
+		for pattern, h := range handlers {
+			mux.Handle(pattern, h)
+		}
+	}
+
+	return mux
+}
+
 func (g *grpcServer) Options() server.Options {
 	g.RLock()
 	opts := g.opts
@@ -677,35 +747,51 @@ func (g *grpcServer) Register() error {
 		advt = config.Address
 	}
 
-	if cnt := strings.Count(advt, ":"); cnt >= 1 {
-		// ipv6 address in format [host]:port or ipv4 host:port
-		host, port, err = net.SplitHostPort(advt)
-		if err != nil {
-			return err
-		}
-	} else {
-		host = advt
-	}
-
-	if ip := net.ParseIP(host); ip != nil {
-		cacheService = true
-	}
-
-	saddr, err := addr.Extract(host)
-	if err != nil {
-		return err
-	}
-
 	// make copy of metadata
 	md := meta.Copy(config.Metadata)
 
 	// register service
 	node := &regpb.Node{
 		Id:       config.Name + "-" + config.Id,
-		Address:  mnet.HostPort(saddr, port),
 		Metadata: md,
 	}
 
+	if mnet.IsUnix(advt) {
+		node.Address = advt
+		cacheService = true
+	} else {
+		if cnt := strings.Count(advt, ":"); cnt >= 1 {
+			// ipv6 address in format [host]:port or ipv4 host:port
+			host, port, err = net.SplitHostPort(advt)
+			if err != nil {
+				return err
+			}
+		} else {
+			host = advt
+		}
+
+		if ip := net.ParseIP(host); ip != nil {
+			cacheService = true
+		}
+
+		saddr, err := addr.Extract(host)
+		if err != nil {
+			return err
+		}
+		node.Address = mnet.HostPort(saddr, port)
+	}
+
+	// advertise the unix socket to callers on the same host
+	path, _ := g.getUnixSocket()
+	if mnet.IsUnix(config.Address) {
+		path = mnet.UnixPath(config.Address)
+	}
+	if len(path) > 0 {
+		hostname, _ := os.Hostname()
+		node.Metadata["unix"] = path
+		node.Metadata["hostname"] = hostname
+	}
+
 	node.Metadata["broker"] = config.Broker.String()
 	node.Metadata["registry"] = config.Registry.String()
 	node.Metadata["server"] = g.String()
@@ -822,24 +908,28 @@ func (g *grpcServer) Deregister() error {
 		advt = config.Address
 	}
 
-	if cnt := strings.Count(advt, ":"); cnt >= 1 {
-		// ipv6 address in format [host]:port or ipv4 host:port
-		host, port, err = net.SplitHostPort(advt)
-		if err != nil {
-			return err
-		}
-	} else {
-		host = advt
+	node := &regpb.Node{
+		Id: config.Name + "-" + config.Id,
 	}
 
-	addr, err := addr.Extract(host)
-	if err != nil {
-		return err
-	}
+	if mnet.IsUnix(advt) {
+		node.Address = advt
+	} else {
+		if cnt := strings.Count(advt, ":"); cnt >= 1 {
+			// ipv6 address in format [host]:port or ipv4 host:port
+			host, port, err = net.SplitHostPort(advt)
+			if err != nil {
+				return err
+			}
+		} else {
+			host = advt
+		}
 
-	node := &regpb.Node{
-		Id:      config.Name + "-" + config.Id,
-		Address: mnet.HostPort(addr, port),
+		addr, err := addr.Extract(host)
+		if err != nil {
+			return err
+		}
+		node.Address = mnet.HostPort(addr, port)
 	}
 
 	service := &regpb.Service{
@@ -899,8 +989,16 @@ func (g *grpcServer) Start() error {
 	} else {
 		var err error
 
-		// check the tls config for secure connect
-		if tc := config.TLSConfig; tc != nil {
+		_, mode := g.getUnixSocket()
+
+		// listen on the unix domain socket
+		if mnet.IsUnix(config.Address) {
+			ts, err = mnet.ListenUnix(mnet.UnixPath(config.Address), mode)
+			if err == nil && config.TLSConfig != nil {
+				ts = tls.NewListener(ts, config.TLSConfig)
+			}
+			// check the tls config for secure connect
+		} else if tc := config.TLSConfig; tc != nil {
 			ts, err = tls.Listen("tcp", config.Address, tc)
 			// otherwise just plain tcp listener
 		} else {
@@ -911,16 +1009,33 @@ func (g *grpcServer) Start() error {
 		}
 	}
 
+	// additional unix domain socket for local callers
+	var us net.Listener
+	if path, mode := g.getUnixSocket(); len(path) > 0 && !mnet.IsUnix(config.Address) {
+		var err error
+		us, err = mnet.ListenUnix(path, mode)
+		if err != nil {
+			ts.Close()
+			return err
+		}
+		log.Infof("Server [grpc] Listening on %s", mnet.UnixAddr(path))
+	}
+
 	if g.opts.Context != nil {
 		if c, ok := g.opts.Context.Value(maxConnKey{}).(int); ok && c > 0 {
 			ts = netutil.LimitListener(ts, c)
 		}
 	}
 
-	log.Infof("Server [grpc] Listening on %s", ts.Addr().String())
+	address := ts.Addr().String()
+	if ts.Addr().Network() == "unix" {
+		address = mnet.UnixAddr(address)
+	}
+
+	log.Infof("Server [grpc] Listening on %s", address)
 
 	g.RLock()
-	g.opts.Address = ts.Addr().String()
+	g.opts.Address = address
 	g.RUnlock()
 
 	// only connect if we're subscribed
@@ -944,13 +1059,7 @@ func (g *grpcServer) Start() error {
 		if v := g.Options().Context.Value(Grpc2Http{}); v != nil {
 			gh := v.(*Grpc2Http)
 
-			mux := http.NewServeMux()
-			mux.Handle("/metrics", promhttp.Handler())
-			mux.HandleFunc("/debug/pprof/", pprof.Index)
-			mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-			mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
-			mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
-			mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+			mux := g.debugMux()
 
 			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				if r.ProtoMajor == 2 && strings.Contains(r.Header.Get("Content-Type"), "application/grpc") {
@@ -977,6 +1086,14 @@ func (g *grpcServer) Start() error {
 		}
 	}()
 
+	if us != nil {
+		go func() {
+			if err := g.svc.Serve(us); err != nil {
+				log.Errorf("gRPC Server start error: %v", err)
+			}
+		}()
+	}
+
 	go func() {
 		t := new(time.Ticker)
 
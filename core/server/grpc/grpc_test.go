@@ -0,0 +1,175 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	bmemory "github.com/lack-io/vine/core/broker/memory"
+	"github.com/lack-io/vine/core/client"
+	cgrpc "github.com/lack-io/vine/core/client/grpc"
+	"github.com/lack-io/vine/core/client/selector"
+	"github.com/lack-io/vine/core/registry/memory"
+	"github.com/lack-io/vine/core/server"
+	"github.com/lack-io/vine/util/context/metadata"
+)
+
+type Message struct {
+	Say string `json:"say"`
+}
+
+type Echo struct{}
+
+func (e *Echo) Say(ctx context.Context, req *Message, rsp *Message) error {
+	rsp.Say = req.Say
+	return nil
+}
+
+type Streamer struct{}
+
+// Stream blocks until the client goes away
+func (s *Streamer) Stream(ctx context.Context, stream server.Stream) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestMaxHeaderSize(t *testing.T) {
+	reg := memory.NewRegistry()
+
+	srv := NewServer(
+		server.Name("go.vine.echo"),
+		server.Address("127.0.0.1:0"),
+		server.Registry(reg),
+		server.Broker(bmemory.NewBroker()),
+		MaxHeaderSize(1024, 20),
+	)
+	if err := srv.Handle(srv.NewHandler(&Echo{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	c := cgrpc.NewClient(
+		client.Registry(reg),
+		client.Selector(selector.NewSelector(selector.Registry(reg))),
+	)
+
+	call := func(md metadata.Metadata) error {
+		ctx := metadata.NewContext(context.Background(), md)
+		req := c.NewRequest("go.vine.echo", "Echo.Say", &Message{Say: "hello"}, client.WithContentType("application/json"))
+		return c.Call(ctx, req, &Message{})
+	}
+
+	if err := call(metadata.Metadata{"foo": "bar"}); err != nil {
+		t.Fatalf("Expected the request within the limits to succeed, got %v", err)
+	}
+
+	err := call(metadata.Metadata{"foo": strings.Repeat("x", 2048)})
+	if err == nil || !strings.Contains(err.Error(), "exceed the limit of 1024 bytes") {
+		t.Fatalf("Expected the oversized headers to be rejected, got %v", err)
+	}
+
+	many := metadata.Metadata{}
+	for i := 0; i < 30; i++ {
+		many[strings.Repeat("k", i+1)] = "v"
+	}
+	err = call(many)
+	if err == nil || !strings.Contains(err.Error(), "exceeding the limit of 20") {
+		t.Fatalf("Expected too many headers to be rejected, got %v", err)
+	}
+}
+
+func TestDebugStreams(t *testing.T) {
+	reg := memory.NewRegistry()
+
+	c := cgrpc.NewClient(
+		client.Registry(reg),
+		client.Selector(selector.NewSelector(selector.Registry(reg))),
+	)
+
+	srv := NewServer(
+		server.Name("go.vine.streamer"),
+		server.Address("127.0.0.1:0"),
+		server.Registry(reg),
+		server.Broker(bmemory.NewBroker()),
+		DebugHandler("/debug/streams", cgrpc.StreamsHandler(c)),
+	)
+	if err := srv.Handle(srv.NewHandler(&Streamer{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req := c.NewRequest("go.vine.streamer", "Streamer.Stream", &Message{}, client.WithContentType("application/json"), client.StreamingRequest())
+	stream, err := c.Stream(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	time.Sleep(10 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	srv.(*grpcServer).debugMux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/streams", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var streams []struct {
+		Id      string `json:"id"`
+		Service string `json:"service"`
+		Age     string `json:"age"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &streams); err != nil {
+		t.Fatal(err)
+	}
+	if len(streams) != 1 {
+		t.Fatalf("Expected 1 open stream, got %s", w.Body.String())
+	}
+	if streams[0].Id != cgrpc.Streams(c)[0].Id || streams[0].Service != "go.vine.streamer" {
+		t.Fatalf("Unexpected stream %+v", streams[0])
+	}
+	if age, err := time.ParseDuration(streams[0].Age); err != nil || age < 10*time.Millisecond {
+		t.Fatalf("Expected the age of the stream, got %q", streams[0].Age)
+	}
+
+	// the endpoint is only served when set
+	w = httptest.NewRecorder()
+	NewServer().(*grpcServer).debugMux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/streams", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404 without the handler, got %d", w.Code)
+	}
+}
@@ -26,6 +26,8 @@ import (
 	"context"
 	"crypto/tls"
 	"net"
+	"net/http"
+	"os"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/encoding"
@@ -39,6 +41,15 @@ type netListener struct{}
 type maxMsgSizeKey struct{}
 type maxConnKey struct{}
 type tlsAuth struct{}
+type unixSocketKey struct{}
+type unixSocketModeKey struct{}
+type maxHeaderKey struct{}
+type debugHandlersKey struct{}
+
+type maxHeader struct {
+	size  int
+	count int
+}
 
 type Grpc2Http struct {
 	CertFile string
@@ -71,6 +82,23 @@ func GrpcToHttp(t *Grpc2Http) server.Option {
 	return setServerOption(Grpc2Http{}, t)
 }
 
+// DebugHandler serves the handler at the pattern of the http handler served
+// with GrpcToHttp, next to /metrics and /debug/pprof, e.g. the open streams of
+// the grpc client at /debug/streams
+func DebugHandler(pattern string, h http.Handler) server.Option {
+	return func(o *server.Options) {
+		handlers := make(map[string]http.Handler)
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		if v, ok := o.Context.Value(debugHandlersKey{}).(map[string]http.Handler); ok && v != nil {
+			handlers = v
+		}
+		handlers[pattern] = h
+		o.Context = context.WithValue(o.Context, debugHandlersKey{}, handlers)
+	}
+}
+
 // MaxConn specifies maximum number of max simultaneous connections to server
 func MaxConn(n int) server.Option {
 	return setServerOption(maxConnKey{}, n)
@@ -81,6 +109,18 @@ func Listener(l net.Listener) server.Option {
 	return setServerOption(netListener{}, l)
 }
 
+// UnixSocket additionally serves on the unix domain socket at path. The path
+// is advertised in the node metadata so callers on the same host prefer it
+// over the tcp address
+func UnixSocket(path string) server.Option {
+	return setServerOption(unixSocketKey{}, path)
+}
+
+// UnixSocketMode sets the file mode of the unix domain socket, defaults to 0660
+func UnixSocketMode(mode os.FileMode) server.Option {
+	return setServerOption(unixSocketModeKey{}, mode)
+}
+
 // Options to be used to configure gRPC options
 func Options(opts ...grpc.ServerOption) server.Option {
 	return setServerOption(grpcOptions{}, opts)
@@ -91,3 +131,11 @@ func Options(opts ...grpc.ServerOption) server.Option {
 func MaxMsgSize(s int) server.Option {
 	return setServerOption(maxMsgSizeKey{}, s)
 }
+
+// MaxHeaderSize sets the maximum total size in bytes of the keys and values
+// of the metadata of a request, and the maximum number of keys. The requests
+// exceeding either are rejected, zero is no limit. The http2 transport keeps
+// its own limit, see grpc.MaxHeaderListSize.
+func MaxHeaderSize(size, count int) server.Option {
+	return setServerOption(maxHeaderKey{}, maxHeader{size: size, count: count})
+}
@@ -25,6 +25,7 @@ package handler
 import (
 	"github.com/lack-io/vine/core/client"
 	"github.com/lack-io/vine/core/client/grpc"
+	"github.com/lack-io/vine/lib/api/marshal"
 	"github.com/lack-io/vine/lib/api/router"
 )
 
@@ -37,6 +38,8 @@ type Options struct {
 	Namespace   string
 	Router      router.Router
 	Client      client.Client
+	// Marshal are the json options of the proto responses
+	Marshal marshal.Options
 }
 
 type Option func(o *Options)
@@ -90,3 +93,10 @@ func WithMaxRecvSize(size int64) Option {
 		o.MaxRecvSize = size
 	}
 }
+
+// WithMarshalOptions specifies how the proto responses are rendered as json
+func WithMarshalOptions(mo marshal.Options) Option {
+	return func(o *Options) {
+		o.Marshal = mo
+	}
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/lack-io/vine/core/codec/jsonrpc"
 	"github.com/lack-io/vine/core/codec/protorpc"
 	"github.com/lack-io/vine/lib/api/handler"
+	"github.com/lack-io/vine/lib/api/marshal"
 	"github.com/lack-io/vine/lib/logger"
 	apipb "github.com/lack-io/vine/proto/apis/api"
 	"github.com/lack-io/vine/proto/apis/errors"
@@ -224,6 +225,11 @@ func (h *rpcHandler) Handle(c *fiber.Ctx) error {
 			request = br
 		}
 
+		// ask the service for the json options of the endpoint
+		if mo := marshal.ForService(h.opts.Marshal, service).String(); len(mo) > 0 {
+			cx = metadata.Set(cx, marshal.MetadataKey, mo)
+		}
+
 		// create request/response
 		var response RawMessage
 
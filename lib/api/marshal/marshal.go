@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package marshal provides the JSON marshaling options shared by the api handlers
+package marshal
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	json "github.com/json-iterator/go"
+
+	apipb "github.com/lack-io/vine/proto/apis/api"
+	"github.com/lack-io/vine/util/jsonpb"
+)
+
+const (
+	// MetadataKey is the request metadata carrying the options from the api to the service
+	MetadataKey = "Vine-Json-Options"
+	// EndpointKey is the endpoint metadata used to override the options per service
+	EndpointKey = "json"
+)
+
+// Options configures how proto messages are rendered as JSON. The zero value
+// renders them the way the services always have: zero values omitted, enums as
+// strings, 64-bit integers as strings and fields by their original proto name.
+type Options struct {
+	// EmitUnpopulated renders the fields with zero values
+	EmitUnpopulated bool
+	// EnumsAsInts renders the enums as numbers
+	EnumsAsInts bool
+	// Int64AsNumber renders the 64-bit integers as numbers
+	Int64AsNumber bool
+	// CamelCase renders the fields by their lowerCamelCase json name
+	CamelCase bool
+}
+
+var flags = []string{"emit_unpopulated", "enums_as_ints", "int64_as_number", "camel_case"}
+
+func (o *Options) flag(name string) *bool {
+	switch name {
+	case "emit_unpopulated":
+		return &o.EmitUnpopulated
+	case "enums_as_ints":
+		return &o.EnumsAsInts
+	case "int64_as_number":
+		return &o.Int64AsNumber
+	case "camel_case":
+		return &o.CamelCase
+	}
+	return nil
+}
+
+// Parse parses a comma separated list of options e.g emit_unpopulated,enums_as_ints
+func Parse(s string) (Options, error) {
+	var o Options
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 {
+			continue
+		}
+		v := o.flag(name)
+		if v == nil {
+			return Options{}, fmt.Errorf("unknown json option '%s', expected one of %s", name, strings.Join(flags, ", "))
+		}
+		*v = true
+	}
+	return o, nil
+}
+
+// String returns the options in the format accepted by Parse, empty for the defaults
+func (o Options) String() string {
+	var set []string
+	for _, name := range flags {
+		if *o.flag(name) {
+			set = append(set, name)
+		}
+	}
+	return strings.Join(set, ",")
+}
+
+// Marshaler returns the jsonpb marshaler configured by the options
+func (o Options) Marshaler() *jsonpb.Marshaler {
+	return &jsonpb.Marshaler{
+		EnumsAsInts:   o.EnumsAsInts,
+		EmitDefaults:  o.EmitUnpopulated,
+		OrigName:      !o.CamelCase,
+		Int64AsNumber: o.Int64AsNumber,
+	}
+}
+
+// Marshal renders v as JSON, proto messages are rendered according to the options
+func (o Options) Marshal(v interface{}) ([]byte, error) {
+	if pb, ok := v.(proto.Message); ok {
+		buf := bytes.NewBuffer(nil)
+		if err := o.Marshaler().Marshal(buf, pb); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return json.Marshal(v)
+}
+
+// ForService returns the options overridden by the endpoint metadata of the
+// service, if any. Invalid overrides are ignored.
+func ForService(o Options, s *apipb.Service) Options {
+	if s == nil || s.Endpoint == nil {
+		return o
+	}
+	for _, svc := range s.Services {
+		for _, ep := range svc.Endpoints {
+			if ep.Name != s.Endpoint.Name {
+				continue
+			}
+			v, ok := ep.Metadata[EndpointKey]
+			if !ok {
+				continue
+			}
+			if eo, err := Parse(v); err == nil {
+				return eo
+			}
+		}
+	}
+	return o
+}
@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package marshal
+
+import (
+	"testing"
+
+	apipb "github.com/lack-io/vine/proto/apis/api"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+func TestParse(t *testing.T) {
+	o, err := Parse("emit_unpopulated, int64_as_number")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o.EmitUnpopulated || !o.Int64AsNumber || o.EnumsAsInts || o.CamelCase {
+		t.Fatalf("unexpected options %+v", o)
+	}
+	if s := o.String(); s != "emit_unpopulated,int64_as_number" {
+		t.Fatalf("expected emit_unpopulated,int64_as_number got %s", s)
+	}
+	if s := (Options{}).String(); s != "" {
+		t.Fatalf("expected empty default options got %s", s)
+	}
+	if _, err := Parse("emit_defaults"); err == nil {
+		t.Fatal("expected error for unknown option")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	ev := &regpb.Event{Id: "1", Type: regpb.EventType_Delete, Timestamp: 10}
+
+	testData := []struct {
+		opts   Options
+		expect string
+	}{
+		{Options{}, `{"id":"1","type":"Delete","timestamp":"10"}`},
+		{Options{EnumsAsInts: true, Int64AsNumber: true}, `{"id":"1","type":1,"timestamp":10}`},
+		{Options{EmitUnpopulated: true}, `{"id":"1","type":"Delete","timestamp":"10","service":null}`},
+	}
+
+	for _, d := range testData {
+		b, err := d.opts.Marshal(ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != d.expect {
+			t.Fatalf("options %+v: expected %s got %s", d.opts, d.expect, b)
+		}
+	}
+}
+
+func TestForService(t *testing.T) {
+	s := &apipb.Service{
+		Name:     "foo",
+		Endpoint: &apipb.Endpoint{Name: "Foo.Bar"},
+		Services: []*regpb.Service{{
+			Name: "foo",
+			Endpoints: []*regpb.Endpoint{
+				{Name: "Foo.Baz", Metadata: map[string]string{EndpointKey: "camel_case"}},
+				{Name: "Foo.Bar", Metadata: map[string]string{EndpointKey: "enums_as_ints"}},
+			},
+		}},
+	}
+
+	o := ForService(Options{EmitUnpopulated: true}, s)
+	if o != (Options{EnumsAsInts: true}) {
+		t.Fatalf("expected endpoint override got %+v", o)
+	}
+
+	s.Services[0].Endpoints[1].Metadata[EndpointKey] = "bogus"
+	o = ForService(Options{EmitUnpopulated: true}, s)
+	if o != (Options{EmitUnpopulated: true}) {
+		t.Fatalf("expected handler options got %+v", o)
+	}
+}
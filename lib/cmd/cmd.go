@@ -41,6 +41,7 @@ import (
 	"github.com/lack-io/vine/core/client/selector/static"
 	"github.com/lack-io/vine/core/registry"
 	"github.com/lack-io/vine/core/registry/etcd"
+	regFile "github.com/lack-io/vine/core/registry/file"
 	"github.com/lack-io/vine/core/registry/grpc"
 	"github.com/lack-io/vine/core/registry/mdns"
 	regMemory "github.com/lack-io/vine/core/registry/memory"
@@ -50,13 +51,18 @@ import (
 	configSrc "github.com/lack-io/vine/lib/config/source"
 	"github.com/lack-io/vine/lib/dao"
 	log "github.com/lack-io/vine/lib/logger"
+	"github.com/lack-io/vine/lib/store"
+	storeMemory "github.com/lack-io/vine/lib/store/memory"
+	storeRedis "github.com/lack-io/vine/lib/store/redis"
 	"github.com/lack-io/vine/lib/trace"
+	jTracer "github.com/lack-io/vine/lib/trace/jaeger"
 	memTracer "github.com/lack-io/vine/lib/trace/memory"
 
 	// servers
 	sgrpc "github.com/lack-io/vine/core/server/grpc"
 
 	daoNop "github.com/lack-io/vine/lib/dao/nop"
+	daoPostgres "github.com/lack-io/vine/lib/dao/postgres"
 
 	// config
 	configSrv "github.com/lack-io/vine/lib/config/source/service"
@@ -74,6 +80,8 @@ type Cmd interface {
 	Init(opts ...Option) error
 	// Options set within this command
 	Options() Options
+	// Shutdown stops the components in order
+	Shutdown() error
 }
 
 type cmd struct {
@@ -110,6 +118,21 @@ var (
 			EnvVars: []string{"VINE_CLIENT_POOL_TTL"},
 			Usage:   "Sets the client connection pool ttl. e.g 500ms, 5s, 1m. Default: 1m",
 		},
+		&cli.IntFlag{
+			Name:    "client-pool-max-idle",
+			EnvVars: []string{"VINE_CLIENT_POOL_MAX_IDLE"},
+			Usage:   "Sets the maximum idle connections kept per address by the client connection pool. Default: 50",
+		},
+		&cli.StringFlag{
+			Name:    "client-pool-acquire-timeout",
+			EnvVars: []string{"VINE_CLIENT_POOL_ACQUIRE_TIMEOUT"},
+			Usage:   "Bounds the client connection pool to its size, calls fail when no connection is available before the timeout. e.g 500ms, 5s",
+		},
+		&cli.StringFlag{
+			Name:    "client-zone",
+			EnvVars: []string{"VINE_ZONE"},
+			Usage:   "Sets the zone of the client, calls prefer nodes with the same zone metadata. e.g us-east-1a",
+		},
 		&cli.IntFlag{
 			Name:    "register-ttl",
 			EnvVars: []string{"VINE_REGISTER_TTL"},
@@ -171,18 +194,23 @@ var (
 		&cli.StringFlag{
 			Name:    "registry",
 			EnvVars: []string{"VINE_REGISTRY"},
-			Usage:   "Registry for discovery. etcd, mdns",
+			Usage:   "Registry for discovery. etcd, mdns, file",
 		},
 		&cli.StringFlag{
 			Name:    "registry-address",
 			EnvVars: []string{"VINE_REGISTRY_ADDRESS"},
-			Usage:   "Comma-separated list of registry addresses",
+			Usage:   "Comma-separated list of registry addresses, the path of the file registry",
 		},
 		&cli.StringFlag{
 			Name:    "selector",
 			EnvVars: []string{"VINE_SELECTOR"},
 			Usage:   "Selector used to pick nodes for querying",
 		},
+		&cli.StringFlag{
+			Name:    "selector-strategy",
+			EnvVars: []string{"VINE_SELECTOR_STRATEGY"},
+			Usage:   "Strategy of the selector to pick nodes, e.g random, roundrobin or weighted",
+		},
 		&cli.StringFlag{
 			Name:    "dao-dialect",
 			EnvVars: []string{"VINE_DAO_DIALECT"},
@@ -208,6 +236,26 @@ var (
 			EnvVars: []string{"VINE_TRACER_ADDRESS"},
 			Usage:   "Comma-separated list of tracer addresses",
 		},
+		&cli.StringFlag{
+			Name:    "store",
+			EnvVars: []string{"VINE_STORE"},
+			Usage:   "Store used for key-value storage, e.g. memory, redis",
+		},
+		&cli.StringFlag{
+			Name:    "store-address",
+			EnvVars: []string{"VINE_STORE_ADDRESS"},
+			Usage:   "Comma-separated list of store addresses",
+		},
+		&cli.StringFlag{
+			Name:    "store-database",
+			EnvVars: []string{"VINE_STORE_DATABASE"},
+			Usage:   "Database option for the underlying store",
+		},
+		&cli.StringFlag{
+			Name:    "store-table",
+			EnvVars: []string{"VINE_STORE_TABLE"},
+			Usage:   "Table option for the underlying store",
+		},
 	}
 
 	DefaultBrokers = map[string]func(...broker.Option) broker.Broker{
@@ -222,6 +270,7 @@ var (
 
 	DefaultRegistries = map[string]func(...registry.Option) registry.Registry{
 		"etcd":    etcd.NewRegistry,
+		"file":    regFile.NewRegistry,
 		"service": grpc.NewRegistry,
 		"mdns":    mdns.NewRegistry,
 		"memory":  regMemory.NewRegistry,
@@ -232,17 +281,29 @@ var (
 		"static": static.NewSelector,
 	}
 
+	DefaultStrategies = map[string]selector.Strategy{
+		"random":     selector.Random,
+		"roundrobin": selector.RoundRobin,
+		"weighted":   selector.WeightedRoundRobin,
+	}
+
 	DefaultServers = map[string]func(...server.Option) server.Server{
 		"grpc": sgrpc.NewServer,
 	}
 
 	DefaultDialects = map[string]func(...dao.Option) dao.Dialect{
-		"nop": daoNop.NewDialect,
+		"nop":      daoNop.NewDialect,
+		"postgres": daoPostgres.NewDialect,
 	}
 
 	DefaultTracers = map[string]func(...trace.Option) trace.Tracer{
 		"memory": memTracer.NewTracer,
-		// "jaeger": jTracer.NewTracer,
+		"jaeger": jTracer.NewTracer,
+	}
+
+	DefaultStores = map[string]func(...store.Option) store.Store{
+		"memory": storeMemory.NewStore,
+		"redis":  storeRedis.NewStore,
 	}
 
 	DefaultConfigs = map[string]func(...config.Option) config.Config{
@@ -259,6 +320,7 @@ func newCmd(opts ...Option) Cmd {
 		Selector:  &selector.DefaultSelector,
 		Dialect:   &dao.DefaultDialect,
 		Tracer:    &trace.DefaultTracer,
+		Store:     &store.DefaultStore,
 		Config:    &config.DefaultConfig,
 
 		Brokers:    DefaultBrokers,
@@ -268,6 +330,7 @@ func newCmd(opts ...Option) Cmd {
 		Servers:    DefaultServers,
 		Dialects:   DefaultDialects,
 		Tracers:    DefaultTracers,
+		Stores:     DefaultStores,
 		Configs:    DefaultConfigs,
 	}
 
@@ -322,6 +385,10 @@ func (c *cmd) Options() Options {
 	return c.opts
 }
 
+func (c *cmd) Shutdown() error {
+	return Teardown(ShutdownSteps(c.opts)...)
+}
+
 func (c *cmd) Before(ctx *cli.Context) error {
 	// If flags are set then use them otherwise do nothing
 	var serverOpts []server.Option
@@ -348,7 +415,37 @@ func (c *cmd) Before(ctx *cli.Context) error {
 			return fmt.Errorf("unsupported tracer: %s", name)
 		}
 
-		*c.opts.Tracer = r()
+		var topts []trace.Option
+		if len(ctx.String("tracer-address")) > 0 {
+			topts = append(topts, trace.Addrs(strings.Split(ctx.String("tracer-address"), ",")...))
+		}
+
+		*c.opts.Tracer = r(topts...)
+	}
+
+	// Set the store
+	var storeOpts []store.Option
+	if addrs := ctx.String("store-address"); len(addrs) > 0 {
+		storeOpts = append(storeOpts, store.Nodes(strings.Split(addrs, ",")...))
+	}
+	if db := ctx.String("store-database"); len(db) > 0 {
+		storeOpts = append(storeOpts, store.Database(db))
+	}
+	if table := ctx.String("store-table"); len(table) > 0 {
+		storeOpts = append(storeOpts, store.Table(table))
+	}
+
+	if name := ctx.String("store"); len(name) > 0 {
+		s, ok := c.opts.Stores[name]
+		if !ok {
+			return fmt.Errorf("unsupported store: %s", name)
+		}
+
+		*c.opts.Store = s(storeOpts...)
+	} else if len(storeOpts) > 0 && *c.opts.Store != nil {
+		if err := (*c.opts.Store).Init(storeOpts...); err != nil {
+			log.Fatalf("Error configuring store: %v", err)
+		}
 	}
 
 	// Set the client
@@ -414,6 +511,19 @@ func (c *cmd) Before(ctx *cli.Context) error {
 		clientOpts = append(clientOpts, client.Selector(*c.opts.Selector))
 	}
 
+	// Set the selector strategy
+	if name := ctx.String("selector-strategy"); len(name) > 0 {
+		s, ok := DefaultStrategies[name]
+		if !ok {
+			return fmt.Errorf("selector strategy %s not found", name)
+		}
+
+		if err := (*c.opts.Selector).Init(selector.SetStrategy(s)); err != nil {
+			log.Fatalf("Error configuring selector: %v", err)
+		}
+		clientOpts = append(clientOpts, client.Selector(*c.opts.Selector))
+	}
+
 	// Parse the server options
 	metadata := make(map[string]string)
 	for _, d := range ctx.StringSlice("server-metadata") {
@@ -507,6 +617,14 @@ func (c *cmd) Before(ctx *cli.Context) error {
 		clientOpts = append(clientOpts, client.PoolSize(r))
 	}
 
+	if r := ctx.Int("client-pool-max-idle"); r > 0 {
+		clientOpts = append(clientOpts, cGrpc.PoolMaxIdle(r))
+	}
+
+	if zone := ctx.String("client-zone"); len(zone) > 0 {
+		clientOpts = append(clientOpts, client.Zone(zone))
+	}
+
 	if t := ctx.String("client-pool-ttl"); len(t) > 0 {
 		d, err := time.ParseDuration(t)
 		if err != nil {
@@ -515,6 +633,14 @@ func (c *cmd) Before(ctx *cli.Context) error {
 		clientOpts = append(clientOpts, client.PoolTTL(d))
 	}
 
+	if t := ctx.String("client-pool-acquire-timeout"); len(t) > 0 {
+		d, err := time.ParseDuration(t)
+		if err != nil {
+			return fmt.Errorf("failed to parse client-pool-acquire-timeout: %v", t)
+		}
+		clientOpts = append(clientOpts, client.PoolAcquireTimeout(d))
+	}
+
 	// We have some command line opts for the server.
 	// Lets set it up
 	if len(serverOpts) > 0 {
@@ -545,6 +671,10 @@ func Init(opts ...Option) error {
 	return DefaultCmd.Init(opts...)
 }
 
+func Shutdown() error {
+	return DefaultCmd.Shutdown()
+}
+
 func NewCmd(opts ...Option) Cmd {
 	return newCmd(opts...)
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/lack-io/vine/core/server"
 	"github.com/lack-io/vine/lib/config"
 	"github.com/lack-io/vine/lib/dao"
+	"github.com/lack-io/vine/lib/store"
 	"github.com/lack-io/vine/lib/trace"
 )
 
@@ -54,6 +55,7 @@ type Options struct {
 	Server    *server.Server
 	Dialect   *dao.Dialect
 	Tracer    *trace.Tracer
+	Store     *store.Store
 
 	Brokers    map[string]func(...broker.Option) broker.Broker
 	Configs    map[string]func(...config.Option) config.Config
@@ -63,6 +65,7 @@ type Options struct {
 	Servers    map[string]func(...server.Option) server.Server
 	Dialects   map[string]func(...dao.Option) dao.Dialect
 	Tracers    map[string]func(...trace.Option) trace.Tracer
+	Stores     map[string]func(...store.Option) store.Store
 
 	// Other options for implementations of the interfaces
 	// can be stored in a context
@@ -146,6 +149,12 @@ func Tracer(t *trace.Tracer) Option {
 	}
 }
 
+func Store(s *store.Store) Option {
+	return func(o *Options) {
+		o.Store = s
+	}
+}
+
 // NewBroker new broker func
 func NewBroker(name string, b func(...broker.Option) broker.Broker) Option {
 	return func(o *Options) {
@@ -194,3 +203,10 @@ func NewDialect(name string, t func(...dao.Option) dao.Dialect) Option {
 		o.Dialects[name] = t
 	}
 }
+
+// NewStore new store func
+func NewStore(name string, s func(...store.Option) store.Store) Option {
+	return func(o *Options) {
+		o.Stores[name] = s
+	}
+}
@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	log "github.com/lack-io/vine/lib/logger"
+)
+
+// DefaultShutdownTimeout bounds each step of the shutdown without timeout
+var DefaultShutdownTimeout = 10 * time.Second
+
+// Step is a step of the ordered shutdown of the components
+type Step struct {
+	Name    string
+	Timeout time.Duration
+	Stop    func() error
+}
+
+// Teardown runs the steps one after the other. A step running longer than
+// its timeout is abandoned so the next ones still run, the first error is
+// returned once all the steps are done.
+func Teardown(steps ...Step) error {
+	var gerr error
+
+	for _, step := range steps {
+		timeout := step.Timeout
+		if timeout <= 0 {
+			timeout = DefaultShutdownTimeout
+		}
+
+		log.Infof("Shutdown [%s] stopping", step.Name)
+		start := time.Now()
+
+		errCh := make(chan error, 1)
+		go func(fn func() error) {
+			errCh <- fn()
+		}(step.Stop)
+
+		var err error
+		select {
+		case err = <-errCh:
+		case <-time.After(timeout):
+			err = fmt.Errorf("timed out after %v", timeout)
+		}
+
+		if err != nil {
+			log.Errorf("Shutdown [%s] error: %v", step.Name, err)
+			if gerr == nil {
+				gerr = fmt.Errorf("shutdown %s: %v", step.Name, err)
+			}
+			continue
+		}
+
+		log.Infof("Shutdown [%s] stopped in %v", step.Name, time.Since(start))
+	}
+
+	return gerr
+}
+
+// ShutdownSteps returns the steps to stop the components of the options:
+// the server stops accepting requests and deregisters, then the broker is
+// disconnected and the tracer flushed
+func ShutdownSteps(o Options) []Step {
+	var steps []Step
+
+	if o.Server != nil && *o.Server != nil {
+		steps = append(steps, Step{Name: "server", Stop: (*o.Server).Stop})
+	}
+	if o.Broker != nil && *o.Broker != nil {
+		steps = append(steps, Step{Name: "broker", Stop: (*o.Broker).Disconnect})
+	}
+	if o.Store != nil && *o.Store != nil {
+		steps = append(steps, Step{Name: "store", Stop: (*o.Store).Close})
+	}
+	if o.Tracer != nil {
+		if c, ok := (*o.Tracer).(io.Closer); ok {
+			steps = append(steps, Step{Name: "tracer", Stop: c.Close})
+		}
+	}
+
+	return steps
+}
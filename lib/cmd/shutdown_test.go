@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/core/broker"
+	bmemory "github.com/lack-io/vine/core/broker/memory"
+	sgrpc "github.com/lack-io/vine/core/server/grpc"
+	"github.com/lack-io/vine/lib/trace"
+	"github.com/lack-io/vine/lib/trace/memory"
+)
+
+func TestTeardown(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+
+	step := func(name string, d time.Duration, err error) Step {
+		return Step{
+			Name:    name,
+			Timeout: time.Millisecond * 100,
+			Stop: func() error {
+				time.Sleep(d)
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+				return err
+			},
+		}
+	}
+
+	start := time.Now()
+	err := Teardown(
+		step("server", 0, nil),
+		step("broker", time.Second, nil),
+		step("store", 0, errors.New("closed")),
+		step("tracer", 0, nil),
+	)
+
+	if elapsed := time.Since(start); elapsed > time.Millisecond*500 {
+		t.Fatalf("Expected the slow step to be bounded by its timeout, took %v", elapsed)
+	}
+
+	// the first error is the timed out broker
+	if err == nil || !strings.Contains(err.Error(), "broker") {
+		t.Fatalf("Expected the broker timeout error, got %v", err)
+	}
+
+	mu.Lock()
+	got := append([]string{}, order...)
+	mu.Unlock()
+
+	if want := []string{"server", "store", "tracer"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected teardown order %v, got %v", want, got)
+	}
+}
+
+type closeTracer struct {
+	trace.Tracer
+	closed bool
+}
+
+func (t *closeTracer) Close() error {
+	t.closed = true
+	return nil
+}
+
+func TestShutdownSteps(t *testing.T) {
+	s := sgrpc.NewServer()
+	b := broker.Broker(bmemory.NewBroker())
+	tr := trace.Tracer(&closeTracer{Tracer: memory.NewTracer()})
+
+	steps := ShutdownSteps(Options{Server: &s, Broker: &b, Tracer: &tr})
+
+	var names []string
+	for _, step := range steps {
+		names = append(names, step.Name)
+	}
+
+	if want := []string{"server", "broker", "tracer"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected shutdown steps %v, got %v", want, names)
+	}
+
+	if err := Teardown(steps...); err != nil {
+		t.Fatal(err)
+	}
+	if !tr.(*closeTracer).closed {
+		t.Fatal("Expected the tracer to be closed")
+	}
+}
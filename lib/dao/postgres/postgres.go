@@ -0,0 +1,260 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lack-io/vine/lib/dao"
+	"github.com/lack-io/vine/lib/dao/callbacks"
+	"github.com/lack-io/vine/lib/dao/clause"
+	"github.com/lack-io/vine/lib/dao/logger"
+	"github.com/lack-io/vine/lib/dao/migrator"
+	"github.com/lack-io/vine/lib/dao/schema"
+	_ "github.com/lib/pq"
+)
+
+// DriverName is the database/sql driver used to open the DSN, github.com/lib/pq
+// registers itself under the default one
+var DriverName = "postgres"
+
+type Dialect struct {
+	DB   *dao.DB
+	Opts dao.Options
+}
+
+func (d *Dialect) Init(opts ...dao.Option) (err error) {
+	for _, opt := range opts {
+		opt(&d.Opts)
+	}
+
+	if d.Opts.ConnPool == nil {
+		d.Opts.ConnPool, err = sql.Open(DriverName, d.Opts.DSN)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.DB, err = dao.Open(d)
+	if err != nil {
+		return err
+	}
+
+	callbacks.RegisterDefaultCallbacks(d.DB, &callbacks.Options{
+		WithReturning: true,
+	})
+	return nil
+}
+
+func (d *Dialect) Options() dao.Options {
+	return d.Opts
+}
+
+func (d *Dialect) NewTx() *dao.DB {
+	return d.DB.Session(&dao.Session{NewDB: true})
+}
+
+func (d *Dialect) Migrator() dao.Migrator {
+	return Migrator{migrator.Migrator{Options: migrator.Options{
+		DB:                          d.DB,
+		Dialect:                     d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+func (d *Dialect) DefaultValueOf(field *schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+func (d *Dialect) BindVarTo(writer clause.Writer, stmt *dao.Statement, v interface{}) {
+	writer.WriteByte('$')
+	writer.WriteString(strconv.Itoa(len(stmt.Vars)))
+}
+
+func (d *Dialect) QuoteTo(writer clause.Writer, str string) {
+	for idx, s := range strings.Split(str, ".") {
+		if idx > 0 {
+			writer.WriteByte('.')
+		}
+		writer.WriteByte('"')
+		writer.WriteString(s)
+		writer.WriteByte('"')
+	}
+}
+
+var numericPlaceholder = regexp.MustCompile(`\$(\d+)`)
+
+func (d *Dialect) Explain(sql string, vars ...interface{}) string {
+	return logger.ExplainSQL(sql, numericPlaceholder, `'`, vars...)
+}
+
+func (d *Dialect) DataTypeOf(field *schema.Field) string {
+	switch field.DataType {
+	case schema.Bool:
+		return "boolean"
+	case schema.Int, schema.Uint:
+		size := field.Size
+		if field.DataType == schema.Uint {
+			size++
+		}
+		if field.AutoIncrement {
+			switch {
+			case size <= 16:
+				return "smallserial"
+			case size <= 32:
+				return "serial"
+			default:
+				return "bigserial"
+			}
+		}
+		switch {
+		case size <= 16:
+			return "smallint"
+		case size <= 32:
+			return "integer"
+		default:
+			return "bigint"
+		}
+	case schema.Float:
+		if field.Precision > 0 {
+			if field.Scale > 0 {
+				return fmt.Sprintf("numeric(%d, %d)", field.Precision, field.Scale)
+			}
+			return fmt.Sprintf("numeric(%d)", field.Precision)
+		}
+		return "decimal"
+	case schema.String:
+		if field.Size > 0 {
+			return fmt.Sprintf("varchar(%d)", field.Size)
+		}
+		return "text"
+	case schema.Time:
+		if field.Precision > 0 {
+			return fmt.Sprintf("timestamptz(%d)", field.Precision)
+		}
+		return "timestamptz"
+	case schema.Bytes:
+		return "bytea"
+	}
+
+	return string(field.DataType)
+}
+
+func (d *Dialect) SavePoint(tx *dao.DB, name string) error {
+	return tx.Exec("SAVEPOINT " + name).Error
+}
+
+func (d *Dialect) RollbackTo(tx *dao.DB, name string) error {
+	return tx.Exec("ROLLBACK TO SAVEPOINT " + name).Error
+}
+
+func (d *Dialect) JSONDataType() string {
+	return "JSONB"
+}
+
+func (d *Dialect) JSONBuild(column string) dao.JSONQuery {
+	return &jsonQueryExpression{column: column}
+}
+
+func (d *Dialect) String() string {
+	return "postgres"
+}
+
+type jsonQueryExpression struct {
+	tx       *dao.DB
+	column   string
+	op       dao.JSONOp
+	contains bool
+	keys     []string
+	value    interface{}
+}
+
+func (j *jsonQueryExpression) Tx(tx *dao.DB) dao.JSONQuery {
+	j.tx = tx
+	return j
+}
+
+func (j *jsonQueryExpression) Op(op dao.JSONOp, value interface{}, keys ...string) dao.JSONQuery {
+	j.op = op
+	j.value = value
+	j.keys = keys
+	return j
+}
+
+func (j *jsonQueryExpression) Contains(op dao.JSONOp, values interface{}, keys ...string) dao.JSONQuery {
+	j.op = op
+	j.contains = true
+	j.value = values
+	j.keys = keys
+	return j
+}
+
+// path formats the keys as a postgres text array, e.g. {a,b}
+func (j *jsonQueryExpression) path() string {
+	return "{" + strings.Join(j.keys, ",") + "}"
+}
+
+func (j *jsonQueryExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*dao.Statement)
+	if !ok {
+		return
+	}
+
+	column := stmt.Quote(j.column)
+	switch {
+	case j.contains:
+		// the array under the path holds an element matching the value
+		b, _ := json.Marshal([]interface{}{j.value})
+		builder.WriteString(column + " #> ")
+		stmt.AddVar(builder, j.path())
+		builder.WriteString(" @> ")
+		stmt.AddVar(builder, string(b))
+	case j.op == dao.JSONHasKey:
+		builder.WriteString(column + " #> ")
+		stmt.AddVar(builder, j.path())
+		builder.WriteString(j.op.String())
+	default:
+		switch j.value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			builder.WriteString("(" + column + " #>> ")
+			stmt.AddVar(builder, j.path())
+			builder.WriteString(")::numeric ")
+		default:
+			builder.WriteString(column + " #>> ")
+			stmt.AddVar(builder, j.path())
+			builder.WriteString(" ")
+		}
+		builder.WriteString(j.op.String() + " ")
+		stmt.AddVar(builder, j.value)
+	}
+}
+
+// NewDialect returns a postgres dialect, Init opens the DSN
+func NewDialect(opts ...dao.Option) dao.Dialect {
+	return &Dialect{Opts: dao.NewOptions(opts...)}
+}
@@ -0,0 +1,197 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/lack-io/vine/lib/dao"
+	"github.com/lack-io/vine/lib/dao/schema"
+)
+
+type User struct {
+	ID    uint   `dao:"primaryKey"`
+	Name  string `dao:"size:64;index"`
+	Email string
+	Age   int
+}
+
+var errOffline = errors.New("offline")
+
+// offlinePool fails every statement, the tests run in dry run mode
+type offlinePool struct{}
+
+func (offlinePool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errOffline
+}
+
+func (offlinePool) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errOffline
+}
+
+func (offlinePool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errOffline
+}
+
+func (offlinePool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func TestDryRun(t *testing.T) {
+	d := NewDialect()
+	if err := d.Init(func(o *dao.Options) {
+		o.ConnPool = offlinePool{}
+		o.DryRun = true
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var users []User
+	stmt := d.NewTx().Where("name = ? AND age > ?", "alice", 20).Find(&users).Statement
+	expected := `SELECT * FROM "users" WHERE name = $1 AND age > $2`
+	if sql := stmt.SQL.String(); sql != expected {
+		t.Fatalf("Expected %s, got %s", expected, sql)
+	}
+	if len(stmt.Vars) != 2 {
+		t.Fatalf("Expected 2 vars, got %v", stmt.Vars)
+	}
+
+	explained := d.Explain(stmt.SQL.String(), stmt.Vars...)
+	if expected := `SELECT * FROM "users" WHERE name = 'alice' AND age > 20`; explained != expected {
+		t.Fatalf("Expected %s, got %s", expected, explained)
+	}
+
+	stmt = d.NewTx().Where(d.JSONBuild("meta").Op(dao.JSONEq, "blue", "color", "name")).Find(&users).Statement
+	expected = `SELECT * FROM "users" WHERE "meta" #>> $1 = $2`
+	if sql := stmt.SQL.String(); sql != expected {
+		t.Fatalf("Expected %s, got %s", expected, sql)
+	}
+	if stmt.Vars[0] != "{color,name}" {
+		t.Fatalf("Expected the path {color,name}, got %v", stmt.Vars[0])
+	}
+}
+
+func TestDataTypeOf(t *testing.T) {
+	d := NewDialect()
+
+	testData := []struct {
+		field    *schema.Field
+		expected string
+	}{
+		{&schema.Field{DataType: schema.Bool}, "boolean"},
+		{&schema.Field{DataType: schema.Int, Size: 32}, "integer"},
+		{&schema.Field{DataType: schema.Uint, Size: 32}, "bigint"},
+		{&schema.Field{DataType: schema.Int, Size: 64, AutoIncrement: true}, "bigserial"},
+		{&schema.Field{DataType: schema.Float, Precision: 10, Scale: 2}, "numeric(10, 2)"},
+		{&schema.Field{DataType: schema.String}, "text"},
+		{&schema.Field{DataType: schema.String, Size: 64}, "varchar(64)"},
+		{&schema.Field{DataType: schema.Time}, "timestamptz"},
+		{&schema.Field{DataType: schema.Bytes}, "bytea"},
+	}
+
+	for _, td := range testData {
+		if dt := d.DataTypeOf(td.field); dt != td.expected {
+			t.Fatalf("Expected %s for %+v, got %s", td.expected, td.field, dt)
+		}
+	}
+}
+
+// TestPostgres runs against the database of VINE_POSTGRES_DSN, the test
+// binary must link a driver registered as DriverName
+func TestPostgres(t *testing.T) {
+	dsn := os.Getenv("VINE_POSTGRES_DSN")
+	if len(dsn) == 0 {
+		t.Skip("VINE_POSTGRES_DSN not set")
+	}
+	registered := false
+	for _, name := range sql.Drivers() {
+		registered = registered || name == DriverName
+	}
+	if !registered {
+		t.Skipf("no %s driver registered", DriverName)
+	}
+
+	d := NewDialect()
+	if err := d.Init(dao.DSN(dsn)); err != nil {
+		t.Fatal(err)
+	}
+
+	m := d.Migrator()
+	if err := m.DropTable(&User{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AutoMigrate(&User{}); err != nil {
+		t.Fatal(err)
+	}
+	defer m.DropTable(&User{})
+	if !m.HasTable(&User{}) || !m.HasColumn(&User{}, "Email") || !m.HasIndex(&User{}, "idx_users_name") {
+		t.Fatal("Expected the users table, its columns and its index")
+	}
+	// migrating twice is a noop
+	if err := m.AutoMigrate(&User{}); err != nil {
+		t.Fatal(err)
+	}
+
+	user := &User{Name: "alice", Email: "alice@example.com", Age: 30}
+	if err := d.NewTx().Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.ID == 0 {
+		t.Fatal("Expected the id to be returned")
+	}
+
+	found := &User{}
+	if err := d.NewTx().Where("name = ?", "alice").First(found).Error; err != nil {
+		t.Fatal(err)
+	}
+	if found.ID != user.ID || found.Email != user.Email {
+		t.Fatalf("Unexpected user %+v", found)
+	}
+
+	if err := d.NewTx().Model(found).Update("age", 31).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := d.NewTx().First(found, user.ID).Error; err != nil || found.Age != 31 {
+		t.Fatalf("Expected age 31, got %d: %v", found.Age, err)
+	}
+
+	if err := d.NewTx().Delete(&User{}, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := d.NewTx().First(&User{}, user.ID).Error; !errors.Is(err, dao.ErrRecordNotFound) {
+		t.Fatalf("Expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestDriverRegistered(t *testing.T) {
+	for _, name := range sql.Drivers() {
+		if name == DriverName {
+			return
+		}
+	}
+	t.Fatalf("Expected the %s driver registered, got %v", DriverName, sql.Drivers())
+}
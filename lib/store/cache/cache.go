@@ -90,7 +90,7 @@ func (c *cache) Read(key string, opts ...store.ReadOption) ([]*store.Record, err
 		o(&readOpts)
 	}
 
-	if readOpts.Prefix || readOpts.Suffix {
+	if readOpts.Prefix || readOpts.Suffix || readOpts.Pattern {
 		// List, then try cached gets for each key
 		var lOpts []store.ListOption
 		if readOpts.Prefix {
@@ -99,6 +99,9 @@ func (c *cache) Read(key string, opts ...store.ReadOption) ([]*store.Record, err
 		if readOpts.Suffix {
 			lOpts = append(lOpts, store.ListSuffix(key))
 		}
+		if readOpts.Pattern {
+			lOpts = append(lOpts, store.ListPattern(key))
+		}
 		if readOpts.Limit > 0 {
 			lOpts = append(lOpts, store.ListLimit(readOpts.Limit))
 		}
@@ -171,3 +174,8 @@ func (c *cache) List(opts ...store.ListOption) ([]string, error) {
 	// List only makes sense from the top level
 	return c.stores[len(c.stores)-1].List(opts...)
 }
+
+func (c *cache) Watch(key string, opts ...store.WatchOption) (store.Watcher, error) {
+	// every write and delete reaches the top level
+	return c.stores[len(c.stores)-1].Watch(key, opts...)
+}
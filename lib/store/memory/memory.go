@@ -28,6 +28,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -42,7 +43,8 @@ func NewStore(opts ...store.Option) store.Store {
 			Database: "vine",
 			Table:    "vine",
 		},
-		store: cache.New(cache.NoExpiration, 5*time.Minute),
+		store:    cache.New(cache.NoExpiration, 5*time.Minute),
+		watchers: make(map[string]*memoryWatcher),
 	}
 	for _, o := range opts {
 		o(&s.options)
@@ -54,6 +56,9 @@ type memoryStore struct {
 	options store.Options
 
 	store *cache.Cache
+
+	sync.RWMutex
+	watchers map[string]*memoryWatcher
 }
 
 type storeRecord struct {
@@ -137,11 +142,15 @@ func (m *memoryStore) set(prefix string, r *store.Record) {
 	}
 
 	m.store.Set(key, i, r.Expiry)
+	m.notify(prefix, store.EventWrite, r)
 }
 
 func (m *memoryStore) delete(prefix, key string) {
-	key = m.key(prefix, key)
-	m.store.Delete(key)
+	if _, found := m.store.Get(m.key(prefix, key)); !found {
+		return
+	}
+	m.store.Delete(m.key(prefix, key))
+	m.notify(prefix, store.EventDelete, &store.Record{Key: key})
 }
 
 func (m *memoryStore) list(prefix string, limit, offset uint) []string {
@@ -156,6 +165,7 @@ func (m *memoryStore) list(prefix string, limit, offset uint) []string {
 		allKeys[i] = strings.TrimPrefix(k, prefix+"/")
 		i++
 	}
+	allKeys = allKeys[:i]
 
 	if limit != 0 || offset != 0 {
 		sort.Slice(allKeys, func(i, j int) bool { return allKeys[i] < allKeys[j] })
@@ -192,8 +202,14 @@ func (m *memoryStore) Read(key string, opts ...store.ReadOption) ([]*store.Recor
 
 	var keys []string
 
-	// Handle Prefix / suffix
-	if readOpts.Prefix || readOpts.Suffix {
+	if readOpts.Pattern {
+		if err := store.ValidatePattern(key); err != nil {
+			return nil, err
+		}
+	}
+
+	// Handle Prefix / suffix / pattern
+	if readOpts.Prefix || readOpts.Suffix || readOpts.Pattern {
 		k := m.list(prefix, readOpts.Limit, readOpts.Offset)
 
 		for _, kk := range k {
@@ -205,6 +221,10 @@ func (m *memoryStore) Read(key string, opts ...store.ReadOption) ([]*store.Recor
 				continue
 			}
 
+			if readOpts.Pattern && !store.MatchPattern(key, kk) {
+				continue
+			}
+
 			keys = append(keys, kk)
 		}
 	} else {
@@ -280,6 +300,12 @@ func (m *memoryStore) List(opts ...store.ListOption) ([]string, error) {
 		o(&listOptions)
 	}
 
+	if len(listOptions.Pattern) > 0 {
+		if err := store.ValidatePattern(listOptions.Pattern); err != nil {
+			return nil, err
+		}
+	}
+
 	prefix := m.prefix(listOptions.Database, listOptions.Table)
 	keys := m.list(prefix, listOptions.Limit, listOptions.Offset)
 
@@ -303,6 +329,16 @@ func (m *memoryStore) List(opts ...store.ListOption) ([]string, error) {
 		keys = suffixKeys
 	}
 
+	if len(listOptions.Pattern) > 0 {
+		var patternKeys []string
+		for _, k := range keys {
+			if store.MatchPattern(listOptions.Pattern, k) {
+				patternKeys = append(patternKeys, k)
+			}
+		}
+		keys = patternKeys
+	}
+
 	return keys, nil
 }
 
@@ -23,8 +23,11 @@
 package memory
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -299,3 +302,167 @@ func basictest(s store.Store, t *testing.T) {
 		}
 	}
 }
+
+func TestMemoryPattern(t *testing.T) {
+	s := NewStore()
+	for _, k := range []string{"orders/2021/01/a", "orders/2021/02/b", "orders/2020/01/c", "orders/2021/01", "users/01/d"} {
+		if err := s.Write(&store.Record{Key: k, Value: []byte(k)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys, err := s.List(store.ListPattern("orders/*/01/*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"orders/2020/01/c", "orders/2021/01/a"}) {
+		t.Fatalf("Unexpected keys %v", keys)
+	}
+
+	// a pattern which is a literal key only matches the key, not as prefix
+	keys, err = s.List(store.ListPattern("orders/2021/01"), store.ListPrefix("orders/2021/01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"orders/2021/01"}) {
+		t.Fatalf("Unexpected keys %v", keys)
+	}
+
+	recs, err := s.Read("orders/2021/*/*", store.ReadPattern())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(recs))
+	}
+
+	if _, err := s.List(store.ListPattern("orders/[")); !errors.Is(err, store.ErrBadPattern) {
+		t.Fatalf("Expected ErrBadPattern, got %v", err)
+	}
+	if _, err := s.Read("orders/[", store.ReadPattern()); !errors.Is(err, store.ErrBadPattern) {
+		t.Fatalf("Expected ErrBadPattern, got %v", err)
+	}
+}
+
+func TestMemoryWatch(t *testing.T) {
+	s := NewStore()
+
+	w, err := s.Watch("orders/", store.WatchPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := s.Watch("orders/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.Watch("orders/1", store.WatchFrom("", "other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Stop()
+
+	if err := s.Write(&store.Record{Key: "users/1", Value: []byte("alice")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(&store.Record{Key: "orders/1", Value: []byte("one")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("orders/1"); err != nil {
+		t.Fatal(err)
+	}
+	// deleting a missing key is not an event
+	if err := s.Delete("orders/2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(&store.Record{Key: "orders/2", Value: []byte("two")}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		t     store.EventType
+		key   string
+		value string
+	}{
+		{store.EventWrite, "orders/1", "one"},
+		{store.EventDelete, "orders/1", ""},
+		{store.EventWrite, "orders/2", "two"},
+	}
+	for _, e := range expected {
+		ev, err := w.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.Type != e.t || ev.Record.Key != e.key || string(ev.Record.Value) != e.value {
+			t.Fatalf("Expected %s %s %q, got %s %s %q", e.t, e.key, e.value, ev.Type, ev.Record.Key, ev.Record.Value)
+		}
+	}
+
+	for _, e := range expected[:2] {
+		ev, err := key.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.Type != e.t || ev.Record.Key != e.key {
+			t.Fatalf("Expected %s %s, got %s %s", e.t, e.key, ev.Type, ev.Record.Key)
+		}
+	}
+
+	// the watcher of another table sees nothing
+	select {
+	case <-next(other):
+		t.Fatal("Unexpected event from another table")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	w.Stop()
+	key.Stop()
+	if _, err := w.Next(); !errors.Is(err, store.ErrWatcherStopped) {
+		t.Fatalf("Expected ErrWatcherStopped, got %v", err)
+	}
+}
+
+func TestMemoryWatchSlow(t *testing.T) {
+	s := NewStore()
+
+	w, err := s.Watch("", store.WatchPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// the writes never block on a watcher which doesn't read
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < WatchBuffer*2; i++ {
+			s.Write(&store.Record{Key: fmt.Sprintf("%d", i)})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Writes blocked by a slow watcher")
+	}
+
+	for i := 0; i < WatchBuffer; i++ {
+		ev, err := w.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.Record.Key != fmt.Sprintf("%d", i) {
+			t.Fatalf("Expected key %d, got %s", i, ev.Record.Key)
+		}
+	}
+}
+
+func next(w store.Watcher) <-chan *store.Event {
+	ch := make(chan *store.Event, 1)
+	go func() {
+		if ev, err := w.Next(); err == nil {
+			ch <- ev
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	log "github.com/lack-io/vine/lib/logger"
+	"github.com/lack-io/vine/lib/store"
+)
+
+// WatchBuffer is the number of events buffered per watcher, the events of
+// the slow watchers are dropped beyond so that they don't block the writers
+var WatchBuffer = 100
+
+type memoryWatcher struct {
+	id string
+	// prefix is the database and table prefix of the watched keys
+	prefix string
+	key    string
+	opts   store.WatchOptions
+
+	events chan *store.Event
+	exit   chan struct{}
+	once   sync.Once
+	stop   func(id string)
+}
+
+func (w *memoryWatcher) matches(prefix, key string) bool {
+	if w.prefix != prefix {
+		return false
+	}
+	if w.opts.Prefix {
+		return strings.HasPrefix(key, w.key)
+	}
+	return key == w.key
+}
+
+func (w *memoryWatcher) Next() (*store.Event, error) {
+	select {
+	case e := <-w.events:
+		return e, nil
+	case <-w.exit:
+		return nil, store.ErrWatcherStopped
+	}
+}
+
+func (w *memoryWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.exit)
+		w.stop(w.id)
+	})
+}
+
+func (m *memoryStore) Watch(key string, opts ...store.WatchOption) (store.Watcher, error) {
+	watchOpts := store.WatchOptions{}
+	for _, o := range opts {
+		o(&watchOpts)
+	}
+
+	w := &memoryWatcher{
+		id:     uuid.New().String(),
+		prefix: m.prefix(watchOpts.Database, watchOpts.Table),
+		key:    key,
+		opts:   watchOpts,
+		events: make(chan *store.Event, WatchBuffer),
+		exit:   make(chan struct{}),
+		stop: func(id string) {
+			m.Lock()
+			delete(m.watchers, id)
+			m.Unlock()
+		},
+	}
+
+	m.Lock()
+	m.watchers[w.id] = w
+	m.Unlock()
+
+	return w, nil
+}
+
+// notify sends the event to the watchers of the record
+func (m *memoryStore) notify(prefix string, t store.EventType, r *store.Record) {
+	m.RLock()
+	defer m.RUnlock()
+
+	for _, w := range m.watchers {
+		if !w.matches(prefix, r.Key) {
+			continue
+		}
+
+		// each watcher gets its own copy of the record
+		record := &store.Record{Key: r.Key, Expiry: r.Expiry}
+		if t == store.EventWrite {
+			record.Value = make([]byte, len(r.Value))
+			copy(record.Value, r.Value)
+			record.Metadata = make(map[string]interface{}, len(r.Metadata))
+			for k, v := range r.Metadata {
+				record.Metadata[k] = v
+			}
+		}
+
+		select {
+		case w.events <- &store.Event{Type: t, Record: record}:
+		default:
+			log.Warnf("Store watcher %s is too slow, dropping the %s event of %s", w.id, t, r.Key)
+		}
+	}
+}
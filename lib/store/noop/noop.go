@@ -22,7 +22,11 @@
 
 package noop
 
-import "github.com/lack-io/vine/lib/store"
+import (
+	"sync"
+
+	"github.com/lack-io/vine/lib/store"
+)
 
 type noopStore struct{}
 
@@ -58,6 +62,24 @@ func (n *noopStore) Close() error {
 	return nil
 }
 
+type noopWatcher struct {
+	exit chan struct{}
+	once sync.Once
+}
+
+func (w *noopWatcher) Next() (*store.Event, error) {
+	<-w.exit
+	return nil, store.ErrWatcherStopped
+}
+
+func (w *noopWatcher) Stop() {
+	w.once.Do(func() { close(w.exit) })
+}
+
+func (n *noopStore) Watch(key string, opts ...store.WatchOption) (store.Watcher, error) {
+	return &noopWatcher{exit: make(chan struct{})}, nil
+}
+
 func NewStore() store.Store {
 	return new(noopStore)
 }
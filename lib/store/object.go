@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/lack-io/vine/core/codec"
+	"github.com/lack-io/vine/core/codec/json"
+	pbcodec "github.com/lack-io/vine/core/codec/proto"
+)
+
+const (
+	// ContentTypeKey is the metadata key of the content type of a record value
+	ContentTypeKey = "Content-Type"
+	// ContentTypeJSON is the content type of the json encoded values
+	ContentTypeJSON = "application/json"
+	// ContentTypeProto is the content type of the protobuf encoded values
+	ContentTypeProto = "application/protobuf"
+)
+
+var (
+	// ErrContentType is returned when a record value can't be decoded into the value
+	ErrContentType = errors.New("unsupported content type")
+
+	marshalers = map[string]codec.Marshaler{
+		ContentTypeJSON:  json.Marshaler{},
+		ContentTypeProto: pbcodec.Marshaler{},
+	}
+)
+
+// ContentType returns the content type of the record value, the records
+// written without content type are json encoded
+func ContentType(r *Record) string {
+	if ct, ok := r.Metadata[ContentTypeKey].(string); ok && len(ct) > 0 {
+		return ct
+	}
+	return ContentTypeJSON
+}
+
+// Encode returns the record of the value with its content type, the value is
+// encoded in protobuf when it's a proto.Message and in json otherwise
+func Encode(key string, v interface{}) (*Record, error) {
+	ct := ContentTypeJSON
+	if _, ok := v.(proto.Message); ok {
+		ct = ContentTypeProto
+	}
+
+	b, err := marshalers[ct].Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		Key:      key,
+		Value:    b,
+		Metadata: map[string]interface{}{ContentTypeKey: ct},
+	}, nil
+}
+
+// Decode decodes the record value into v according to the content type of
+// the record. It returns ErrContentType when v can't be decoded from it, e.g
+// a protobuf value into a value which isn't a proto.Message.
+func Decode(r *Record, v interface{}) error {
+	ct := ContentType(r)
+
+	m, ok := marshalers[ct]
+	if !ok {
+		return fmt.Errorf("%w %s of %s", ErrContentType, ct, r.Key)
+	}
+	if _, ok := v.(proto.Message); !ok && ct == ContentTypeProto {
+		return fmt.Errorf("%w %s of %s: %T isn't a proto.Message", ErrContentType, ct, r.Key, v)
+	}
+
+	return m.Unmarshal(r.Value, v)
+}
+
+// WriteObject encodes the value, see Encode, and writes it to the store
+func WriteObject(s Store, key string, v interface{}, opts ...WriteOption) error {
+	r, err := Encode(key, v)
+	if err != nil {
+		return err
+	}
+	return s.Write(r, opts...)
+}
+
+// ReadObject reads the record of the key from the store and decodes it into
+// the value, see Decode
+func ReadObject(s Store, key string, v interface{}, opts ...ReadOption) error {
+	recs, err := s.Read(key, opts...)
+	if err != nil {
+		return err
+	}
+	if len(recs) == 0 {
+		return ErrNotFound
+	}
+	return Decode(recs[0], v)
+}
@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lack-io/vine/lib/store"
+	"github.com/lack-io/vine/lib/store/memory"
+	regpb "github.com/lack-io/vine/proto/apis/registry"
+)
+
+func TestObject(t *testing.T) {
+	s := memory.NewStore()
+
+	type order struct {
+		Id    string `json:"id"`
+		Total int    `json:"total"`
+	}
+
+	if err := store.WriteObject(s, "json", &order{Id: "1", Total: 10}); err != nil {
+		t.Fatal(err)
+	}
+	o := &order{}
+	if err := store.ReadObject(s, "json", o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Id != "1" || o.Total != 10 {
+		t.Fatalf("Unexpected order %+v", o)
+	}
+
+	if err := store.WriteObject(s, "proto", &regpb.Service{Name: "go.vine.foo", Version: "latest"}); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := s.Read("proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := store.ContentType(recs[0]); ct != store.ContentTypeProto {
+		t.Fatalf("Expected content type %s, got %s", store.ContentTypeProto, ct)
+	}
+	svc := &regpb.Service{}
+	if err := store.ReadObject(s, "proto", svc); err != nil {
+		t.Fatal(err)
+	}
+	if svc.Name != "go.vine.foo" || svc.Version != "latest" {
+		t.Fatalf("Unexpected service %+v", svc)
+	}
+
+	// a protobuf value can't be decoded into a struct
+	if err := store.ReadObject(s, "proto", o); !errors.Is(err, store.ErrContentType) {
+		t.Fatalf("Expected ErrContentType, got %v", err)
+	}
+
+	// the records written without content type are json
+	if err := s.Write(&store.Record{Key: "legacy", Value: []byte(`{"id":"2"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ReadObject(s, "legacy", o); err != nil || o.Id != "2" {
+		t.Fatalf("Unexpected legacy read %+v: %v", o, err)
+	}
+}
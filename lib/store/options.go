@@ -92,6 +92,8 @@ type ReadOptions struct {
 	Prefix bool
 	// Suffix returns all records that have the suffix key
 	Suffix bool
+	// Pattern returns all records with a key matching the key as a glob pattern
+	Pattern bool
 	// Limit limits the number of returned records
 	Limit uint
 	// Offset when combined with Limit supports pagination
@@ -123,6 +125,14 @@ func ReadSuffix() ReadOption {
 	}
 }
 
+// ReadPattern returns all records with a key matching the key as a glob
+// pattern, e.g orders/*/01/*
+func ReadPattern() ReadOption {
+	return func(r *ReadOptions) {
+		r.Pattern = true
+	}
+}
+
 // ReadLimit limits the number of responses to l
 func ReadLimit(l uint) ReadOption {
 	return func(r *ReadOptions) {
@@ -196,6 +206,8 @@ type ListOptions struct {
 	Prefix string
 	// Suffix returns all keys that end with key
 	Suffix string
+	// Pattern returns all keys matching the glob pattern
+	Pattern string
 	// Limit limits the number of returned keys
 	Limit uint
 	// Offset when combined with Limit supports pagination
@@ -227,6 +239,13 @@ func ListSuffix(s string) ListOption {
 	}
 }
 
+// ListPattern returns all keys matching the glob pattern, e.g orders/*/01/*
+func ListPattern(p string) ListOption {
+	return func(l *ListOptions) {
+		l.Pattern = p
+	}
+}
+
 // ListLimit limits the number of returned keys to l
 func ListLimit(l uint) ListOption {
 	return func(lo *ListOptions) {
@@ -240,3 +259,28 @@ func ListOffset(o uint) ListOption {
 		l.Offset = o
 	}
 }
+
+// WatchOptions configures an individual Watch operation
+type WatchOptions struct {
+	Database, Table string
+	// Prefix watches all the records that are prefixed with key
+	Prefix bool
+}
+
+// WatchOption sets values in WatchOptions
+type WatchOption func(w *WatchOptions)
+
+// WatchFrom the database and table
+func WatchFrom(database, table string) WatchOption {
+	return func(w *WatchOptions) {
+		w.Database = database
+		w.Table = table
+	}
+}
+
+// WatchPrefix watches all the records that are prefixed with key
+func WatchPrefix() WatchOption {
+	return func(w *WatchOptions) {
+		w.Prefix = true
+	}
+}
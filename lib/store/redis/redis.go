@@ -0,0 +1,372 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package redis is a redis backed store using github.com/go-redis/redis.
+//
+// Watch relies on the keyspace notifications, which redis disables by default.
+// The server must publish at least the keyspace events of the string and the
+// generic commands and of the expired keys, e.g. notify-keyspace-events K$gx.
+package redis
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+
+	"github.com/lack-io/vine/lib/store"
+)
+
+var (
+	// DefaultAddress is the address of the redis server
+	DefaultAddress = "localhost:6379"
+	// DialTimeout is the timeout of the connections to redis
+	DialTimeout = 5 * time.Second
+	// ScanCount is the number of keys scanned per call when listing
+	ScanCount = 100
+)
+
+// NewStore returns a redis store, the records of each database and table
+// are kept under the database/table/ key prefix. The address is either
+// host:port or redis://[:password@]host:port[/db].
+func NewStore(opts ...store.Option) store.Store {
+	s := &redisStore{
+		options: store.Options{
+			Database: "vine",
+			Table:    "vine",
+		},
+	}
+	s.configure(opts...)
+	return s
+}
+
+type redisStore struct {
+	options store.Options
+
+	client *redis.Client
+	// db is the redis database number, part of the keyspace channels
+	db  int
+	err error
+}
+
+// storeRecord is the value stored in redis
+type storeRecord struct {
+	Value    []byte                 `json:"value"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (r *redisStore) configure(opts ...store.Option) {
+	for _, o := range opts {
+		o(&r.options)
+	}
+
+	address := DefaultAddress
+	if len(r.options.Nodes) > 0 {
+		address = r.options.Nodes[0]
+	}
+
+	if r.client != nil {
+		r.client.Close()
+		r.client = nil
+	}
+
+	o := &redis.Options{Addr: address}
+	if strings.HasPrefix(address, "redis://") {
+		if o, r.err = redis.ParseURL(address); r.err != nil {
+			return
+		}
+	}
+	o.DialTimeout = DialTimeout
+
+	r.client = redis.NewClient(o)
+	r.db = o.DB
+	r.err = nil
+}
+
+func (r *redisStore) prefix(database, table string) string {
+	if len(database) == 0 {
+		database = r.options.Database
+	}
+	if len(table) == 0 {
+		table = r.options.Table
+	}
+	return database + "/" + table + "/"
+}
+
+// escape escapes the glob characters of the scan and subscribe patterns
+func escape(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch c {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// scan returns the keys with the prefix, without it
+func (r *redisStore) scan(prefix string) ([]string, error) {
+	var keys []string
+
+	var cursor uint64
+	for {
+		found, next, err := r.client.Scan(cursor, escape(prefix)+"*", int64(ScanCount)).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range found {
+			keys = append(keys, strings.TrimPrefix(k, prefix))
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	// the keys are scanned in no particular order, and possibly twice
+	sort.Strings(keys)
+	unique := keys[:0]
+	for i, k := range keys {
+		if i == 0 || keys[i-1] != k {
+			unique = append(unique, k)
+		}
+	}
+
+	return unique, nil
+}
+
+// page returns the keys from offset up to limit keys, a zero limit returns
+// all of them
+func page(keys []string, limit, offset uint) []string {
+	if offset >= uint(len(keys)) {
+		return nil
+	}
+	keys = keys[offset:]
+	if limit > 0 && limit < uint(len(keys)) {
+		keys = keys[:limit]
+	}
+	return keys
+}
+
+func (r *redisStore) get(prefix, key string) (*store.Record, error) {
+	b, err := r.client.Get(prefix + key).Bytes()
+	if err == redis.Nil {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sr storeRecord
+	if err := json.Unmarshal(b, &sr); err != nil {
+		return nil, err
+	}
+
+	record := &store.Record{
+		Key:      key,
+		Value:    sr.Value,
+		Metadata: sr.Metadata,
+	}
+	if record.Value == nil {
+		record.Value = []byte{}
+	}
+	if record.Metadata == nil {
+		record.Metadata = make(map[string]interface{})
+	}
+
+	// the remaining time to live, negative without expiry
+	if ttl, err := r.client.PTTL(prefix + key).Result(); err == nil && ttl > 0 {
+		record.Expiry = ttl
+	}
+
+	return record, nil
+}
+
+func (r *redisStore) Init(opts ...store.Option) error {
+	r.configure(opts...)
+	return r.err
+}
+
+func (r *redisStore) Options() store.Options {
+	return r.options
+}
+
+func (r *redisStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	readOpts := store.ReadOptions{}
+	for _, o := range opts {
+		o(&readOpts)
+	}
+
+	prefix := r.prefix(readOpts.Database, readOpts.Table)
+
+	if !readOpts.Prefix && !readOpts.Suffix && !readOpts.Pattern {
+		record, err := r.get(prefix, key)
+		if err != nil {
+			return nil, err
+		}
+		return []*store.Record{record}, nil
+	}
+
+	if readOpts.Pattern {
+		if err := store.ValidatePattern(key); err != nil {
+			return nil, err
+		}
+	}
+
+	// only scan the keys with the prefix
+	scanPrefix := prefix
+	if readOpts.Prefix {
+		scanPrefix += key
+	}
+	keys, err := r.scan(scanPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []string
+	for _, k := range keys {
+		k = strings.TrimPrefix(scanPrefix+k, prefix)
+		if readOpts.Suffix && !strings.HasSuffix(k, key) {
+			continue
+		}
+		if readOpts.Pattern && !store.MatchPattern(key, k) {
+			continue
+		}
+		matched = append(matched, k)
+	}
+
+	var records []*store.Record
+	for _, k := range page(matched, readOpts.Limit, readOpts.Offset) {
+		record, err := r.get(prefix, k)
+		if err == store.ErrNotFound {
+			// expired or deleted since scanned
+			continue
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func (r *redisStore) Write(record *store.Record, opts ...store.WriteOption) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	writeOpts := store.WriteOptions{}
+	for _, o := range opts {
+		o(&writeOpts)
+	}
+
+	expiry := record.Expiry
+	if !writeOpts.Expiry.IsZero() {
+		expiry = time.Until(writeOpts.Expiry)
+	}
+	if writeOpts.TTL != 0 {
+		expiry = writeOpts.TTL
+	}
+
+	b, err := json.Marshal(&storeRecord{Value: record.Value, Metadata: record.Metadata})
+	if err != nil {
+		return err
+	}
+
+	// redis expires the keys to the millisecond
+	if expiry > 0 && expiry < time.Millisecond {
+		expiry = time.Millisecond
+	}
+
+	return r.client.Set(r.prefix(writeOpts.Database, writeOpts.Table)+record.Key, b, expiry).Err()
+}
+
+func (r *redisStore) Delete(key string, opts ...store.DeleteOption) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	deleteOpts := store.DeleteOptions{}
+	for _, o := range opts {
+		o(&deleteOpts)
+	}
+
+	return r.client.Del(r.prefix(deleteOpts.Database, deleteOpts.Table) + key).Err()
+}
+
+func (r *redisStore) List(opts ...store.ListOption) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	listOpts := store.ListOptions{}
+	for _, o := range opts {
+		o(&listOpts)
+	}
+
+	if len(listOpts.Pattern) > 0 {
+		if err := store.ValidatePattern(listOpts.Pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	prefix := r.prefix(listOpts.Database, listOpts.Table)
+	keys, err := r.scan(prefix + listOpts.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []string
+	for _, k := range keys {
+		k = listOpts.Prefix + k
+		if len(listOpts.Suffix) > 0 && !strings.HasSuffix(k, listOpts.Suffix) {
+			continue
+		}
+		if len(listOpts.Pattern) > 0 && !store.MatchPattern(listOpts.Pattern, k) {
+			continue
+		}
+		matched = append(matched, k)
+	}
+
+	return page(matched, listOpts.Limit, listOpts.Offset), nil
+}
+
+func (r *redisStore) Close() error {
+	if r.client != nil {
+		return r.client.Close()
+	}
+	return nil
+}
+
+func (r *redisStore) String() string {
+	return "redis"
+}
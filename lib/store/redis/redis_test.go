@@ -0,0 +1,420 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package redis
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/lib/store"
+)
+
+func TestConfigure(t *testing.T) {
+	s := NewStore(store.Nodes("redis://:secret@10.0.0.1:6380/2")).(*redisStore)
+	defer s.Close()
+	if o := s.client.Options(); o.Addr != "10.0.0.1:6380" || o.Password != "secret" || o.DB != 2 || s.db != 2 {
+		t.Fatalf("Unexpected options %+v", o)
+	}
+
+	s = NewStore(store.Nodes("10.0.0.1:6379")).(*redisStore)
+	defer s.Close()
+	if o := s.client.Options(); o.Addr != "10.0.0.1:6379" || len(o.Password) > 0 || o.DB != 0 {
+		t.Fatalf("Unexpected options %+v", o)
+	}
+}
+
+// readCommand reads a command, an array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line := func() (string, error) {
+		l, err := r.ReadString('\n')
+		return strings.TrimSuffix(l, "\r\n"), err
+	}
+
+	l, err := line()
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimPrefix(l, "*"))
+	cmd := make([]string, n)
+	for i := range cmd {
+		if l, err = line(); err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimPrefix(l, "$"))
+		b := make([]byte, size+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		cmd[i] = string(b[:size])
+	}
+	return cmd, nil
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+// fakeConn is a client connection, written to by the notifications too
+type fakeConn struct {
+	sync.Mutex
+	net.Conn
+	patterns []string
+}
+
+func (c *fakeConn) write(s string) error {
+	c.Lock()
+	defer c.Unlock()
+	_, err := c.Conn.Write([]byte(s))
+	return err
+}
+
+// unescape removes the escaping of the glob characters by escape
+var unescape = strings.NewReplacer(`\*`, "*", `\?`, "?", `\[`, "[", `\]`, "]", `\\`, `\`).Replace
+
+// fakeRedis serves the commands used by the store from a map, the patterns
+// are either exact or end with a single *
+type fakeRedis struct {
+	sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+	conns   map[*fakeConn]bool
+}
+
+func matches(pattern, key string) bool {
+	if strings.HasSuffix(pattern, "*") && !strings.HasSuffix(pattern, `\*`) {
+		return strings.HasPrefix(key, unescape(strings.TrimSuffix(pattern, "*")))
+	}
+	return key == unescape(pattern)
+}
+
+// notify publishes the keyspace notification of the event to the subscribers
+func (f *fakeRedis) notify(event, key string) {
+	channel := "__keyspace@0__:" + key
+	for c := range f.conns {
+		c.Lock()
+		patterns := c.patterns
+		c.Unlock()
+		for _, p := range patterns {
+			if matches(p, channel) {
+				c.write("*4\r\n" + bulk("pmessage") + bulk(p) + bulk(channel) + bulk(event))
+			}
+		}
+	}
+}
+
+func (f *fakeRedis) reply(c *fakeConn, cmd []string) string {
+	f.Lock()
+	defer f.Unlock()
+
+	for k, e := range f.expires {
+		if time.Now().After(e) {
+			delete(f.values, k)
+			delete(f.expires, k)
+			f.notify("expired", k)
+		}
+	}
+
+	switch strings.ToUpper(cmd[0]) {
+	case "SET":
+		f.values[cmd[1]] = cmd[2]
+		delete(f.expires, cmd[1])
+		if len(cmd) == 5 {
+			n, _ := strconv.Atoi(cmd[4])
+			d := time.Duration(n) * time.Millisecond
+			if strings.ToUpper(cmd[3]) == "EX" {
+				d = time.Duration(n) * time.Second
+			}
+			f.expires[cmd[1]] = time.Now().Add(d)
+		}
+		f.notify("set", cmd[1])
+		return "+OK\r\n"
+	case "GET":
+		v, ok := f.values[cmd[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(v)
+	case "PTTL":
+		e, ok := f.expires[cmd[1]]
+		if !ok {
+			return ":-1\r\n"
+		}
+		return fmt.Sprintf(":%d\r\n", time.Until(e).Milliseconds())
+	case "DEL":
+		if _, ok := f.values[cmd[1]]; !ok {
+			return ":0\r\n"
+		}
+		delete(f.values, cmd[1])
+		delete(f.expires, cmd[1])
+		f.notify("del", cmd[1])
+		return ":1\r\n"
+	case "SCAN":
+		// pages of 2 keys, the cursor is the index of the next page
+		var keys []string
+		for k := range f.values {
+			if matches(cmd[3], k) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		start, _ := strconv.Atoi(cmd[1])
+		end, next := start+2, strconv.Itoa(start+2)
+		if end >= len(keys) {
+			end, next = len(keys), "0"
+		}
+		rsp := fmt.Sprintf("*2\r\n%s*%d\r\n", bulk(next), end-start)
+		for _, k := range keys[start:end] {
+			rsp += bulk(k)
+		}
+		return rsp
+	case "PSUBSCRIBE":
+		var rsp string
+		c.Lock()
+		for _, p := range cmd[1:] {
+			c.patterns = append(c.patterns, p)
+			rsp += fmt.Sprintf("*3\r\n%s%s:%d\r\n", bulk("psubscribe"), bulk(p), len(c.patterns))
+		}
+		c.Unlock()
+		return rsp
+	case "PING":
+		return "*2\r\n" + bulk("pong") + bulk("")
+	}
+	return "-ERR unknown command\r\n"
+}
+
+func newFakeRedis(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	f := &fakeRedis{
+		values:  make(map[string]string),
+		expires: make(map[string]time.Time),
+		conns:   make(map[*fakeConn]bool),
+	}
+	go func() {
+		for {
+			nc, err := l.Accept()
+			if err != nil {
+				return
+			}
+			c := &fakeConn{Conn: nc}
+			f.Lock()
+			f.conns[c] = true
+			f.Unlock()
+
+			go func() {
+				defer func() {
+					f.Lock()
+					delete(f.conns, c)
+					f.Unlock()
+					c.Close()
+				}()
+				r := bufio.NewReader(c)
+				for {
+					cmd, err := readCommand(r)
+					if err != nil || len(cmd) == 0 {
+						return
+					}
+					if err := c.write(f.reply(c, cmd)); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func TestStore(t *testing.T) {
+	testStore(t, NewStore(store.Nodes(newFakeRedis(t))))
+}
+
+// TestRedis runs against the redis server at VINE_REDIS_ADDRESS
+func TestRedis(t *testing.T) {
+	address := os.Getenv("VINE_REDIS_ADDRESS")
+	if len(address) == 0 {
+		t.Skip("VINE_REDIS_ADDRESS not set")
+	}
+	testStore(t, NewStore(store.Nodes(address), store.Database(fmt.Sprintf("test-%d", time.Now().UnixNano()))))
+}
+
+func testStore(t *testing.T, s store.Store) {
+	defer s.Close()
+
+	if _, err := s.Read("foo"); err != store.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", store.ErrNotFound, err)
+	}
+
+	for _, key := range []string{"foo", "foobar", "foo/baz", "barfoo", "a*b"} {
+		if err := s.Write(&store.Record{Key: key, Value: []byte(key), Metadata: map[string]interface{}{"key": key}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// other tables are kept apart
+	if err := s.Write(&store.Record{Key: "foo", Value: []byte("other")}, store.WriteTo("", "other")); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := s.Read("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || string(records[0].Value) != "foo" || records[0].Metadata["key"] != "foo" || records[0].Expiry != 0 {
+		t.Fatalf("Unexpected records %+v", records)
+	}
+
+	records, err = s.Read("foo", store.ReadFrom("", "other"))
+	if err != nil || string(records[0].Value) != "other" {
+		t.Fatalf("Unexpected records %+v: %v", records, err)
+	}
+
+	keys := func(records []*store.Record) []string {
+		var keys []string
+		for _, r := range records {
+			keys = append(keys, r.Key)
+		}
+		return keys
+	}
+
+	records, err = s.Read("foo", store.ReadPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k := keys(records); !reflect.DeepEqual(k, []string{"foo", "foo/baz", "foobar"}) {
+		t.Fatalf("Unexpected prefix keys %v", k)
+	}
+
+	records, err = s.Read("foo", store.ReadSuffix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k := keys(records); !reflect.DeepEqual(k, []string{"barfoo", "foo"}) {
+		t.Fatalf("Unexpected suffix keys %v", k)
+	}
+
+	// the glob characters of the keys are not patterns
+	list, err := s.List(store.ListPrefix("a*"))
+	if err != nil || !reflect.DeepEqual(list, []string{"a*b"}) {
+		t.Fatalf("Unexpected keys %v: %v", list, err)
+	}
+
+	list, err = s.List(store.ListPrefix("foo"), store.ListLimit(2), store.ListOffset(1))
+	if err != nil || !reflect.DeepEqual(list, []string{"foo/baz", "foobar"}) {
+		t.Fatalf("Unexpected page %v: %v", list, err)
+	}
+
+	list, err = s.List(store.ListPattern("foo/*"))
+	if err != nil || !reflect.DeepEqual(list, []string{"foo/baz"}) {
+		t.Fatalf("Unexpected pattern keys %v: %v", list, err)
+	}
+
+	list, err = s.List()
+	if err != nil || len(list) != 5 {
+		t.Fatalf("Expected 5 keys, got %v: %v", list, err)
+	}
+
+	if err := s.Delete("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Read("foo"); err != store.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", store.ErrNotFound, err)
+	}
+
+	// the ttl maps to the redis expiry
+	if err := s.Write(&store.Record{Key: "ttl", Value: []byte("ttl")}, store.WriteTTL(100*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	records, err = s.Read("ttl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := records[0].Expiry; e <= 0 || e > 100*time.Millisecond {
+		t.Fatalf("Unexpected expiry %v", e)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if _, err := s.Read("ttl"); err != store.ErrNotFound {
+		t.Fatalf("Expected the record to expire, got %v", err)
+	}
+
+	for _, key := range []string{"foobar", "foo/baz", "barfoo", "a*b"} {
+		s.Delete(key)
+	}
+	s.Delete("foo", store.DeleteFrom("", "other"))
+}
+
+func TestWatch(t *testing.T) {
+	s := NewStore(store.Nodes(newFakeRedis(t)))
+	defer s.Close()
+
+	w, err := s.Watch("foo", store.WatchPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	if err := s.Write(&store.Record{Key: "bar", Value: []byte("bar")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(&store.Record{Key: "foo/1", Value: []byte("one")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("foo/1"); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the record may be deleted before being read back, then only the delete is seen
+	if e.Type == store.EventWrite {
+		if e.Record.Key != "foo/1" || string(e.Record.Value) != "one" {
+			t.Fatalf("Unexpected write event %+v", e.Record)
+		}
+		if e, err = w.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if e.Type != store.EventDelete || e.Record.Key != "foo/1" {
+		t.Fatalf("Expected the delete of foo/1, got %v of %+v", e.Type, e.Record)
+	}
+
+	w.Stop()
+	if _, err := w.Next(); err != store.ErrWatcherStopped {
+		t.Fatalf("Expected %v, got %v", store.ErrWatcherStopped, err)
+	}
+}
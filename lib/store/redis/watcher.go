@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package redis
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v7"
+
+	"github.com/lack-io/vine/lib/store"
+)
+
+// redisWatcher turns the keyspace notifications of the watched keys into
+// store events
+type redisWatcher struct {
+	store *redisStore
+	// prefix is the database and table prefix of the watched keys
+	prefix string
+	// channel is the keyspace channel prefix of the keys of the database
+	channel string
+
+	pubsub *redis.PubSub
+	events <-chan *redis.Message
+	exit   chan struct{}
+	once   sync.Once
+}
+
+func (w *redisWatcher) Next() (*store.Event, error) {
+	for {
+		var msg *redis.Message
+		select {
+		case m, ok := <-w.events:
+			if !ok {
+				return nil, store.ErrWatcherStopped
+			}
+			msg = m
+		case <-w.exit:
+			return nil, store.ErrWatcherStopped
+		}
+
+		key := strings.TrimPrefix(strings.TrimPrefix(msg.Channel, w.channel), w.prefix)
+
+		switch msg.Payload {
+		case "set":
+			// the notifications don't carry the values, the record is read
+			// back and skipped if deleted in the meantime
+			record, err := w.store.get(w.prefix, key)
+			if err == store.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &store.Event{Type: store.EventWrite, Record: record}, nil
+		case "del", "expired", "evicted":
+			return &store.Event{Type: store.EventDelete, Record: &store.Record{Key: key}}, nil
+		}
+	}
+}
+
+func (w *redisWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.exit)
+		w.pubsub.Close()
+	})
+}
+
+// Watch subscribes to the keyspace notifications of the key, see the package
+// doc for the notifications the server must publish
+func (r *redisStore) Watch(key string, opts ...store.WatchOption) (store.Watcher, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	watchOpts := store.WatchOptions{}
+	for _, o := range opts {
+		o(&watchOpts)
+	}
+
+	w := &redisWatcher{
+		store:   r,
+		prefix:  r.prefix(watchOpts.Database, watchOpts.Table),
+		channel: fmt.Sprintf("__keyspace@%d__:", r.db),
+		exit:    make(chan struct{}),
+	}
+
+	pattern := escape(w.channel + w.prefix + key)
+	if watchOpts.Prefix {
+		pattern += "*"
+	}
+
+	// wait for the subscription so that no write is missed after Watch
+	w.pubsub = r.client.PSubscribe(pattern)
+	if _, err := w.pubsub.Receive(); err != nil {
+		w.pubsub.Close()
+		return nil, err
+	}
+	w.events = w.pubsub.Channel()
+
+	return w, nil
+}
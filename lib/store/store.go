@@ -25,12 +25,16 @@ package store
 
 import (
 	"errors"
+	"fmt"
+	"path"
 	"time"
 )
 
 var (
 	// ErrNotFound is returned when a key doesn't exist
 	ErrNotFound = errors.New("not found")
+	// ErrBadPattern is returned when a glob pattern is malformed
+	ErrBadPattern = errors.New("bad pattern")
 	// DefaultStore is the memory store.
 	DefaultStore Store
 )
@@ -49,6 +53,8 @@ type Store interface {
 	Delete(key string, opts ...DeleteOption) error
 	// List returns any keys that match, or an empty list with no error if none matched.
 	List(opts ...ListOption) ([]string, error)
+	// Watch returns a watcher of the writes and deletes of the key, or of the keys with the prefix.
+	Watch(key string, opts ...WatchOption) (Watcher, error)
 	// Close the store
 	Close() error
 	// String returns the name of the implementation.
@@ -66,3 +72,19 @@ type Record struct {
 	// Time to expire a record: TODO: change to timestamp
 	Expiry time.Duration `json:"expiry,omitempty"`
 }
+
+// ValidatePattern returns ErrBadPattern if the glob pattern is malformed
+func ValidatePattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("%w: %s", ErrBadPattern, pattern)
+	}
+	return nil
+}
+
+// MatchPattern reports whether the key matches the glob pattern, the pattern
+// syntax is the one of path.Match, '*' doesn't match the '/' of the keys.
+// The pattern must have been validated by ValidatePattern.
+func MatchPattern(pattern, key string) bool {
+	ok, _ := path.Match(pattern, key)
+	return ok
+}
@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package store
+
+import "errors"
+
+var (
+	// ErrWatcherStopped is returned when the watcher is stopped
+	ErrWatcherStopped = errors.New("watcher stopped")
+	// ErrWatchNotSupported is returned by the stores unable to watch
+	ErrWatchNotSupported = errors.New("watch not supported")
+)
+
+// EventType is the type of the store event
+type EventType int
+
+const (
+	// EventWrite is emitted when a record is written
+	EventWrite EventType = iota
+	// EventDelete is emitted when a record is deleted
+	EventDelete
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventWrite:
+		return "write"
+	case EventDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a change of a record in the store, the deleted records only
+// have their key set
+type Event struct {
+	Type   EventType
+	Record *Record
+}
+
+// Watcher returns the changes of the watched records
+type Watcher interface {
+	// Next is a blocking call returning the next event
+	Next() (*Event, error)
+	// Stop stops the watcher
+	Stop()
+}
@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package trace
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Edge is the calls made by a service to another
+type Edge struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Calls    int           `json:"calls"`
+	Errors   int           `json:"errors"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Graph is the service to service call graph
+type Graph struct {
+	Services []string `json:"services"`
+	Edges    []*Edge  `json:"edges"`
+}
+
+// Unknown is the caller of the calls made outside of a traced service
+const Unknown = "unknown"
+
+// service returns the service of the span, from its metadata or its name
+// which is the service followed by the endpoint e.g go.vine.foo.Foo.Call
+func service(s *Span) string {
+	if v := s.Metadata["service"]; len(v) > 0 {
+		return v
+	}
+	parts := strings.Split(s.Name, ".")
+	if len(parts) < 3 {
+		return s.Name
+	}
+	return strings.Join(parts[:len(parts)-2], ".")
+}
+
+// NewGraph builds the call graph of the outbound spans. The caller of a call
+// is the service which made it, or the service of the inbound span it was
+// made in.
+func NewGraph(spans []*Span) *Graph {
+	byID := make(map[string]*Span, len(spans))
+	for _, s := range spans {
+		byID[s.Id] = s
+	}
+
+	edges := make(map[string]*Edge)
+	services := make(map[string]bool)
+
+	for _, s := range spans {
+		if s.Type != SpanTypeRequestOutbound {
+			continue
+		}
+
+		from := s.Metadata["caller"]
+		if len(from) == 0 {
+			if p, ok := byID[s.Parent]; ok && p.Type == SpanTypeRequestInbound {
+				from = service(p)
+			} else {
+				from = Unknown
+			}
+		}
+		to := service(s)
+
+		k := from + "\x00" + to
+		e, ok := edges[k]
+		if !ok {
+			e = &Edge{From: from, To: to}
+			edges[k] = e
+		}
+		e.Calls++
+		e.Duration += s.Duration
+		if len(s.Metadata["error"]) > 0 {
+			e.Errors++
+		}
+
+		services[from] = true
+		services[to] = true
+	}
+
+	g := &Graph{
+		Services: make([]string, 0, len(services)),
+		Edges:    make([]*Edge, 0, len(edges)),
+	}
+	for s := range services {
+		g.Services = append(g.Services, s)
+	}
+	for _, e := range edges {
+		g.Edges = append(g.Edges, e)
+	}
+
+	sort.Strings(g.Services)
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+
+	return g
+}
+
+// DOT renders the graph in the graphviz format
+func (g *Graph) DOT() []byte {
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString("digraph services {\n")
+	for _, s := range g.Services {
+		fmt.Fprintf(buf, "\t%q;\n", s)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(buf, "\t%q -> %q [label=\"%d calls, %d errors\"];\n", e.From, e.To, e.Calls, e.Errors)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// String renders the graph one edge per line
+func (g *Graph) String() string {
+	buf := bytes.NewBuffer(nil)
+	for _, e := range g.Edges {
+		fmt.Fprintf(buf, "%s -> %s calls=%d errors=%d avg=%v\n", e.From, e.To, e.Calls, e.Errors, e.Duration/time.Duration(e.Calls))
+	}
+	return buf.String()
+}
@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package trace
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewGraph(t *testing.T) {
+	spans := []*Span{
+		// the api calls foo without an inbound span
+		{Id: "1", Name: "go.vine.foo.Foo.Call", Type: SpanTypeRequestOutbound, Duration: time.Millisecond, Metadata: map[string]string{"caller": "go.vine.api"}},
+		{Id: "2", Parent: "1", Name: "go.vine.foo.Foo.Call", Type: SpanTypeRequestInbound, Metadata: map[string]string{}},
+		// foo calls bar twice while serving the request, once failing
+		{Id: "3", Parent: "2", Name: "go.vine.bar.Bar.Get", Type: SpanTypeRequestOutbound, Duration: time.Millisecond, Metadata: map[string]string{}},
+		{Id: "4", Parent: "2", Name: "go.vine.bar.Bar.Get", Type: SpanTypeRequestOutbound, Duration: time.Millisecond, Metadata: map[string]string{"error": "timeout"}},
+		// a call of a client which isn't traced
+		{Id: "5", Name: "go.vine.bar.Bar.Get", Type: SpanTypeRequestOutbound, Duration: time.Millisecond, Metadata: map[string]string{"service": "go.vine.bar"}},
+	}
+
+	g := NewGraph(spans)
+
+	expect := []Edge{
+		{From: "go.vine.api", To: "go.vine.foo", Calls: 1},
+		{From: "go.vine.foo", To: "go.vine.bar", Calls: 2, Errors: 1},
+		{From: Unknown, To: "go.vine.bar", Calls: 1},
+	}
+	if len(g.Edges) != len(expect) {
+		t.Fatalf("Expected %d edges, got %+v", len(expect), g.Edges)
+	}
+	for i, e := range expect {
+		got := g.Edges[i]
+		if got.From != e.From || got.To != e.To || got.Calls != e.Calls || got.Errors != e.Errors {
+			t.Fatalf("Expected edge %+v, got %+v", e, got)
+		}
+	}
+
+	if len(g.Services) != 4 {
+		t.Fatalf("Expected 4 services, got %v", g.Services)
+	}
+
+	dot := string(g.DOT())
+	if !strings.Contains(dot, `"go.vine.foo" -> "go.vine.bar" [label="2 calls, 1 errors"];`) {
+		t.Fatalf("Unexpected dot output:\n%s", dot)
+	}
+}
@@ -0,0 +1,218 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package jaeger is a tracer exporting the spans to a jaeger collector
+package jaeger
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/lack-io/vine/lib/logger"
+	"github.com/lack-io/vine/lib/trace"
+	"github.com/lack-io/vine/lib/trace/memory"
+)
+
+var (
+	// DefaultAddress is the address of the collector http endpoint
+	DefaultAddress = "localhost:14268"
+	// DefaultServiceName is the service name of the spans without service metadata
+	DefaultServiceName = "vine"
+	// DefaultFlushInterval is how often the spans are sent
+	DefaultFlushInterval = time.Second
+	// DefaultBatchSize is the number of spans which triggers a flush
+	DefaultBatchSize = 100
+)
+
+// Tracer records the spans like the memory tracer and sends them in batches
+// to the http endpoint of a jaeger collector
+type Tracer struct {
+	trace.Tracer
+
+	opts     trace.Options
+	endpoint string
+	service  string
+	interval time.Duration
+	size     int
+	client   *http.Client
+
+	sync.Mutex
+	spans []*trace.Span
+	flush chan struct{}
+	exit  chan struct{}
+	once  sync.Once
+}
+
+// Finish records the span and queues it to be sent
+func (t *Tracer) Finish(s *trace.Span) error {
+	if err := t.Tracer.Finish(s); err != nil {
+		return err
+	}
+
+	t.Lock()
+	// drop the oldest spans when the collector is unreachable
+	if len(t.spans) >= t.size*10 {
+		t.spans = t.spans[1:]
+	}
+	t.spans = append(t.spans, s)
+	full := len(t.spans) >= t.size
+	t.Unlock()
+
+	if full {
+		select {
+		case t.flush <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Flush sends the queued spans to the collector, one batch per service
+func (t *Tracer) Flush() error {
+	t.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	services := make(map[string][]*trace.Span)
+	for _, s := range spans {
+		name := s.Metadata["service"]
+		if len(name) == 0 {
+			name = t.service
+		}
+		services[name] = append(services[name], s)
+	}
+
+	var gerr error
+	for name, spans := range services {
+		if err := t.send(encodeBatch(name, spans)); err != nil && gerr == nil {
+			gerr = err
+		}
+	}
+
+	return gerr
+}
+
+func (t *Tracer) send(b []byte) error {
+	req, err := http.NewRequest(http.MethodPost, t.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-thrift")
+
+	rsp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("jaeger collector %s returned %s", t.endpoint, rsp.Status)
+	}
+	return nil
+}
+
+func (t *Tracer) run() {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.exit:
+			return
+		case <-ticker.C:
+		case <-t.flush:
+		}
+
+		if err := t.Flush(); err != nil {
+			log.Errorf("Error sending spans to jaeger: %v", err)
+		}
+	}
+}
+
+// Close sends the queued spans and stops the tracer
+func (t *Tracer) Close() error {
+	t.once.Do(func() {
+		close(t.exit)
+	})
+	return t.Flush()
+}
+
+// endpoint returns the url of the collector http endpoint of the address
+func endpoint(address string) string {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	if strings.Count(address, "/") < 3 {
+		address = strings.TrimSuffix(address, "/") + "/api/traces"
+	}
+	return address
+}
+
+func NewTracer(opts ...trace.Option) trace.Tracer {
+	options := trace.DefaultOptions()
+	for _, o := range opts {
+		o(&options)
+	}
+
+	address := DefaultAddress
+	if len(options.Addrs) > 0 && len(options.Addrs[0]) > 0 {
+		address = options.Addrs[0]
+	}
+
+	t := &Tracer{
+		Tracer:   memory.NewTracer(opts...),
+		opts:     options,
+		endpoint: endpoint(address),
+		service:  DefaultServiceName,
+		interval: DefaultFlushInterval,
+		size:     DefaultBatchSize,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		flush:    make(chan struct{}, 1),
+		exit:     make(chan struct{}),
+	}
+
+	if ctx := options.Context; ctx != nil {
+		if v, ok := ctx.Value(serviceNameKey{}).(string); ok && len(v) > 0 {
+			t.service = v
+		}
+		if v, ok := ctx.Value(flushIntervalKey{}).(time.Duration); ok && v > 0 {
+			t.interval = v
+		}
+		if v, ok := ctx.Value(batchSizeKey{}).(int); ok && v > 0 {
+			t.size = v
+		}
+	}
+
+	go t.run()
+
+	return t
+}
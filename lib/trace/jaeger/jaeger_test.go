@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package jaeger
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lack-io/vine/lib/trace"
+)
+
+func TestTracer(t *testing.T) {
+	bodies := make(chan []byte, 10)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/traces" {
+			t.Errorf("Expected path /api/traces, got %s", r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-thrift" {
+			t.Errorf("Expected content type application/x-thrift, got %s", ct)
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies <- b
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	tr := NewTracer(trace.Addrs(ts.URL), FlushInterval(time.Hour), BatchSize(2)).(*Tracer)
+	defer tr.Close()
+
+	ctx, s := tr.Start(context.Background(), "greeter.Say.Hello")
+	s.Metadata["service"] = "greeter"
+	s.Metadata["error"] = "boom"
+	if err := tr.Finish(s); err != nil {
+		t.Fatal(err)
+	}
+
+	// the span is still readable like the memory tracer
+	spans, err := tr.Read(trace.ReadTrace(s.Trace))
+	if err != nil || len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d: %v", len(spans), err)
+	}
+
+	_, c := tr.Start(ctx, "store.Read")
+	c.Type = trace.SpanTypeRequestOutbound
+	if err := tr.Finish(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// the batch size triggers the flush, one batch per service
+	var got [][]byte
+	for len(got) < 2 {
+		select {
+		case b := <-bodies:
+			got = append(got, b)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("Expected 2 batches, got %d", len(got))
+		}
+	}
+
+	all := bytes.Join(got, nil)
+	for _, v := range []string{"greeter", "greeter.Say.Hello", DefaultServiceName, "store.Read", "error.message", "boom", "client", "server"} {
+		if !bytes.Contains(all, []byte(v)) {
+			t.Errorf("Expected batches to contain %q", v)
+		}
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	testData := map[string]string{
+		"localhost:14268":                   "http://localhost:14268/api/traces",
+		"https://jaeger:14268":              "https://jaeger:14268/api/traces",
+		"http://jaeger:14268/custom/traces": "http://jaeger:14268/custom/traces",
+	}
+
+	for addr, want := range testData {
+		if got := endpoint(addr); got != want {
+			t.Errorf("Expected endpoint %s for %s, got %s", want, addr, got)
+		}
+	}
+}
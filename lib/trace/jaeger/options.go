@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package jaeger
+
+import (
+	"context"
+	"time"
+
+	"github.com/lack-io/vine/lib/trace"
+)
+
+type serviceNameKey struct{}
+type flushIntervalKey struct{}
+type batchSizeKey struct{}
+
+// ServiceName sets the service name of the spans without service metadata
+func ServiceName(name string) trace.Option {
+	return setOption(serviceNameKey{}, name)
+}
+
+// FlushInterval sets how often the spans are sent to the collector
+func FlushInterval(d time.Duration) trace.Option {
+	return setOption(flushIntervalKey{}, d)
+}
+
+// BatchSize sets the number of spans which triggers a flush before the interval
+func BatchSize(n int) trace.Option {
+	return setOption(batchSizeKey{}, n)
+}
+
+func setOption(k, v interface{}) trace.Option {
+	return func(o *trace.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
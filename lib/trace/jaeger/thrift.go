@@ -0,0 +1,233 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package jaeger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/fnv"
+	"os"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/lack-io/vine/lib/trace"
+)
+
+// thrift binary protocol types
+const (
+	typeStop   byte = 0
+	typeBool   byte = 2
+	typeI32    byte = 8
+	typeI64    byte = 10
+	typeString byte = 11
+	typeStruct byte = 12
+	typeList   byte = 15
+)
+
+// jaeger.thrift tag types
+const (
+	tagString int32 = 0
+	tagBool   int32 = 2
+)
+
+// encoder writes the jaeger.thrift structs with the thrift binary protocol
+type encoder struct {
+	bytes.Buffer
+}
+
+func (e *encoder) field(typ byte, id int16) {
+	e.WriteByte(typ)
+	e.i16(id)
+}
+
+func (e *encoder) stop() {
+	e.WriteByte(typeStop)
+}
+
+func (e *encoder) i16(v int16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], uint16(v))
+	e.Write(b[:])
+}
+
+func (e *encoder) i32(v int32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(v))
+	e.Write(b[:])
+}
+
+func (e *encoder) i64(v int64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(v))
+	e.Write(b[:])
+}
+
+func (e *encoder) str(s string) {
+	e.i32(int32(len(s)))
+	e.WriteString(s)
+}
+
+func (e *encoder) list(typ byte, n int) {
+	e.WriteByte(typ)
+	e.i32(int32(n))
+}
+
+type tag struct {
+	key  string
+	str  string
+	bool bool
+	typ  int32
+}
+
+func (e *encoder) tag(t tag) {
+	e.field(typeString, 1)
+	e.str(t.key)
+	e.field(typeI32, 2)
+	e.i32(t.typ)
+	switch t.typ {
+	case tagBool:
+		e.field(typeBool, 5)
+		if t.bool {
+			e.WriteByte(1)
+		} else {
+			e.WriteByte(0)
+		}
+	default:
+		e.field(typeString, 3)
+		e.str(t.str)
+	}
+	e.stop()
+}
+
+func (e *encoder) tags(id int16, tags []tag) {
+	e.field(typeList, id)
+	e.list(typeStruct, len(tags))
+	for _, t := range tags {
+		e.tag(t)
+	}
+}
+
+// id64 returns the 64 bits of an id, the low bits of an uuid or the hash
+// of the other ids
+func id64(id string) int64 {
+	if len(id) == 0 {
+		return 0
+	}
+	if u, err := uuid.Parse(id); err == nil {
+		return int64(binary.BigEndian.Uint64(u[8:]))
+	}
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return int64(h.Sum64())
+}
+
+// traceID returns the high and low 64 bits of a trace id
+func traceID(id string) (int64, int64) {
+	if u, err := uuid.Parse(id); err == nil {
+		return int64(binary.BigEndian.Uint64(u[:8])), int64(binary.BigEndian.Uint64(u[8:]))
+	}
+	return 0, id64(id)
+}
+
+// spanTags returns the standard tags of the span followed by its metadata
+func spanTags(s *trace.Span) []tag {
+	kind := "server"
+	if s.Type == trace.SpanTypeRequestOutbound {
+		kind = "client"
+	}
+
+	tags := []tag{
+		{key: "span.kind", str: kind},
+		{key: "component", str: "vine"},
+	}
+
+	keys := make([]string, 0, len(s.Metadata))
+	for k := range s.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := s.Metadata[k]
+		switch k {
+		case "service":
+			// the service is the process of the batch
+		case "error":
+			tags = append(tags, tag{key: "error", bool: true, typ: tagBool}, tag{key: "error.message", str: v})
+		default:
+			tags = append(tags, tag{key: k, str: v})
+		}
+	}
+
+	return tags
+}
+
+func (e *encoder) span(s *trace.Span) {
+	high, low := traceID(s.Trace)
+
+	e.field(typeI64, 1)
+	e.i64(low)
+	e.field(typeI64, 2)
+	e.i64(high)
+	e.field(typeI64, 3)
+	e.i64(id64(s.Id))
+	e.field(typeI64, 4)
+	e.i64(id64(s.Parent))
+	e.field(typeString, 5)
+	e.str(s.Name)
+	// sampled
+	e.field(typeI32, 7)
+	e.i32(1)
+	// microseconds
+	e.field(typeI64, 8)
+	e.i64(s.Started.UnixNano() / 1000)
+	e.field(typeI64, 9)
+	e.i64(int64(s.Duration) / 1000)
+	e.tags(10, spanTags(s))
+	e.stop()
+}
+
+// encodeBatch returns the thrift encoded batch of the spans of the service
+func encodeBatch(service string, spans []*trace.Span) []byte {
+	e := new(encoder)
+
+	// process
+	e.field(typeStruct, 1)
+	e.field(typeString, 1)
+	e.str(service)
+	if hostname, err := os.Hostname(); err == nil {
+		e.tags(2, []tag{{key: "hostname", str: hostname}})
+	}
+	e.stop()
+
+	// spans
+	e.field(typeList, 2)
+	e.list(typeStruct, len(spans))
+	for _, s := range spans {
+		e.span(s)
+	}
+
+	e.stop()
+	return e.Bytes()
+}
@@ -22,13 +22,28 @@
 
 package trace
 
+import "context"
+
 type Options struct {
 	// Size is the size of ring buffer
 	Size int
+	// Addrs are the addresses of the trace collectors
+	Addrs []string
+
+	// Other options for implementations of the interface
+	// can be stored in a context
+	Context context.Context
 }
 
 type Option func(o *Options)
 
+// Addrs sets the addresses of the trace collectors
+func Addrs(addrs ...string) Option {
+	return func(o *Options) {
+		o.Addrs = addrs
+	}
+}
+
 type ReadOptions struct {
 	// Trace id
 	Trace string
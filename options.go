@@ -37,6 +37,7 @@ import (
 	"github.com/lack-io/vine/lib/cmd"
 	"github.com/lack-io/vine/lib/config"
 	"github.com/lack-io/vine/lib/dao"
+	"github.com/lack-io/vine/lib/logger"
 	"github.com/lack-io/vine/lib/trace"
 )
 
@@ -63,6 +64,12 @@ type Options struct {
 	Context context.Context
 
 	Signal bool
+
+	// LogCalls logs the calls of the client and the requests served
+	LogCalls bool
+	// Logger of the calls and the requests, nil is the default logger
+	// at the time of the call
+	Logger logger.Logger
 }
 
 func newOptions(opts ...Option) Options {
@@ -126,6 +133,21 @@ func HandleSignal(b bool) Option {
 	}
 }
 
+// LogCalls logs the calls of the client and the requests served by the
+// service, with their tag set by client.WithLogTag
+func LogCalls(b bool) Option {
+	return func(o *Options) {
+		o.LogCalls = b
+	}
+}
+
+// Logger of the calls and the requests logged by LogCalls
+func Logger(l logger.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
 // Server to be used for service
 func Server(s server.Server) Option {
 	return func(o *Options) {
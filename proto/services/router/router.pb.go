@@ -346,6 +346,168 @@ func (m *QueryResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_QueryResponse proto.InternalMessageInfo
 
+// TableRequest is made to Table, the routes are filtered by the query
+type TableRequest struct {
+	Query *Query `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (m *TableRequest) Reset()         { *m = TableRequest{} }
+func (m *TableRequest) String() string { return proto.CompactTextString(m) }
+func (*TableRequest) ProtoMessage()    {}
+func (*TableRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5a0219df09765ec1, []int{7}
+}
+func (m *TableRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TableRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TableRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TableRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TableRequest.Merge(m, src)
+}
+func (m *TableRequest) XXX_Size() int {
+	return m.XSize()
+}
+func (m *TableRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TableRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TableRequest proto.InternalMessageInfo
+
+// TableRoute is a route of the routing table streamed by Table
+type TableRoute struct {
+	Route *Route `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	// age of the route in nanoseconds, since it was last created or updated
+	Age int64 `protobuf:"varint,2,opt,name=age,proto3" json:"age,omitempty"`
+}
+
+func (m *TableRoute) Reset()         { *m = TableRoute{} }
+func (m *TableRoute) String() string { return proto.CompactTextString(m) }
+func (*TableRoute) ProtoMessage()    {}
+func (*TableRoute) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5a0219df09765ec1, []int{8}
+}
+func (m *TableRoute) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TableRoute) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TableRoute.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TableRoute) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TableRoute.Merge(m, src)
+}
+func (m *TableRoute) XXX_Size() int {
+	return m.XSize()
+}
+func (m *TableRoute) XXX_DiscardUnknown() {
+	xxx_messageInfo_TableRoute.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TableRoute proto.InternalMessageInfo
+
+// StatsRequest is made to Stats
+type StatsRequest struct {
+}
+
+func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
+func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatsRequest) ProtoMessage()    {}
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5a0219df09765ec1, []int{9}
+}
+func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StatsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsRequest.Merge(m, src)
+}
+func (m *StatsRequest) XXX_Size() int {
+	return m.XSize()
+}
+func (m *StatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsRequest proto.InternalMessageInfo
+
+// StatsResponse is returned by Stats
+type StatsResponse struct {
+	// number of routes in the table
+	Routes int64 `protobuf:"varint,1,opt,name=routes,proto3" json:"routes,omitempty"`
+	// number of routing table events processed
+	Events uint64 `protobuf:"varint,2,opt,name=events,proto3" json:"events,omitempty"`
+	// number of adverts sent
+	AdvertsSent uint64 `protobuf:"varint,3,opt,name=adverts_sent,json=advertsSent,proto3" json:"adverts_sent,omitempty"`
+	// number of adverts received
+	AdvertsReceived uint64 `protobuf:"varint,4,opt,name=adverts_received,json=advertsReceived,proto3" json:"adverts_received,omitempty"`
+	// unix timestamp of the last registry sync
+	LastSync int64 `protobuf:"varint,5,opt,name=last_sync,json=lastSync,proto3" json:"last_sync,omitempty"`
+}
+
+func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
+func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatsResponse) ProtoMessage()    {}
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5a0219df09765ec1, []int{10}
+}
+func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StatsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsResponse.Merge(m, src)
+}
+func (m *StatsResponse) XXX_Size() int {
+	return m.XSize()
+}
+func (m *StatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsResponse proto.InternalMessageInfo
+
 // WatchRequest is made to Watch Router
 type WatchRequest struct {
 }
@@ -354,7 +516,7 @@ func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
 func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
 func (*WatchRequest) ProtoMessage()    {}
 func (*WatchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5a0219df09765ec1, []int{7}
+	return fileDescriptor_5a0219df09765ec1, []int{11}
 }
 func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -401,7 +563,7 @@ func (m *Advert) Reset()         { *m = Advert{} }
 func (m *Advert) String() string { return proto.CompactTextString(m) }
 func (*Advert) ProtoMessage()    {}
 func (*Advert) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5a0219df09765ec1, []int{8}
+	return fileDescriptor_5a0219df09765ec1, []int{12}
 }
 func (m *Advert) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -438,7 +600,7 @@ func (m *ProcessResponse) Reset()         { *m = ProcessResponse{} }
 func (m *ProcessResponse) String() string { return proto.CompactTextString(m) }
 func (*ProcessResponse) ProtoMessage()    {}
 func (*ProcessResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5a0219df09765ec1, []int{9}
+	return fileDescriptor_5a0219df09765ec1, []int{13}
 }
 func (m *ProcessResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -475,7 +637,7 @@ func (m *CreateResponse) Reset()         { *m = CreateResponse{} }
 func (m *CreateResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateResponse) ProtoMessage()    {}
 func (*CreateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5a0219df09765ec1, []int{10}
+	return fileDescriptor_5a0219df09765ec1, []int{14}
 }
 func (m *CreateResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -512,7 +674,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5a0219df09765ec1, []int{11}
+	return fileDescriptor_5a0219df09765ec1, []int{15}
 }
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -549,7 +711,7 @@ func (m *UpdateResponse) Reset()         { *m = UpdateResponse{} }
 func (m *UpdateResponse) String() string { return proto.CompactTextString(m) }
 func (*UpdateResponse) ProtoMessage()    {}
 func (*UpdateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5a0219df09765ec1, []int{12}
+	return fileDescriptor_5a0219df09765ec1, []int{16}
 }
 func (m *UpdateResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -594,7 +756,7 @@ func (m *Event) Reset()         { *m = Event{} }
 func (m *Event) String() string { return proto.CompactTextString(m) }
 func (*Event) ProtoMessage()    {}
 func (*Event) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5a0219df09765ec1, []int{13}
+	return fileDescriptor_5a0219df09765ec1, []int{17}
 }
 func (m *Event) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -631,13 +793,15 @@ type Query struct {
 	Gateway string `protobuf:"bytes,2,opt,name=gateway,proto3" json:"gateway,omitempty"`
 	// network to lookup
 	Network string `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+	// metadata the routes must match
+	Metadata map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *Query) Reset()         { *m = Query{} }
 func (m *Query) String() string { return proto.CompactTextString(m) }
 func (*Query) ProtoMessage()    {}
 func (*Query) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5a0219df09765ec1, []int{14}
+	return fileDescriptor_5a0219df09765ec1, []int{18}
 }
 func (m *Query) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -682,13 +846,15 @@ type Route struct {
 	Link string `protobuf:"bytes,6,opt,name=link,proto3" json:"link,omitempty"`
 	// the metric / score of this route
 	Metric int64 `protobuf:"varint,7,opt,name=metric,proto3" json:"metric,omitempty"`
+	// metadata of the service node
+	Metadata map[string]string `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *Route) Reset()         { *m = Route{} }
 func (m *Route) String() string { return proto.CompactTextString(m) }
 func (*Route) ProtoMessage()    {}
 func (*Route) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5a0219df09765ec1, []int{15}
+	return fileDescriptor_5a0219df09765ec1, []int{19}
 }
 func (m *Route) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -727,6 +893,10 @@ func init() {
 	proto.RegisterType((*LookupResponse)(nil), "router.LookupResponse")
 	proto.RegisterType((*QueryRequest)(nil), "router.QueryRequest")
 	proto.RegisterType((*QueryResponse)(nil), "router.QueryResponse")
+	proto.RegisterType((*TableRequest)(nil), "router.TableRequest")
+	proto.RegisterType((*TableRoute)(nil), "router.TableRoute")
+	proto.RegisterType((*StatsRequest)(nil), "router.StatsRequest")
+	proto.RegisterType((*StatsResponse)(nil), "router.StatsResponse")
 	proto.RegisterType((*WatchRequest)(nil), "router.WatchRequest")
 	proto.RegisterType((*Advert)(nil), "router.Advert")
 	proto.RegisterType((*ProcessResponse)(nil), "router.ProcessResponse")
@@ -735,7 +905,9 @@ func init() {
 	proto.RegisterType((*UpdateResponse)(nil), "router.UpdateResponse")
 	proto.RegisterType((*Event)(nil), "router.Event")
 	proto.RegisterType((*Query)(nil), "router.Query")
+	proto.RegisterMapType((map[string]string)(nil), "router.Query.MetadataEntry")
 	proto.RegisterType((*Route)(nil), "router.Route")
+	proto.RegisterMapType((map[string]string)(nil), "router.Route.MetadataEntry")
 }
 
 func init() {
@@ -743,51 +915,64 @@ func init() {
 }
 
 var fileDescriptor_5a0219df09765ec1 = []byte{
-	// 689 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0xcd, 0x4e, 0xdb, 0x40,
-	0x10, 0xf6, 0x26, 0x71, 0xd2, 0x4c, 0x43, 0x30, 0x2b, 0xa0, 0x16, 0xaa, 0xac, 0xc8, 0x88, 0x0a,
-	0x21, 0x15, 0xd3, 0x40, 0xa9, 0xda, 0x9e, 0xe8, 0xcf, 0x8d, 0x43, 0x6b, 0x81, 0x2a, 0xf5, 0x66,
-	0x9c, 0x11, 0x58, 0x49, 0x6c, 0xe3, 0xdd, 0x04, 0xe5, 0xd8, 0x63, 0x6f, 0xbd, 0xf5, 0x41, 0xfa,
-	0x12, 0x1c, 0x39, 0xf6, 0x52, 0xa9, 0x85, 0x17, 0xa9, 0xbc, 0x3f, 0x21, 0x36, 0xa2, 0x6a, 0x4e,
-	0xde, 0x99, 0x6f, 0xbf, 0x99, 0xd9, 0xd9, 0xf9, 0xd6, 0xf0, 0xea, 0x34, 0xe2, 0x67, 0xa3, 0x93,
-	0xed, 0x30, 0x19, 0x7a, 0x83, 0x20, 0xec, 0x3f, 0x8d, 0x12, 0x6f, 0x1c, 0xc5, 0xe8, 0xa5, 0x59,
-	0xc2, 0x13, 0x8f, 0x61, 0x36, 0x8e, 0x42, 0x64, 0x5e, 0x96, 0x8c, 0x38, 0x66, 0xea, 0xb3, 0x2d,
-	0x40, 0x5a, 0x97, 0x96, 0xdb, 0x84, 0x86, 0x8f, 0xe7, 0x23, 0x64, 0xdc, 0x05, 0x78, 0xe0, 0x23,
-	0x4b, 0x93, 0x98, 0xa1, 0xfb, 0x1c, 0x5a, 0x87, 0x11, 0xe3, 0xda, 0xa6, 0x1b, 0x20, 0x09, 0xcc,
-	0x26, 0x9d, 0xea, 0xe6, 0xc3, 0xee, 0xc2, 0xb6, 0x8a, 0xe6, 0xe7, 0x1f, 0x5f, 0x81, 0xee, 0x1e,
-	0x2c, 0x1c, 0x26, 0x49, 0x7f, 0x94, 0xaa, 0x98, 0x74, 0x1d, 0xcc, 0xf3, 0x11, 0x66, 0x13, 0x9b,
-	0x74, 0xc8, 0x2c, 0xed, 0x63, 0xee, 0xf4, 0x25, 0xe6, 0xbe, 0x80, 0xb6, 0x66, 0xcd, 0x97, 0x6e,
-	0x17, 0x5a, 0x32, 0xd0, 0x3c, 0xd9, 0xf6, 0x61, 0x41, 0x91, 0xe6, 0x4b, 0xd6, 0x86, 0xd6, 0xa7,
-	0x80, 0x87, 0x67, 0xba, 0x5d, 0xdf, 0x09, 0xd4, 0x0f, 0x7a, 0x63, 0xcc, 0x38, 0x6d, 0x43, 0x25,
-	0xea, 0x89, 0xa4, 0x4d, 0xbf, 0x12, 0xf5, 0xe8, 0x13, 0xa8, 0xf1, 0x49, 0x8a, 0x76, 0xa5, 0x43,
-	0x36, 0xdb, 0x5d, 0xaa, 0xe3, 0xc9, 0xdd, 0x47, 0x93, 0x14, 0x7d, 0x81, 0xd3, 0xc7, 0xd0, 0xe4,
-	0xd1, 0x10, 0x19, 0x0f, 0x86, 0xa9, 0x5d, 0xed, 0x90, 0xcd, 0xaa, 0x7f, 0xeb, 0xa0, 0x16, 0x54,
-	0x39, 0x1f, 0xd8, 0x35, 0xe1, 0xcf, 0x97, 0x79, 0xa5, 0x38, 0xc6, 0x98, 0x33, 0xdb, 0x2c, 0x56,
-	0xfa, 0x3e, 0xf7, 0xfa, 0x0a, 0x74, 0x97, 0x60, 0xf1, 0x43, 0x96, 0x84, 0xc8, 0xd8, 0xf4, 0x3e,
-	0x2d, 0x68, 0xbf, 0xcd, 0x30, 0xe0, 0x38, 0xeb, 0x79, 0x87, 0x03, 0x2c, 0x7a, 0x8e, 0xd3, 0xde,
-	0xec, 0x9e, 0x2f, 0x04, 0x4c, 0x11, 0xfa, 0xce, 0x09, 0x37, 0x0a, 0x27, 0x5c, 0x2a, 0xd4, 0xf1,
-	0xdf, 0x07, 0x5c, 0x07, 0x53, 0xf0, 0xc4, 0x11, 0xef, 0xf4, 0x5d, 0x62, 0xee, 0x31, 0x98, 0xe2,
-	0xba, 0xa8, 0x0d, 0x0d, 0x35, 0xd1, 0xaa, 0x0e, 0x6d, 0xe6, 0xc8, 0x69, 0xc0, 0xf1, 0x22, 0x98,
-	0x88, 0x7a, 0x9a, 0xbe, 0x36, 0x73, 0x24, 0x46, 0x7e, 0x91, 0x64, 0x7d, 0x91, 0xbd, 0xe9, 0x6b,
-	0xd3, 0xfd, 0x41, 0xc0, 0x14, 0x79, 0xfe, 0x1d, 0x37, 0xe8, 0xf5, 0x32, 0x64, 0x4c, 0xc7, 0x55,
-	0xe6, 0x6c, 0xc6, 0xea, 0xbd, 0x19, 0x6b, 0x85, 0x8c, 0x74, 0x55, 0x8d, 0x59, 0x66, 0x9b, 0x02,
-	0x50, 0x16, 0xa5, 0x50, 0x1b, 0x44, 0x71, 0xdf, 0xae, 0x0b, 0xaf, 0x58, 0xe7, 0x7b, 0x87, 0xc8,
-	0xb3, 0x28, 0xb4, 0x1b, 0xa2, 0x69, 0xca, 0xda, 0xea, 0x02, 0xdc, 0x0e, 0x11, 0xa5, 0xd0, 0x96,
-	0xd6, 0x41, 0x1c, 0x27, 0xa3, 0x38, 0x44, 0xcb, 0xa0, 0x16, 0xb4, 0xa4, 0x4f, 0x5e, 0xa5, 0x45,
-	0xb6, 0x3c, 0x68, 0x4e, 0xaf, 0x85, 0x02, 0xd4, 0xe5, 0x1c, 0x58, 0x46, 0xbe, 0x96, 0x13, 0x60,
-	0x91, 0x7c, 0xad, 0x08, 0x95, 0xee, 0x2f, 0x02, 0x75, 0x5f, 0xd6, 0xf6, 0x12, 0xea, 0x52, 0x99,
-	0x74, 0x45, 0x5f, 0x4e, 0x41, 0xdf, 0x6b, 0xab, 0x65, 0xb7, 0x9a, 0x1c, 0x83, 0xee, 0x80, 0x29,
-	0xe4, 0x42, 0x97, 0xf5, 0x96, 0x59, 0xf5, 0xac, 0x15, 0x47, 0xd7, 0x35, 0x76, 0x08, 0xdd, 0x81,
-	0xa6, 0x2c, 0x3d, 0x62, 0x48, 0x17, 0xa7, 0xc3, 0xa0, 0x08, 0xed, 0xa2, 0x8a, 0x04, 0x63, 0x0f,
-	0x1a, 0x6a, 0xd0, 0x69, 0x09, 0x5e, 0x7b, 0xa4, 0xed, 0xb2, 0x12, 0x8c, 0xee, 0xd7, 0x0a, 0x98,
-	0x47, 0xc1, 0xc9, 0x00, 0xe9, 0x33, 0xdd, 0x0d, 0x5a, 0x9c, 0xbd, 0xdb, 0x63, 0x95, 0x44, 0x63,
-	0xe4, 0x14, 0xd9, 0xb4, 0x7b, 0x29, 0x25, 0x55, 0x09, 0x8a, 0xec, 0xed, 0xbd, 0x94, 0x92, 0xec,
-	0x0c, 0xea, 0x41, 0x2d, 0x7f, 0x7e, 0xef, 0x76, 0x61, 0xda, 0xcc, 0xd9, 0xd7, 0xd9, 0x35, 0xe8,
-	0xbe, 0x56, 0xc9, 0x72, 0xf1, 0xcd, 0x53, 0xb4, 0x95, 0x92, 0x57, 0xf3, 0xde, 0xf8, 0x97, 0x7f,
-	0x1c, 0xe3, 0xf2, 0xda, 0x21, 0x57, 0xd7, 0x0e, 0xf9, 0x7d, 0xed, 0x90, 0x6f, 0x37, 0x8e, 0x71,
-	0x75, 0xe3, 0x18, 0x3f, 0x6f, 0x1c, 0xe3, 0xf3, 0xde, 0x5c, 0x3f, 0x98, 0xd7, 0xf2, 0x73, 0x52,
-	0x17, 0xe8, 0xee, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xe9, 0xc6, 0x4d, 0xbf, 0x9f, 0x06, 0x00,
-	0x00,
+	// 900 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xcd, 0x8e, 0x1b, 0x45,
+	0x10, 0x9e, 0xb1, 0x3d, 0xde, 0x9d, 0x8a, 0xed, 0x75, 0x5a, 0x9b, 0x30, 0x72, 0x90, 0xb5, 0x0c,
+	0x0a, 0x0a, 0x91, 0x58, 0x6f, 0x9c, 0x4d, 0x02, 0xc9, 0x29, 0x84, 0xdc, 0x82, 0x04, 0xbd, 0x41,
+	0x48, 0x5c, 0xa2, 0xde, 0x71, 0x69, 0x33, 0xb2, 0x3d, 0xe3, 0x4c, 0xb7, 0x1d, 0xcd, 0x91, 0x23,
+	0x37, 0x6e, 0x3c, 0x03, 0x3c, 0x49, 0x24, 0x38, 0xe4, 0xc8, 0x11, 0x76, 0x5f, 0x04, 0xf5, 0x9f,
+	0x3d, 0x3d, 0xab, 0x45, 0xf8, 0xc0, 0x69, 0xba, 0xbe, 0xea, 0xea, 0xaa, 0xfa, 0xba, 0xaa, 0xa6,
+	0xe1, 0xf1, 0x59, 0x2a, 0x5e, 0x2f, 0x4f, 0x0f, 0x93, 0x7c, 0x3e, 0x9a, 0xb1, 0x64, 0xfa, 0x59,
+	0x9a, 0x8f, 0x56, 0x69, 0x86, 0xa3, 0x45, 0x91, 0x8b, 0x7c, 0xc4, 0xb1, 0x58, 0xa5, 0x09, 0xf2,
+	0x51, 0x91, 0x2f, 0x05, 0x16, 0xe6, 0x73, 0xa8, 0x94, 0xa4, 0xad, 0xa5, 0x38, 0x84, 0x1d, 0x8a,
+	0x6f, 0x96, 0xc8, 0x45, 0x0c, 0xb0, 0x4b, 0x91, 0x2f, 0xf2, 0x8c, 0x63, 0xfc, 0x00, 0x3a, 0x2f,
+	0x52, 0x2e, 0xac, 0x4c, 0x6e, 0x83, 0x36, 0xe0, 0x91, 0x7f, 0xd0, 0xbc, 0x73, 0x6d, 0xdc, 0x3d,
+	0x34, 0xa7, 0x51, 0xf9, 0xa1, 0x46, 0x19, 0x1f, 0x43, 0xf7, 0x45, 0x9e, 0x4f, 0x97, 0x0b, 0x73,
+	0x26, 0xf9, 0x18, 0x82, 0x37, 0x4b, 0x2c, 0xca, 0xc8, 0x3f, 0xf0, 0xab, 0x66, 0xdf, 0x4a, 0x90,
+	0x6a, 0x5d, 0xfc, 0x08, 0x7a, 0xd6, 0x6a, 0x3b, 0x77, 0xf7, 0xa1, 0xa3, 0x0f, 0xda, 0xc6, 0xdb,
+	0x43, 0xe8, 0x1a, 0xa3, 0xad, 0x9d, 0xbd, 0x64, 0xa7, 0x33, 0xdc, 0xca, 0xd9, 0x33, 0x00, 0x6d,
+	0x24, 0x75, 0xd2, 0x44, 0x6d, 0xaa, 0x9b, 0x68, 0x47, 0x5a, 0x47, 0xfa, 0xd0, 0x64, 0x67, 0x18,
+	0x35, 0x0e, 0xfc, 0x3b, 0x4d, 0x2a, 0x97, 0x71, 0x0f, 0x3a, 0x27, 0x82, 0x09, 0x6e, 0x2f, 0xea,
+	0x37, 0x1f, 0xba, 0x06, 0x30, 0x29, 0xdc, 0xac, 0xa4, 0x20, 0xcd, 0x8c, 0x24, 0x71, 0x5c, 0x61,
+	0x26, 0xb8, 0x3a, 0xae, 0x45, 0x8d, 0x44, 0x3e, 0x82, 0x0e, 0x9b, 0xac, 0xb0, 0x10, 0xfc, 0x15,
+	0xc7, 0x4c, 0x44, 0x4d, 0xa5, 0xbd, 0x66, 0xb0, 0x13, 0xcc, 0x04, 0xf9, 0x14, 0xfa, 0x76, 0x4b,
+	0x81, 0x09, 0xa6, 0x2b, 0x9c, 0x44, 0x2d, 0xb5, 0x6d, 0xcf, 0xe0, 0xd4, 0xc0, 0xe4, 0x16, 0x84,
+	0x33, 0xc6, 0xc5, 0x2b, 0x5e, 0x66, 0x49, 0x14, 0xa8, 0x00, 0x76, 0x25, 0x70, 0x52, 0x66, 0x89,
+	0x0c, 0xfe, 0x7b, 0x26, 0x92, 0xd7, 0x36, 0xf8, 0x5f, 0x7c, 0x68, 0x3f, 0x55, 0x07, 0x90, 0x1e,
+	0x34, 0xd2, 0x89, 0x8a, 0x38, 0xa4, 0x8d, 0x74, 0x42, 0x3e, 0x81, 0x96, 0x28, 0x17, 0x3a, 0xf5,
+	0xde, 0x98, 0x58, 0x76, 0xf4, 0xee, 0x97, 0xe5, 0x02, 0xa9, 0xd2, 0x93, 0x0f, 0x21, 0x14, 0xe9,
+	0x1c, 0xb9, 0x60, 0xf3, 0x85, 0x0a, 0xbd, 0x49, 0x37, 0x80, 0xe4, 0x4f, 0x88, 0x99, 0x8a, 0xb5,
+	0x49, 0xe5, 0x52, 0x5e, 0xb0, 0x61, 0x21, 0x70, 0x2f, 0xf8, 0xb9, 0x44, 0x2d, 0x29, 0xf1, 0x75,
+	0xd8, 0xfb, 0xa6, 0xc8, 0x13, 0xe4, 0x6b, 0x5e, 0xe3, 0x3e, 0xf4, 0x9e, 0x15, 0xc8, 0x04, 0x56,
+	0x91, 0xaf, 0x70, 0x86, 0x2e, 0xf2, 0xdd, 0x62, 0x52, 0xdd, 0xf3, 0xa3, 0x0f, 0x81, 0x3a, 0xfa,
+	0x52, 0x86, 0xb7, 0x9d, 0x0c, 0xaf, 0x3b, 0x71, 0xfc, 0xe7, 0x04, 0xd7, 0x55, 0xd4, 0xba, 0xba,
+	0x8a, 0xe2, 0xdf, 0x7d, 0x08, 0x54, 0x25, 0x92, 0x08, 0x76, 0xcc, 0x24, 0x30, 0x81, 0x58, 0x51,
+	0x6a, 0xce, 0x98, 0xc0, 0xb7, 0xac, 0x54, 0x01, 0x85, 0xd4, 0x8a, 0x52, 0x93, 0xa1, 0x78, 0x9b,
+	0x17, 0x53, 0xe5, 0x3e, 0xa4, 0x56, 0x24, 0x8f, 0x60, 0x77, 0x8e, 0x82, 0x4d, 0x98, 0x60, 0x51,
+	0x4b, 0xb1, 0x79, 0xcb, 0x29, 0xfc, 0xc3, 0xaf, 0x8d, 0xf6, 0x79, 0x26, 0x8a, 0x92, 0xae, 0x37,
+	0x0f, 0x9e, 0x40, 0xd7, 0x51, 0xc9, 0x7b, 0x9a, 0x62, 0x69, 0x62, 0x92, 0x4b, 0xb2, 0x0f, 0xc1,
+	0x8a, 0xcd, 0x96, 0x68, 0xa2, 0xd1, 0xc2, 0xe3, 0xc6, 0xe7, 0x7e, 0xfc, 0x6b, 0x03, 0x02, 0xdd,
+	0x42, 0xff, 0x9a, 0x0d, 0x9b, 0x4c, 0x0a, 0xe4, 0xdc, 0x66, 0x63, 0xc4, 0x6a, 0x9e, 0xcd, 0x2b,
+	0xf3, 0x6c, 0xb9, 0x79, 0xda, 0x8e, 0x2a, 0x54, 0x41, 0x87, 0xa6, 0xa3, 0x0a, 0x42, 0xa0, 0x35,
+	0x4b, 0xb3, 0x69, 0xd4, 0x56, 0xa8, 0x5a, 0xcb, 0xbd, 0x73, 0x14, 0x45, 0x9a, 0x44, 0x3b, 0xba,
+	0xfb, 0xb4, 0xe4, 0x70, 0xb5, 0xeb, 0x72, 0xa5, 0x92, 0xf9, 0x5f, 0xb8, 0xba, 0x3b, 0x06, 0xd8,
+	0x74, 0x0c, 0x21, 0xd0, 0xd3, 0xd2, 0xd3, 0x2c, 0xcb, 0x97, 0x59, 0x82, 0x7d, 0x8f, 0xf4, 0xa1,
+	0xa3, 0x31, 0x5d, 0xb7, 0x7d, 0xff, 0xee, 0x08, 0xc2, 0x75, 0x0d, 0x12, 0x80, 0xb6, 0x2e, 0xfa,
+	0xbe, 0x27, 0xd7, 0xba, 0xdc, 0xfb, 0xbe, 0x5c, 0x1b, 0x83, 0xc6, 0xf8, 0x8f, 0x06, 0xb4, 0xa9,
+	0x66, 0xe4, 0x0b, 0x68, 0xeb, 0xe9, 0x4d, 0x6e, 0xd8, 0xec, 0x9c, 0x7f, 0xc0, 0xe0, 0x66, 0x1d,
+	0x36, 0x6d, 0xe2, 0x91, 0x23, 0x08, 0xd4, 0x6c, 0x20, 0xfb, 0x76, 0x4b, 0x75, 0x54, 0x0c, 0xdc,
+	0x3e, 0x8d, 0xbd, 0x23, 0x9f, 0x1c, 0x41, 0xa8, 0x43, 0x4f, 0x39, 0x92, 0xbd, 0x35, 0x9b, 0xc6,
+	0xa0, 0xe7, 0x8e, 0x0c, 0x65, 0x71, 0x0c, 0x3b, 0xa6, 0xab, 0x49, 0x4d, 0x3d, 0xf8, 0xc0, 0xca,
+	0xf5, 0xb6, 0xf7, 0xc8, 0x03, 0x08, 0xd4, 0xdc, 0xde, 0x44, 0x56, 0x9d, 0xfd, 0x03, 0xe2, 0xa2,
+	0x72, 0xad, 0x9c, 0x3d, 0x84, 0x40, 0x0d, 0xe6, 0x8d, 0x59, 0x75, 0x70, 0x0f, 0x6e, 0xd4, 0x50,
+	0xeb, 0x6e, 0xfc, 0x53, 0xc3, 0xfa, 0xbb, 0x67, 0xc9, 0x27, 0x6e, 0x5f, 0x6f, 0x58, 0xac, 0x0d,
+	0x24, 0x8f, 0xdc, 0xb3, 0x77, 0x74, 0xa5, 0x49, 0x6d, 0x62, 0x29, 0x13, 0x7d, 0x95, 0x57, 0x9a,
+	0xd4, 0x46, 0x9a, 0x47, 0x46, 0xd0, 0x92, 0x2f, 0x82, 0xcb, 0xa4, 0xaf, 0x53, 0xad, 0x3e, 0x18,
+	0x62, 0x4f, 0x72, 0xa1, 0x07, 0xd0, 0xbe, 0xfb, 0x67, 0xac, 0x73, 0xe1, 0xfc, 0x8c, 0x63, 0xef,
+	0x4b, 0xfa, 0xee, 0xef, 0xa1, 0xf7, 0xee, 0x7c, 0xe8, 0xbf, 0x3f, 0x1f, 0xfa, 0x7f, 0x9d, 0x0f,
+	0xfd, 0x9f, 0x2f, 0x86, 0xde, 0xfb, 0x8b, 0xa1, 0xf7, 0xe7, 0xc5, 0xd0, 0xfb, 0xe1, 0x78, 0xab,
+	0x37, 0xcf, 0x13, 0xfd, 0x39, 0x6d, 0x2b, 0xed, 0xfd, 0x7f, 0x06, 0x00, 0x72, 0xe2, 0x9a, 0x66,
+	0x32, 0x09, 0x00, 0x00,
 }
 
 func (m *Request) XSize() (n int) {
@@ -879,6 +1064,68 @@ func (m *QueryResponse) XSize() (n int) {
 	return n
 }
 
+func (m *TableRequest) XSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Query != nil {
+		l = m.Query.XSize()
+		n += 1 + l + sovRouter(uint64(l))
+	}
+	return n
+}
+
+func (m *TableRoute) XSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Route != nil {
+		l = m.Route.XSize()
+		n += 1 + l + sovRouter(uint64(l))
+	}
+	if m.Age != 0 {
+		n += 1 + sovRouter(uint64(m.Age))
+	}
+	return n
+}
+
+func (m *StatsRequest) XSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *StatsResponse) XSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Routes != 0 {
+		n += 1 + sovRouter(uint64(m.Routes))
+	}
+	if m.Events != 0 {
+		n += 1 + sovRouter(uint64(m.Events))
+	}
+	if m.AdvertsSent != 0 {
+		n += 1 + sovRouter(uint64(m.AdvertsSent))
+	}
+	if m.AdvertsReceived != 0 {
+		n += 1 + sovRouter(uint64(m.AdvertsReceived))
+	}
+	if m.LastSync != 0 {
+		n += 1 + sovRouter(uint64(m.LastSync))
+	}
+	return n
+}
+
 func (m *WatchRequest) XSize() (n int) {
 	if m == nil {
 		return 0
@@ -993,6 +1240,14 @@ func (m *Query) XSize() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRouter(uint64(l))
 	}
+	if len(m.Metadata) > 0 {
+		for k, v := range m.Metadata {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovRouter(uint64(len(k))) + 1 + len(v) + sovRouter(uint64(len(v)))
+			n += mapEntrySize + 1 + sovRouter(uint64(mapEntrySize))
+		}
+	}
 	return n
 }
 
@@ -1029,6 +1284,14 @@ func (m *Route) XSize() (n int) {
 	if m.Metric != 0 {
 		n += 1 + sovRouter(uint64(m.Metric))
 	}
+	if len(m.Metadata) > 0 {
+		for k, v := range m.Metadata {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovRouter(uint64(len(k))) + 1 + len(v) + sovRouter(uint64(len(v)))
+			n += mapEntrySize + 1 + sovRouter(uint64(mapEntrySize))
+		}
+	}
 	return n
 }
 
@@ -1265,7 +1528,7 @@ func (m *QueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *WatchRequest) Marshal() (dAtA []byte, err error) {
+func (m *TableRequest) Marshal() (dAtA []byte, err error) {
 	size := m.XSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1275,20 +1538,32 @@ func (m *WatchRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *WatchRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *TableRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.XSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *WatchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TableRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
+	if m.Query != nil {
+		{
+			size, err := m.Query.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRouter(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Advert) Marshal() (dAtA []byte, err error) {
+func (m *TableRoute) Marshal() (dAtA []byte, err error) {
 	size := m.XSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1298,56 +1573,37 @@ func (m *Advert) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Advert) MarshalTo(dAtA []byte) (int, error) {
+func (m *TableRoute) MarshalTo(dAtA []byte) (int, error) {
 	size := m.XSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Advert) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TableRoute) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Events) > 0 {
-		for iNdEx := len(m.Events) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Events[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintRouter(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x2a
-		}
-	}
-	if m.Ttl != 0 {
-		i = encodeVarintRouter(dAtA, i, uint64(m.Ttl))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.Timestamp != 0 {
-		i = encodeVarintRouter(dAtA, i, uint64(m.Timestamp))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.Type != 0 {
-		i = encodeVarintRouter(dAtA, i, uint64(m.Type))
+	if m.Age != 0 {
+		i = encodeVarintRouter(dAtA, i, uint64(m.Age))
 		i--
 		dAtA[i] = 0x10
 	}
-	if len(m.Id) > 0 {
-		i -= len(m.Id)
-		copy(dAtA[i:], m.Id)
-		i = encodeVarintRouter(dAtA, i, uint64(len(m.Id)))
+	if m.Route != nil {
+		{
+			size, err := m.Route.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRouter(dAtA, i, uint64(size))
+		}
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *ProcessResponse) Marshal() (dAtA []byte, err error) {
+func (m *StatsRequest) Marshal() (dAtA []byte, err error) {
 	size := m.XSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1357,12 +1613,12 @@ func (m *ProcessResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ProcessResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *StatsRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.XSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ProcessResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *StatsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -1370,7 +1626,7 @@ func (m *ProcessResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *CreateResponse) Marshal() (dAtA []byte, err error) {
+func (m *StatsResponse) Marshal() (dAtA []byte, err error) {
 	size := m.XSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1380,20 +1636,45 @@ func (m *CreateResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *CreateResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *StatsResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.XSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *CreateResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *StatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
+	if m.LastSync != 0 {
+		i = encodeVarintRouter(dAtA, i, uint64(m.LastSync))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.AdvertsReceived != 0 {
+		i = encodeVarintRouter(dAtA, i, uint64(m.AdvertsReceived))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.AdvertsSent != 0 {
+		i = encodeVarintRouter(dAtA, i, uint64(m.AdvertsSent))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Events != 0 {
+		i = encodeVarintRouter(dAtA, i, uint64(m.Events))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Routes != 0 {
+		i = encodeVarintRouter(dAtA, i, uint64(m.Routes))
+		i--
+		dAtA[i] = 0x8
+	}
 	return len(dAtA) - i, nil
 }
 
-func (m *DeleteResponse) Marshal() (dAtA []byte, err error) {
+func (m *WatchRequest) Marshal() (dAtA []byte, err error) {
 	size := m.XSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1403,12 +1684,12 @@ func (m *DeleteResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *DeleteResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *WatchRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.XSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *DeleteResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *WatchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -1416,7 +1697,7 @@ func (m *DeleteResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *UpdateResponse) Marshal() (dAtA []byte, err error) {
+func (m *Advert) Marshal() (dAtA []byte, err error) {
 	size := m.XSize()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1426,31 +1707,159 @@ func (m *UpdateResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *UpdateResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *Advert) MarshalTo(dAtA []byte) (int, error) {
 	size := m.XSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *UpdateResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Advert) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	return len(dAtA) - i, nil
-}
-
-func (m *Event) Marshal() (dAtA []byte, err error) {
-	size := m.XSize()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
-
-func (m *Event) MarshalTo(dAtA []byte) (int, error) {
-	size := m.XSize()
+	if len(m.Events) > 0 {
+		for iNdEx := len(m.Events) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Events[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRouter(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.Ttl != 0 {
+		i = encodeVarintRouter(dAtA, i, uint64(m.Ttl))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintRouter(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Type != 0 {
+		i = encodeVarintRouter(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRouter(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ProcessResponse) Marshal() (dAtA []byte, err error) {
+	size := m.XSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ProcessResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.XSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ProcessResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *CreateResponse) Marshal() (dAtA []byte, err error) {
+	size := m.XSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CreateResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.XSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CreateResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteResponse) Marshal() (dAtA []byte, err error) {
+	size := m.XSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.XSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *UpdateResponse) Marshal() (dAtA []byte, err error) {
+	size := m.XSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.XSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *Event) Marshal() (dAtA []byte, err error) {
+	size := m.XSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Event) MarshalTo(dAtA []byte) (int, error) {
+	size := m.XSize()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
@@ -1511,6 +1920,25 @@ func (m *Query) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Metadata) > 0 {
+		for k := range m.Metadata {
+			v := m.Metadata[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintRouter(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintRouter(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintRouter(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if len(m.Network) > 0 {
 		i -= len(m.Network)
 		copy(dAtA[i:], m.Network)
@@ -1555,6 +1983,25 @@ func (m *Route) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Metadata) > 0 {
+		for k := range m.Metadata {
+			v := m.Metadata[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintRouter(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintRouter(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintRouter(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
 	if m.Metric != 0 {
 		i = encodeVarintRouter(dAtA, i, uint64(m.Metric))
 		i--
@@ -1948,7 +2395,368 @@ func (m *LookupResponse) Unmarshal(dAtA []byte) error {
 			if err := m.Routes[len(m.Routes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			iNdEx = postIndex
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRouter(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRouter
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Query == nil {
+				m.Query = &Query{}
+			}
+			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRouter(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRouter
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Routes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Routes = append(m.Routes, &Route{})
+			if err := m.Routes[len(m.Routes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRouter(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TableRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRouter
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TableRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TableRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Query == nil {
+				m.Query = &Query{}
+			}
+			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRouter(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TableRoute) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRouter
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TableRoute: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TableRoute: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Route", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Route == nil {
+				m.Route = &Route{}
+			}
+			if err := m.Route.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Age", wireType)
+			}
+			m.Age = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Age |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRouter(dAtA[iNdEx:])
@@ -1970,7 +2778,7 @@ func (m *LookupResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryRequest) Unmarshal(dAtA []byte) error {
+func (m *StatsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1993,48 +2801,12 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: StatsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StatsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowRouter
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthRouter
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthRouter
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Query == nil {
-				m.Query = &Query{}
-			}
-			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRouter(dAtA[iNdEx:])
@@ -2056,7 +2828,7 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryResponse) Unmarshal(dAtA []byte) error {
+func (m *StatsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2079,17 +2851,17 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: StatsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StatsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
+			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Routes", wireType)
 			}
-			var msglen int
+			m.Routes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRouter
@@ -2099,26 +2871,87 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Routes |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthRouter
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Events", wireType)
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthRouter
+			m.Events = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Events |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AdvertsSent", wireType)
 			}
-			m.Routes = append(m.Routes, &Route{})
-			if err := m.Routes[len(m.Routes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.AdvertsSent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AdvertsSent |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AdvertsReceived", wireType)
+			}
+			m.AdvertsReceived = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AdvertsReceived |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastSync", wireType)
+			}
+			m.LastSync = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastSync |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRouter(dAtA[iNdEx:])
@@ -2844,6 +3677,133 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 			}
 			m.Network = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRouter
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRouter
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthRouter
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthRouter
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRouter
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthRouter
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthRouter
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipRouter(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthRouter
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Metadata[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRouter(dAtA[iNdEx:])
@@ -3105,6 +4065,133 @@ func (m *Route) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRouter
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRouter
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthRouter
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthRouter
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRouter
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthRouter
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthRouter
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipRouter(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthRouter
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Metadata[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRouter(dAtA[iNdEx:])
@@ -3219,6 +4306,8 @@ type RouterClient interface {
 	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Router_WatchClient, error)
 	Advertise(ctx context.Context, in *Request, opts ...grpc.CallOption) (Router_AdvertiseClient, error)
 	Process(ctx context.Context, in *Advert, opts ...grpc.CallOption) (*ProcessResponse, error)
+	Table(ctx context.Context, in *TableRequest, opts ...grpc.CallOption) (Router_TableClient, error)
+	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 }
 
 type routerClient struct {
@@ -3311,12 +4400,55 @@ func (c *routerClient) Process(ctx context.Context, in *Advert, opts ...grpc.Cal
 	return out, nil
 }
 
+func (c *routerClient) Table(ctx context.Context, in *TableRequest, opts ...grpc.CallOption) (Router_TableClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Router_serviceDesc.Streams[2], "/router.Router/Table", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerTableClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Router_TableClient interface {
+	Recv() (*TableRoute, error)
+	grpc.ClientStream
+}
+
+type routerTableClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerTableClient) Recv() (*TableRoute, error) {
+	m := new(TableRoute)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *routerClient) Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/router.Router/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RouterServer is the server API for Router service.
 type RouterServer interface {
 	Lookup(context.Context, *LookupRequest) (*LookupResponse, error)
 	Watch(*WatchRequest, Router_WatchServer) error
 	Advertise(*Request, Router_AdvertiseServer) error
 	Process(context.Context, *Advert) (*ProcessResponse, error)
+	Table(*TableRequest, Router_TableServer) error
+	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
 }
 
 // UnimplementedRouterServer can be embedded to have forward compatible implementations.
@@ -3335,6 +4467,12 @@ func (*UnimplementedRouterServer) Advertise(req *Request, srv Router_AdvertiseSe
 func (*UnimplementedRouterServer) Process(ctx context.Context, req *Advert) (*ProcessResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Process not implemented")
 }
+func (*UnimplementedRouterServer) Table(req *TableRequest, srv Router_TableServer) error {
+	return status.Errorf(codes.Unimplemented, "method Table not implemented")
+}
+func (*UnimplementedRouterServer) Stats(ctx context.Context, req *StatsRequest) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
 
 func RegisterRouterServer(s *grpc.Server, srv RouterServer) {
 	s.RegisterService(&_Router_serviceDesc, srv)
@@ -3418,6 +4556,45 @@ func _Router_Process_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_Table_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TableRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RouterServer).Table(m, &routerTableServer{stream})
+}
+
+type Router_TableServer interface {
+	Send(*TableRoute) error
+	grpc.ServerStream
+}
+
+type routerTableServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerTableServer) Send(m *TableRoute) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Router_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/router.Router/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).Stats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Router_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "router.Router",
 	HandlerType: (*RouterServer)(nil),
@@ -3430,6 +4607,10 @@ var _Router_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Process",
 			Handler:    _Router_Process_Handler,
 		},
+		{
+			MethodName: "Stats",
+			Handler:    _Router_Stats_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3442,6 +4623,11 @@ var _Router_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Router_Advertise_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Table",
+			Handler:       _Router_Table_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "github.com/lack-io/vine/proto/services/router/router.proto",
 }
@@ -36,6 +36,8 @@ type RouterService interface {
 	Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Router_WatchService, error)
 	Advertise(ctx context.Context, in *Request, opts ...client.CallOption) (Router_AdvertiseService, error)
 	Process(ctx context.Context, in *Advert, opts ...client.CallOption) (*ProcessResponse, error)
+	Table(ctx context.Context, in *TableRequest, opts ...client.CallOption) (Router_TableService, error)
+	Stats(ctx context.Context, in *StatsRequest, opts ...client.CallOption) (*StatsResponse, error)
 }
 
 type routerService struct {
@@ -168,6 +170,65 @@ func (c *routerService) Process(ctx context.Context, in *Advert, opts ...client.
 	return out, nil
 }
 
+func (c *routerService) Table(ctx context.Context, in *TableRequest, opts ...client.CallOption) (Router_TableService, error) {
+	req := c.c.NewRequest(c.name, "Router.Table", &TableRequest{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &routerServiceTable{stream}, nil
+}
+
+type Router_TableService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Recv() (*TableRoute, error)
+}
+
+type routerServiceTable struct {
+	stream client.Stream
+}
+
+func (x *routerServiceTable) Close() error {
+	return x.stream.Close()
+}
+
+func (x *routerServiceTable) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *routerServiceTable) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *routerServiceTable) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *routerServiceTable) Recv() (*TableRoute, error) {
+	m := new(TableRoute)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *routerService) Stats(ctx context.Context, in *StatsRequest, opts ...client.CallOption) (*StatsResponse, error) {
+	req := c.c.NewRequest(c.name, "Router.Stats", in)
+	out := new(StatsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Router service
 // Router service is used by the proxy to lookup routes
 type RouterHandler interface {
@@ -175,6 +236,8 @@ type RouterHandler interface {
 	Watch(context.Context, *WatchRequest, Router_WatchStream) error
 	Advertise(context.Context, *Request, Router_AdvertiseStream) error
 	Process(context.Context, *Advert, *ProcessResponse) error
+	Table(context.Context, *TableRequest, Router_TableStream) error
+	Stats(context.Context, *StatsRequest, *StatsResponse) error
 }
 
 func RegisterRouterHandler(s server.Server, hdlr RouterHandler, opts ...server.HandlerOption) error {
@@ -183,6 +246,8 @@ func RegisterRouterHandler(s server.Server, hdlr RouterHandler, opts ...server.H
 		Watch(ctx context.Context, stream server.Stream) error
 		Advertise(ctx context.Context, stream server.Stream) error
 		Process(ctx context.Context, in *Advert, out *ProcessResponse) error
+		Table(ctx context.Context, stream server.Stream) error
+		Stats(ctx context.Context, in *StatsRequest, out *StatsResponse) error
 	}
 	type Router struct {
 		routerImpl
@@ -283,6 +348,50 @@ func (h *routerHandler) Process(ctx context.Context, in *Advert, out *ProcessRes
 	return h.RouterHandler.Process(ctx, in, out)
 }
 
+func (h *routerHandler) Table(ctx context.Context, stream server.Stream) error {
+	m := new(TableRequest)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.RouterHandler.Table(ctx, m, &routerTableStream{stream})
+}
+
+type Router_TableStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*TableRoute) error
+}
+
+type routerTableStream struct {
+	stream server.Stream
+}
+
+func (x *routerTableStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *routerTableStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *routerTableStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *routerTableStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *routerTableStream) Send(m *TableRoute) error {
+	return x.stream.Send(m)
+}
+
+func (h *routerHandler) Stats(ctx context.Context, in *StatsRequest, out *StatsResponse) error {
+	return h.RouterHandler.Stats(ctx, in, out)
+}
+
 // API Endpoints for Table service
 func NewTableEndpoints() []*apipb.Endpoint {
 	return []*apipb.Endpoint{}
@@ -31,7 +31,6 @@ import (
 	"github.com/lack-io/vine/core/server"
 	"github.com/lack-io/vine/lib/cmd"
 	"github.com/lack-io/vine/lib/logger"
-	"github.com/lack-io/vine/lib/trace"
 	signalutil "github.com/lack-io/vine/util/signal"
 	"github.com/lack-io/vine/util/wrapper"
 )
@@ -51,12 +50,17 @@ func newService(opts ...Option) Service {
 
 	// wrap client to inject From-Service header on any calls
 	options.Client = wrapper.FromService(serviceName, options.Client)
-	options.Client = wrapper.TraceCall(serviceName, trace.DefaultTracer, options.Client)
+	// the default tracer is resolved on each call since the flags may replace it
+	options.Client = wrapper.TraceCall(serviceName, nil, options.Client)
 
 	// wrap the server to provided handler stats
-	_ = options.Server.Init(
-		server.WrapHandler(wrapper.TraceHandler(trace.DefaultTracer)),
-	)
+	_ = options.Server.Init(server.WrapHandler(wrapper.TraceHandler(nil)))
+
+	// log the calls and the requests when asked to
+	if options.LogCalls {
+		options.Client = wrapper.LogCall(options.Logger, options.Client)
+		_ = options.Server.Init(server.WrapHandler(wrapper.LogHandler(options.Logger)))
+	}
 
 	// set opts
 	sv.opts = options
@@ -146,7 +150,9 @@ func (s *service) Stop() error {
 		}
 	}
 
-	if err := s.opts.Server.Stop(); err != nil {
+	// stop the server first, then the broker, the store and the tracer.
+	// each step is bounded so a stuck handler can't hang the shutdown
+	if err := cmd.Teardown(s.shutdownSteps()...); err != nil {
 		return err
 	}
 
@@ -159,6 +165,20 @@ func (s *service) Stop() error {
 	return gerr
 }
 
+// shutdownSteps returns the steps stopping the components of the service,
+// the store is the one of the cmd
+func (s *service) shutdownSteps() []cmd.Step {
+	opts := cmd.Options{
+		Server: &s.opts.Server,
+		Broker: &s.opts.Broker,
+		Tracer: &s.opts.Trace,
+	}
+	if s.opts.Cmd != nil {
+		opts.Store = s.opts.Cmd.Options().Store
+	}
+	return cmd.ShutdownSteps(opts)
+}
+
 func (s *service) Run() error {
 	// start the profiler
 	logger.Infof("Starting [service] %s", s.Name())
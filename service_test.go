@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vine
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/lack-io/vine/core/broker"
+	bmemory "github.com/lack-io/vine/core/broker/memory"
+	"github.com/lack-io/vine/core/registry/memory"
+	"github.com/lack-io/vine/core/server"
+	sgrpc "github.com/lack-io/vine/core/server/grpc"
+	"github.com/lack-io/vine/lib/cmd"
+	"github.com/lack-io/vine/lib/store"
+	smemory "github.com/lack-io/vine/lib/store/memory"
+	"github.com/lack-io/vine/lib/trace"
+	tmemory "github.com/lack-io/vine/lib/trace/memory"
+)
+
+// stops records the order the components are stopped in, the server
+// disconnects its broker too so only the first stop is recorded
+type stops struct {
+	sync.Mutex
+	order []string
+}
+
+func (s *stops) add(name string) {
+	s.Lock()
+	defer s.Unlock()
+	for _, n := range s.order {
+		if n == name {
+			return
+		}
+	}
+	s.order = append(s.order, name)
+}
+
+type stopServer struct {
+	server.Server
+	stops *stops
+}
+
+func (s *stopServer) Stop() error {
+	s.stops.add("server")
+	return s.Server.Stop()
+}
+
+type stopBroker struct {
+	broker.Broker
+	stops *stops
+}
+
+func (b *stopBroker) Disconnect() error {
+	b.stops.add("broker")
+	return b.Broker.Disconnect()
+}
+
+type stopStore struct {
+	store.Store
+	stops *stops
+}
+
+func (s *stopStore) Close() error {
+	s.stops.add("store")
+	return s.Store.Close()
+}
+
+type stopTracer struct {
+	trace.Tracer
+	stops *stops
+}
+
+func (t *stopTracer) Close() error {
+	t.stops.add("tracer")
+	return nil
+}
+
+func TestServiceStopOrder(t *testing.T) {
+	st := &stops{}
+
+	reg := memory.NewRegistry()
+	b := &stopBroker{Broker: bmemory.NewBroker(), stops: st}
+	s := store.Store(&stopStore{Store: smemory.NewStore(), stops: st})
+
+	svc := newService(
+		Server(&stopServer{
+			Server: sgrpc.NewServer(server.Name("go.vine.stop"), server.Address("127.0.0.1:0"), server.Registry(reg)),
+			stops:  st,
+		}),
+		Broker(b),
+		Tracer(&stopTracer{Tracer: tmemory.NewTracer(), stops: st}),
+		Cmd(cmd.NewCmd(cmd.Store(&s))),
+	)
+
+	srv := svc.(*service)
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	st.Lock()
+	defer st.Unlock()
+	if want := []string{"server", "broker", "store", "tracer"}; !reflect.DeepEqual(st.order, want) {
+		t.Fatalf("Expected the components to stop in order %v, got %v", want, st.order)
+	}
+}
@@ -24,6 +24,7 @@ package backoff
 
 import (
 	"math"
+	"math/rand"
 	"time"
 )
 
@@ -35,3 +36,21 @@ func Do(attempts int) time.Duration {
 	}
 	return time.Duration(math.Pow(float64(attempts), math.E)) * time.Millisecond * 100
 }
+
+// Exponential doubles the base interval on each attempt, limited to max,
+// and keeps a random half of it so the retries of many clients spread out.
+func Exponential(attempts int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
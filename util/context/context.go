@@ -29,8 +29,17 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/lack-io/vine/util/context/metadata"
+	"github.com/lack-io/vine/util/tls"
 )
 
+type clientIdentityKey struct{}
+
+// ClientIdentity returns the identity of the verified client certificate of the request
+func ClientIdentity(ctx context.Context) (*tls.Identity, bool) {
+	id, ok := ctx.Value(clientIdentityKey{}).(*tls.Identity)
+	return id, ok
+}
+
 func FromRequest(c *fiber.Ctx) context.Context {
 	ctx := c.Context()
 	md, ok := metadata.FromContext(ctx)
@@ -55,6 +64,9 @@ func FromRequest(c *fiber.Ctx) context.Context {
 	}
 	// pass http method
 	md.Set("Method", c.Method())
+	if id := tls.ClientIdentity(c.Context().TLSConnectionState()); id != nil {
+		return metadata.NewContext(context.WithValue(ctx, clientIdentityKey{}, id), md)
+	}
 	return metadata.NewContext(ctx, md)
 }
 
@@ -36,6 +36,7 @@ import (
 	"github.com/lack-io/cli"
 
 	"github.com/lack-io/vine/util/context/metadata"
+	mtls "github.com/lack-io/vine/util/tls"
 )
 
 func RequestToContext(c *fiber.Ctx) context.Context {
@@ -47,40 +48,98 @@ func RequestToContext(c *fiber.Ctx) context.Context {
 	return metadata.NewContext(ctx, md)
 }
 
+// TLSFlags are the flags read by TLSConfig
+func TLSFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "enable-tls",
+			Usage:   "Enable TLS support. Expects cert and key file to be specified",
+			EnvVars: []string{"VINE_ENABLE_TLS"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-cert-file",
+			Usage:   "Path to the TLS Certificate file",
+			EnvVars: []string{"VINE_TLS_CERT_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-key-file",
+			Usage:   "Path to the TLS Key file",
+			EnvVars: []string{"VINE_TLS_KEY_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-client-ca-file",
+			Aliases: []string{"tls-client-ca"},
+			Usage:   "Path to the TLS CA file to verify clients against, enables mutual TLS",
+			EnvVars: []string{"VINE_TLS_CLIENT_CA_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-min-version",
+			Usage:   "Set the minimum TLS version {1.2, 1.3}",
+			EnvVars: []string{"VINE_TLS_MIN_VERSION"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-max-version",
+			Usage:   "Set the maximum TLS version {1.2, 1.3}",
+			EnvVars: []string{"VINE_TLS_MAX_VERSION"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "tls-cipher-suites",
+			Usage:   "Set the cipher suites allowed with TLS 1.2 e.g TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+			EnvVars: []string{"VINE_TLS_CIPHER_SUITES"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "tls-curves",
+			Usage:   "Set the curves allowed for the key exchange {X25519, P256, P384, P521}",
+			EnvVars: []string{"VINE_TLS_CURVES"},
+		},
+	}
+}
+
 func TLSConfig(ctx *cli.Context) (*tls.Config, error) {
 	cert := ctx.String("tls-cert-file")
 	key := ctx.String("tls-key-file")
 	ca := ctx.String("tls-client-ca-file")
 
-	if len(cert) > 0 && len(key) > 0 {
-		certs, err := tls.LoadX509KeyPair(cert, key)
+	if len(cert) == 0 || len(key) == 0 {
+		return nil, errors.New("TLS certificate and key files not specified")
+	}
+
+	certs, err := tls.LoadX509KeyPair(cert, key)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certs},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	if len(ca) > 0 {
+		caCert, err := ioutil.ReadFile(ca)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("read TLS client CA file: %v", err)
 		}
 
-		if len(ca) > 0 {
-			caCert, err := ioutil.ReadFile(ca)
-			if err != nil {
-				return nil, err
-			}
-
-			caCertPool := x509.NewCertPool()
-			caCertPool.AppendCertsFromPEM(caCert)
-
-			return &tls.Config{
-				Certificates: []tls.Certificate{certs},
-				ClientCAs:    caCertPool,
-				ClientAuth:   tls.RequireAndVerifyClientCert,
-				NextProtos:   []string{"h2", "http/1.1"},
-			}, nil
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in TLS client CA file %s", ca)
 		}
 
-		return &tls.Config{
-			Certificates: []tls.Certificate{certs}, NextProtos: []string{"h2", "http/1.1"},
-		}, nil
+		config.ClientCAs = caCertPool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	err = mtls.Configure(config, mtls.Options{
+		MinVersion:   ctx.String("tls-min-version"),
+		MaxVersion:   ctx.String("tls-max-version"),
+		CipherSuites: ctx.StringSlice("tls-cipher-suites"),
+		Curves:       ctx.StringSlice("tls-curves"),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, errors.New("TLS certificate and key files not specified")
+	return config, nil
 }
 
 // UnexpectedSubcommand checks for erroneous subcommands and prints help and returns error
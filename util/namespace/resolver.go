@@ -32,14 +32,59 @@ import (
 	log "github.com/lack-io/vine/lib/logger"
 )
 
-func NewResolver(svcType, namespace string) *Resolver {
-	return &Resolver{svcType, namespace}
+// Options of the namespace resolver
+type Options struct {
+	// Reserved are the subdomains resolving to the default namespace,
+	// e.g. api and www
+	Reserved []string
+	// Domains maps the custom domains to their namespace
+	Domains map[string]string
+	// Mapper maps the subdomain to its namespace instead of the reversal
+	Mapper func(subdomain string) string
+}
+
+type Option func(o *Options)
+
+// WithReservedSubdomains sets the subdomains resolving to the default
+// namespace, e.g. api.example.com and www.example.com
+func WithReservedSubdomains(subdomains []string) Option {
+	return func(o *Options) {
+		o.Reserved = subdomains
+	}
+}
+
+// WithDomainMapping maps the custom domains to their namespace, e.g.
+// customer1.example.com to customer1-prod, instead of deriving it from the
+// subdomain
+func WithDomainMapping(domains map[string]string) Option {
+	return func(o *Options) {
+		o.Domains = domains
+	}
+}
+
+// WithSubdomainMapper maps the subdomain of the host to its namespace, e.g.
+// staging.foo of staging.foo.example.com, instead of skipping the reserved
+// subdomains and reversing the rest. An empty namespace resolves to the
+// default namespace.
+func WithSubdomainMapper(fn func(subdomain string) string) Option {
+	return func(o *Options) {
+		o.Mapper = fn
+	}
+}
+
+func NewResolver(svcType, namespace string, opts ...Option) *Resolver {
+	var options Options
+	for _, o := range opts {
+		o(&options)
+	}
+	return &Resolver{svcType, namespace, options}
 }
 
 // Resolver determines the namespace for a request
 type Resolver struct {
 	svcType   string
 	namespace string
+	opts      Options
 }
 
 func (r Resolver) String() string {
@@ -60,11 +105,23 @@ func (r Resolver) Resolve(c *fiber.Ctx) string {
 	// determine the host, e.g. dev.vine.mu:8080
 	host := c.Hostname()
 	if len(host) == 0 {
-		if h, _, err := net.SplitHostPort(string(c.Request().Host())); err == nil {
-			host = h // host does contain a port
-		} else if strings.Contains(err.Error(), "missing port in address") {
-			host = string(c.Request().Host()) // host does not contain a port
-		}
+		host = string(c.Request().Host())
+	}
+
+	return r.resolveHost(host)
+}
+
+// resolveHost returns the namespace of the host
+func (r Resolver) resolveHost(host string) string {
+	// strip the port and the trailing dot of the fully qualified names
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	// the custom domains are mapped as is
+	if ns, ok := r.opts.Domains[host]; ok {
+		return ns
 	}
 
 	// check for an ip address
@@ -93,8 +150,27 @@ func (r Resolver) Resolve(c *fiber.Ctx) string {
 	// remove the domain from the host, leaving the subdomain
 	subdomain := strings.TrimSuffix(host, "."+domain)
 
-	// return the reversed subdomain as the namespace
+	if r.opts.Mapper != nil {
+		if ns := r.opts.Mapper(subdomain); len(ns) > 0 {
+			return ns
+		}
+		return DefaultNamespace
+	}
+
+	// the reserved subdomains belong to the default domain, e.g.
+	// api.example.com, they are also skipped in api.staging.example.com
 	comps := strings.Split(subdomain, ".")
+	for _, s := range r.opts.Reserved {
+		if comps[0] == s {
+			comps = comps[1:]
+			break
+		}
+	}
+	if len(comps) == 0 {
+		return DefaultNamespace
+	}
+
+	// return the reversed subdomain as the namespace
 	for i := len(comps)/2 - 1; i >= 0; i-- {
 		opp := len(comps) - 1 - i
 		comps[i], comps[opp] = comps[opp], comps[i]
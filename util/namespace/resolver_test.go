@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package namespace
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestResolve(t *testing.T) {
+	r := NewResolver("api", "domain",
+		WithReservedSubdomains([]string{"api", "www", "static"}),
+		WithDomainMapping(map[string]string{"customer1.example.com": "customer1-prod"}),
+	)
+
+	testCases := []struct {
+		host      string
+		namespace string
+	}{
+		{"localhost:8080", DefaultNamespace},
+		{"10.0.0.1", DefaultNamespace},
+		{"example.com", DefaultNamespace},
+		{"dev.vine.mu", DefaultNamespace},
+		{"foo.example.com", "foo"},
+		{"foo.bar.example.com", "bar.foo"},
+		{"foo.example.com:8080", "foo"},
+		{"foo.example.com.", "foo"},
+		{"foo.example.com.:8080", "foo"},
+		{"api.example.com", DefaultNamespace},
+		{"www.example.com:443", DefaultNamespace},
+		{"api.staging.example.com", "staging"},
+		{"customer1.example.com", "customer1-prod"},
+		{"customer1.example.com.:8080", "customer1-prod"},
+		{"customer1.example.org", "customer1"},
+	}
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(r.Resolve(c))
+	})
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest("GET", "http://"+tc.host+"/", nil)
+		rsp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(rsp.Body)
+		rsp.Body.Close()
+		if string(b) != tc.namespace {
+			t.Errorf("Expected namespace %q for %s, got %q", tc.namespace, tc.host, b)
+		}
+	}
+}
+
+func TestResolveNamespace(t *testing.T) {
+	r := NewResolver("web", "go.vine", WithDomainMapping(map[string]string{"foo.example.com": "foo"}))
+	if ns := r.resolveHost("bar.example.com"); ns != "bar" {
+		t.Fatalf("Expected bar, got %s", ns)
+	}
+
+	// the namespace is only resolved from the domain when set to domain
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(r.ResolveWithType(c))
+	})
+	rsp, err := app.Test(httptest.NewRequest("GET", "http://foo.example.com/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(rsp.Body)
+	if string(b) != "go.vine.web" {
+		t.Fatalf("Expected go.vine.web, got %s", b)
+	}
+}
+
+func TestResolveSubdomainMapper(t *testing.T) {
+	tenants := map[string]string{"acme": "tenant-acme"}
+	r := NewResolver("web", "domain", WithReservedSubdomains([]string{"api"}), WithSubdomainMapper(func(subdomain string) string {
+		return tenants[subdomain]
+	}))
+
+	testData := []struct {
+		host      string
+		namespace string
+	}{
+		{"acme.example.com", "tenant-acme"},
+		// the mapper replaces the reserved subdomains and the reversal
+		{"api.example.com", DefaultNamespace},
+		{"staging.foo.example.com", DefaultNamespace},
+		{"example.com", DefaultNamespace},
+	}
+	for _, d := range testData {
+		if ns := r.resolveHost(d.host); ns != d.namespace {
+			t.Fatalf("Expected namespace %s for %s, got %s", d.namespace, d.host, ns)
+		}
+	}
+
+	// keep the dots of the subdomain
+	r = NewResolver("web", "domain", WithSubdomainMapper(func(subdomain string) string {
+		return subdomain
+	}))
+	if ns := r.resolveHost("staging.foo.example.com"); ns != "staging.foo" {
+		t.Fatalf("Expected staging.foo, got %s", ns)
+	}
+}
@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package net
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vine.sock")
+
+	l, err := ListenUnix(path, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Fatalf("Expected mode 0600, got %v", fi.Mode().Perm())
+	}
+
+	// the socket is in use
+	if _, err := ListenUnix(path, 0); err == nil {
+		t.Fatal("Expected error listening on a socket in use")
+	}
+
+	l.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("Expected socket file to be removed on close")
+	}
+}
+
+func TestListenUnixStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vine.sock")
+
+	// leave a stale socket file behind, as a crashed process would
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal("Expected stale socket file to exist")
+	}
+
+	// restart concurrently, the stale file must not prevent listening
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var listeners []net.Listener
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l, err := ListenUnix(path, 0)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			listeners = append(listeners, l)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(listeners) == 0 {
+		t.Fatal("Expected to listen on stale socket")
+	}
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+
+	c, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+}
+
+func TestUnixAddr(t *testing.T) {
+	addr := UnixAddr("/tmp/vine.sock")
+	if !IsUnix(addr) {
+		t.Fatalf("Expected %s to be a unix address", addr)
+	}
+	if p := UnixPath(addr); p != "/tmp/vine.sock" {
+		t.Fatalf("Expected /tmp/vine.sock, got %s", p)
+	}
+	if IsUnix("127.0.0.1:8080") {
+		t.Fatal("Expected tcp address not to be a unix address")
+	}
+}
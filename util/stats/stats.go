@@ -44,6 +44,8 @@ type stats struct {
 
 	Counters []*counter `json:"counters"`
 
+	Endpoints map[string]*endpoint `json:"endpoints"`
+
 	running bool
 	exit    chan bool
 }
@@ -56,6 +58,14 @@ type counter struct {
 	Total  int            `json:"total_reqs"`
 }
 
+// endpoint is the concurrency of an endpoint
+type endpoint struct {
+	// current in-flight requests
+	InFlight int `json:"in_flight"`
+	// peak in-flight requests
+	Peak int `json:"peak"`
+}
+
 var (
 	// 5 second window
 	window = time.Second * 5
@@ -123,11 +133,57 @@ func (s *stats) Record(c string, t int) {
 	s.Unlock()
 }
 
+// Begin records the start of a request to the endpoint
+func (s *stats) Begin(name string) {
+	s.Lock()
+	e, ok := s.Endpoints[name]
+	if !ok {
+		e = &endpoint{}
+		s.Endpoints[name] = e
+	}
+	e.InFlight++
+	if e.InFlight > e.Peak {
+		e.Peak = e.InFlight
+	}
+	s.Unlock()
+}
+
+// End records the end of a request to the endpoint
+func (s *stats) End(name string) {
+	s.Lock()
+	if e, ok := s.Endpoints[name]; ok && e.InFlight > 0 {
+		e.InFlight--
+	}
+	s.Unlock()
+}
+
+// InFlight returns the current and peak in-flight requests of the endpoint
+func (s *stats) InFlight(name string) (int, int) {
+	s.RLock()
+	defer s.RUnlock()
+	e, ok := s.Endpoints[name]
+	if !ok {
+		return 0, 0
+	}
+	return e.InFlight, e.Peak
+}
+
+// ServeHTTP records the requests of the handler under the http endpoint
 func (s *stats) ServeHTTP(h http.Handler) http.Handler {
+	return s.Handler("http", h)
+}
+
+// Handler records the requests of the handler under the named endpoint.
+// The endpoints are keyed by the handler and not by the request path,
+// which would grow the endpoints with every path the clients make up.
+func (s *stats) Handler(name string, h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var code string
 		rw := &writer{w, 200}
 
+		s.Begin(name)
+		defer s.End(name)
+
 		h.ServeHTTP(w, r)
 
 		switch {
@@ -202,5 +258,6 @@ func New() *stats {
 				Status:    make(map[string]int),
 			},
 		},
+		Endpoints: make(map[string]*endpoint),
 	}
 }
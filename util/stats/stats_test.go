@@ -23,6 +23,8 @@
 package stats
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -63,3 +65,48 @@ func TestStats(t *testing.T) {
 		}
 	}
 }
+
+func TestInFlight(t *testing.T) {
+	s := New()
+
+	s.Begin("Foo.Bar")
+	s.Begin("Foo.Bar")
+	s.End("Foo.Bar")
+	s.Begin("Foo.Bar")
+
+	if cur, peak := s.InFlight("Foo.Bar"); cur != 2 || peak != 2 {
+		t.Fatalf("Expected 2 in flight with a peak of 2, got %d and %d", cur, peak)
+	}
+
+	s.End("Foo.Bar")
+	s.End("Foo.Bar")
+	s.End("Foo.Bar")
+
+	if cur, peak := s.InFlight("Foo.Bar"); cur != 0 || peak != 2 {
+		t.Fatalf("Expected none in flight with a peak of 2, got %d and %d", cur, peak)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	s := New()
+
+	h := s.Handler("Foo", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/panic" {
+			panic("handler failed")
+		}
+	}))
+
+	for _, path := range []string{"/a", "/b", "/panic"} {
+		func() {
+			defer func() { recover() }()
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		}()
+	}
+
+	if len(s.Endpoints) != 1 {
+		t.Fatalf("Expected the requests recorded under one endpoint, got %v", s.Endpoints)
+	}
+	if cur, peak := s.InFlight("Foo"); cur != 0 || peak != 1 {
+		t.Fatalf("Expected none in flight with a peak of 1, got %d and %d", cur, peak)
+	}
+}
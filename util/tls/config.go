@@ -0,0 +1,180 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tls
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	versions = map[string]uint16{
+		"1.0": tls.VersionTLS10,
+		"1.1": tls.VersionTLS11,
+		"1.2": tls.VersionTLS12,
+		"1.3": tls.VersionTLS13,
+	}
+
+	curves = map[string]tls.CurveID{
+		"X25519": tls.X25519,
+		"P256":   tls.CurveP256,
+		"P384":   tls.CurveP384,
+		"P521":   tls.CurveP521,
+	}
+)
+
+// Options constrain the protocol of a tls.Config
+type Options struct {
+	// MinVersion e.g 1.2
+	MinVersion string
+	// MaxVersion e.g 1.3
+	MaxVersion string
+	// CipherSuites allowed with TLS 1.2 e.g TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256
+	CipherSuites []string
+	// Curves allowed for the key exchange e.g X25519
+	Curves []string
+}
+
+func keys(m map[string]uint16) string {
+	var names []string
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// ParseVersion returns the TLS version e.g 1.2
+func ParseVersion(s string) (uint16, error) {
+	v, ok := versions[s]
+	if !ok {
+		return 0, fmt.Errorf("unknown TLS version '%s', expected one of %s", s, keys(versions))
+	}
+	return v, nil
+}
+
+// ParseCipherSuites returns the ids of the cipher suites, only the secure ones are accepted
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	suites := make(map[string]uint16)
+	for _, s := range tls.CipherSuites() {
+		suites[s.Name] = s.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := suites[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite '%s', expected one of %s", name, keys(suites))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// ParseCurves returns the ids of the curves e.g X25519
+func ParseCurves(names []string) ([]tls.CurveID, error) {
+	ids := make([]tls.CurveID, 0, len(names))
+	for _, name := range names {
+		id, ok := curves[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown curve '%s', expected one of P256, P384, P521, X25519", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Configure applies the options to the config. Invalid options e.g a min
+// version higher than the max version are rejected.
+func Configure(config *tls.Config, o Options) error {
+	if len(o.MinVersion) > 0 {
+		v, err := ParseVersion(o.MinVersion)
+		if err != nil {
+			return err
+		}
+		config.MinVersion = v
+	}
+
+	if len(o.MaxVersion) > 0 {
+		v, err := ParseVersion(o.MaxVersion)
+		if err != nil {
+			return err
+		}
+		config.MaxVersion = v
+	}
+
+	if config.MinVersion != 0 && config.MaxVersion != 0 && config.MinVersion > config.MaxVersion {
+		return fmt.Errorf("TLS min version %s is higher than max version %s", o.MinVersion, o.MaxVersion)
+	}
+
+	if len(o.CipherSuites) > 0 {
+		if config.MinVersion == tls.VersionTLS13 {
+			return fmt.Errorf("cipher suites only apply to TLS 1.2 and lower, they can't be set with min version 1.3")
+		}
+		ids, err := ParseCipherSuites(o.CipherSuites)
+		if err != nil {
+			return err
+		}
+		config.CipherSuites = ids
+	}
+
+	if len(o.Curves) > 0 {
+		ids, err := ParseCurves(o.Curves)
+		if err != nil {
+			return err
+		}
+		config.CurvePreferences = ids
+	}
+
+	return nil
+}
+
+// Identity is the identity of a verified client certificate
+type Identity struct {
+	CommonName     string
+	DNSNames       []string
+	EmailAddresses []string
+	URIs           []string
+}
+
+// ClientIdentity returns the identity of the verified client certificate of
+// the connection, nil when the client is not verified
+func ClientIdentity(state *tls.ConnectionState) *Identity {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	cert := state.VerifiedChains[0][0]
+	id := &Identity{
+		CommonName:     cert.Subject.CommonName,
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+	}
+	for _, u := range cert.URIs {
+		id.URIs = append(id.URIs, u.String())
+	}
+
+	return id
+}
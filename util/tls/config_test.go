@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+)
+
+func TestConfigure(t *testing.T) {
+	config := &tls.Config{}
+	err := Configure(config, Options{
+		MinVersion:   "1.2",
+		CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+		Curves:       []string{"X25519", "P256"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.MinVersion != tls.VersionTLS12 {
+		t.Fatalf("Expected min version 1.2, got %x", config.MinVersion)
+	}
+	if len(config.CipherSuites) != 1 || config.CipherSuites[0] != tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 {
+		t.Fatalf("Unexpected cipher suites %v", config.CipherSuites)
+	}
+	if len(config.CurvePreferences) != 2 || config.CurvePreferences[0] != tls.X25519 {
+		t.Fatalf("Unexpected curves %v", config.CurvePreferences)
+	}
+
+	testData := []Options{
+		{MinVersion: "1.3", MaxVersion: "1.2"},
+		{MinVersion: "1.4"},
+		{MinVersion: "1.3", CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}},
+		{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+		{Curves: []string{"P224"}},
+	}
+	for _, o := range testData {
+		if err := Configure(&tls.Config{}, o); err == nil {
+			t.Fatalf("Expected options %+v to be rejected", o)
+		}
+	}
+}
+
+func TestClientIdentity(t *testing.T) {
+	if id := ClientIdentity(&tls.ConnectionState{}); id != nil {
+		t.Fatalf("Expected no identity for an unverified client, got %+v", id)
+	}
+
+	cert, err := Certificate("client.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := ClientIdentity(&tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{leaf}}})
+	if id == nil || len(id.DNSNames) != 1 || id.DNSNames[0] != "client.example.com" {
+		t.Fatalf("Expected the identity of the certificate, got %+v", id)
+	}
+}
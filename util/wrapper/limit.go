@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package wrapper
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/core/server"
+	"github.com/lack-io/vine/proto/apis/errors"
+	"github.com/lack-io/vine/util/context/metadata"
+)
+
+// limiter caps the requests in flight, admitting them by priority
+type limiter struct {
+	sync.Mutex
+
+	max      int
+	inFlight int
+	// waiting are the high priority requests waiting for a slot
+	waiting []chan struct{}
+}
+
+// acquire takes a slot for the request. The high priority requests wait for
+// a slot when all are taken, the normal ones are shed and the low ones are
+// shed early, keeping a quarter of the slots for the others.
+func (l *limiter) acquire(ctx context.Context, p client.Priority) bool {
+	l.Lock()
+
+	limit := l.max
+	if p == client.PriorityLow {
+		limit -= l.max / 4
+	}
+	if l.inFlight < limit && len(l.waiting) == 0 {
+		l.inFlight++
+		l.Unlock()
+		return true
+	}
+
+	if p != client.PriorityHigh {
+		l.Unlock()
+		return false
+	}
+
+	ch := make(chan struct{})
+	l.waiting = append(l.waiting, ch)
+	l.Unlock()
+
+	select {
+	case <-ch:
+		return true
+	case <-ctx.Done():
+	}
+
+	l.Lock()
+	for i, w := range l.waiting {
+		if w == ch {
+			l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+			l.Unlock()
+			return false
+		}
+	}
+	l.Unlock()
+
+	// the slot was handed over meanwhile, pass it on
+	<-ch
+	l.release()
+	return false
+}
+
+// release frees the slot or hands it over to the first waiting request
+func (l *limiter) release() {
+	l.Lock()
+	defer l.Unlock()
+
+	if len(l.waiting) > 0 {
+		close(l.waiting[0])
+		l.waiting = l.waiting[1:]
+		return
+	}
+	l.inFlight--
+}
+
+// HandlerLimit wraps a server handler to limit the requests in flight to
+// max. Under load the requests are admitted by the priority set with
+// client.WithPriority: the low priority ones are shed first, the high
+// priority ones wait for a slot until their deadline.
+func HandlerLimit(max int) server.HandlerWrapper {
+	l := &limiter{max: max}
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			p, _ := metadata.Get(ctx, client.PriorityHeader)
+			if !l.acquire(ctx, client.ParsePriority(p)) {
+				return errors.ServiceUnavailable(req.Service(), "too many requests in flight, %s priority request shed", client.ParsePriority(p))
+			}
+			defer l.release()
+
+			return h(ctx, req, rsp)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package wrapper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bmemory "github.com/lack-io/vine/core/broker/memory"
+	"github.com/lack-io/vine/core/client"
+	cgrpc "github.com/lack-io/vine/core/client/grpc"
+	"github.com/lack-io/vine/core/client/selector"
+	"github.com/lack-io/vine/core/registry/memory"
+	"github.com/lack-io/vine/core/server"
+	sgrpc "github.com/lack-io/vine/core/server/grpc"
+	"github.com/lack-io/vine/proto/apis/errors"
+)
+
+func TestLimiter(t *testing.T) {
+	l := &limiter{max: 4}
+	ctx := context.Background()
+
+	// the low priority requests get three quarters of the slots
+	for i := 0; i < 3; i++ {
+		if !l.acquire(ctx, client.PriorityLow) {
+			t.Fatalf("Expected low priority request %d to be admitted", i)
+		}
+	}
+	if l.acquire(ctx, client.PriorityLow) {
+		t.Fatal("Expected the low priority request to be shed")
+	}
+
+	if !l.acquire(ctx, client.PriorityNormal) {
+		t.Fatal("Expected the normal priority request to be admitted")
+	}
+	if l.acquire(ctx, client.PriorityNormal) {
+		t.Fatal("Expected the normal priority request to be shed")
+	}
+
+	// the high priority requests wait for a slot until their deadline
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if l.acquire(tctx, client.PriorityHigh) {
+		t.Fatal("Expected the high priority request to time out")
+	}
+
+	admitted := make(chan bool)
+	go func() {
+		admitted <- l.acquire(ctx, client.PriorityHigh)
+	}()
+
+	// wait for the request to queue, the freed slot is handed over
+	for {
+		l.Lock()
+		n := len(l.waiting)
+		l.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	l.release()
+	if !<-admitted {
+		t.Fatal("Expected the high priority request to be admitted")
+	}
+	if l.inFlight != 4 {
+		t.Fatalf("Expected 4 requests in flight, got %d", l.inFlight)
+	}
+
+	for i := 0; i < 4; i++ {
+		l.release()
+	}
+	if l.inFlight != 0 {
+		t.Fatalf("Expected no request in flight, got %d", l.inFlight)
+	}
+}
+
+type Gate struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (g *Gate) Pass(ctx context.Context, req *Message, rsp *Message) error {
+	g.started <- struct{}{}
+	<-g.release
+	return nil
+}
+
+func TestHandlerLimit(t *testing.T) {
+	reg := memory.NewRegistry()
+
+	srv := sgrpc.NewServer(
+		server.Name("go.vine.gate"),
+		server.Address("127.0.0.1:0"),
+		server.Registry(reg),
+		server.Broker(bmemory.NewBroker()),
+		server.WrapHandler(HandlerLimit(1)),
+	)
+	gate := &Gate{started: make(chan struct{}, 10), release: make(chan struct{})}
+	if err := srv.Handle(srv.NewHandler(gate)); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	cli := cgrpc.NewClient(
+		client.Registry(reg),
+		client.Selector(selector.NewSelector(selector.Registry(reg))),
+	)
+
+	call := func(p client.Priority) error {
+		req := cli.NewRequest("go.vine.gate", "Gate.Pass", &Message{}, client.WithContentType("application/json"))
+		return cli.Call(context.TODO(), req, &Message{}, client.WithPriority(p), client.WithRetries(0), client.WithRequestTimeout(5*time.Second))
+	}
+
+	// take the only slot
+	errs := make(chan error, 2)
+	go func() { errs <- call(client.PriorityNormal) }()
+	<-gate.started
+
+	for _, p := range []client.Priority{client.PriorityLow, client.PriorityNormal} {
+		err := call(p)
+		if verr := errors.FromErr(err); verr == nil || verr.Code != 503 {
+			t.Fatalf("Expected the %s priority request to be shed, got %v", p, err)
+		}
+	}
+
+	// the high priority request waits for the slot
+	go func() { errs <- call(client.PriorityHigh) }()
+	time.Sleep(50 * time.Millisecond)
+	close(gate.release)
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package wrapper
+
+import (
+	"context"
+	"time"
+
+	"github.com/lack-io/vine/core/client"
+	"github.com/lack-io/vine/core/server"
+	"github.com/lack-io/vine/lib/logger"
+	"github.com/lack-io/vine/util/context/metadata"
+)
+
+var (
+	// LogTagHeader is the metadata key of the correlation tag of a call
+	LogTagHeader = HeaderPrefix + "Log-Tag"
+)
+
+// logTag returns the tag of the call, set by the call options or received
+// by the service making the call
+func logTag(ctx context.Context, opts []client.CallOption) string {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if len(options.LogTag) > 0 {
+		return options.LogTag
+	}
+	tag, _ := metadata.Get(ctx, LogTagHeader)
+	return tag
+}
+
+// logCall logs a call or a request, the tagged calls are logged at info level to be
+// found without enabling the debug logs
+func logCall(l logger.Logger, msg, tag string, fields map[string]interface{}, err error, d time.Duration) {
+	level := logger.DebugLevel
+	if len(tag) > 0 {
+		fields["tag"] = tag
+		level = logger.InfoLevel
+	}
+	fields["duration"] = d
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	l.Fields(fields).Log(level, msg)
+}
+
+// loggerOf returns the logger of the wrappers, nil is the default logger at the
+// time of the call since the flags may replace it
+func loggerOf(l logger.Logger) logger.Logger {
+	if l == nil {
+		return logger.DefaultLogger
+	}
+	return l
+}
+
+type logWrapper struct {
+	client.Client
+	log logger.Logger
+}
+
+func (l *logWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	tag := logTag(ctx, opts)
+	if len(tag) > 0 {
+		ctx = metadata.Set(ctx, LogTagHeader, tag)
+	}
+
+	start := time.Now()
+	err := l.Client.Call(ctx, req, rsp, opts...)
+	logCall(loggerOf(l.log), "call finished", tag, map[string]interface{}{
+		"service":  req.Service(),
+		"endpoint": req.Endpoint(),
+	}, err, time.Since(start))
+
+	return err
+}
+
+func (l *logWrapper) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	tag := logTag(ctx, opts)
+	if len(tag) > 0 {
+		ctx = metadata.Set(ctx, LogTagHeader, tag)
+	}
+
+	start := time.Now()
+	stream, err := l.Client.Stream(ctx, req, opts...)
+	logCall(loggerOf(l.log), "stream opened", tag, map[string]interface{}{
+		"service":  req.Service(),
+		"endpoint": req.Endpoint(),
+		"stream":   true,
+	}, err, time.Since(start))
+
+	return stream, err
+}
+
+// LogCall wraps a client to log the calls, with their tag set by client.WithLogTag,
+// a nil logger uses the default logger
+func LogCall(l logger.Logger, c client.Client) client.Client {
+	return &logWrapper{
+		Client: c,
+		log:    l,
+	}
+}
+
+// LogHandler wraps a server handler to log the requests with their tag. The
+// logger of the request context, see logger.FromContext, includes the tag.
+// A nil logger uses the default logger.
+func LogHandler(ll logger.Logger) server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			l := loggerOf(ll)
+			tag, _ := metadata.Get(ctx, LogTagHeader)
+			if len(tag) > 0 {
+				ctx = logger.NewContext(ctx, l.Fields(map[string]interface{}{"tag": tag}))
+			}
+
+			start := time.Now()
+			err := h(ctx, req, rsp)
+			logCall(l, "request served", tag, map[string]interface{}{
+				"service":  req.Service(),
+				"endpoint": req.Endpoint(),
+			}, err, time.Since(start))
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package wrapper
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	bmemory "github.com/lack-io/vine/core/broker/memory"
+	"github.com/lack-io/vine/core/client"
+	cgrpc "github.com/lack-io/vine/core/client/grpc"
+	"github.com/lack-io/vine/core/client/selector"
+	"github.com/lack-io/vine/core/registry/memory"
+	"github.com/lack-io/vine/core/server"
+	sgrpc "github.com/lack-io/vine/core/server/grpc"
+	"github.com/lack-io/vine/lib/logger"
+)
+
+// captureLogger records the fields of the log entries
+type captureLogger struct {
+	mu      *sync.Mutex
+	fields  map[string]interface{}
+	entries *[]map[string]interface{}
+}
+
+func newCaptureLogger() *captureLogger {
+	return &captureLogger{mu: &sync.Mutex{}, entries: &[]map[string]interface{}{}}
+}
+
+func (l *captureLogger) Init(...logger.Option) error { return nil }
+func (l *captureLogger) Options() logger.Options     { return logger.Options{} }
+func (l *captureLogger) String() string              { return "capture" }
+
+func (l *captureLogger) Fields(fields map[string]interface{}) logger.Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &captureLogger{mu: l.mu, fields: merged, entries: l.entries}
+}
+
+func (l *captureLogger) Log(level logger.Level, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.entries = append(*l.entries, l.fields)
+}
+
+func (l *captureLogger) Logf(level logger.Level, format string, v ...interface{}) {
+	l.Log(level, v...)
+}
+
+func (l *captureLogger) tagged(tag string) []map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var entries []map[string]interface{}
+	for _, e := range *l.entries {
+		if e["tag"] == tag {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+type Message struct {
+	Name string `json:"name"`
+}
+
+type Greeter struct{}
+
+func (g *Greeter) Hello(ctx context.Context, req *Message, rsp *Message) error {
+	// the logger of the request context is tagged
+	l, ok := logger.FromContext(ctx)
+	if ok {
+		l.Log(logger.InfoLevel, "in handler")
+	}
+	rsp.Name = "Hello " + req.Name
+	return nil
+}
+
+func TestLogTag(t *testing.T) {
+	reg := memory.NewRegistry()
+	log := newCaptureLogger()
+
+	srv := sgrpc.NewServer(
+		server.Name("go.vine.greeter"),
+		server.Address("127.0.0.1:0"),
+		server.Registry(reg),
+		server.Broker(bmemory.NewBroker()),
+		server.WrapHandler(LogHandler(log)),
+	)
+	if err := srv.Handle(srv.NewHandler(&Greeter{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	cli := LogCall(log, cgrpc.NewClient(
+		client.Registry(reg),
+		client.Selector(selector.NewSelector(selector.Registry(reg))),
+	))
+
+	call := func(opts ...client.CallOption) {
+		req := cli.NewRequest("go.vine.greeter", "Greeter.Hello", &Message{Name: "John"}, client.WithContentType("application/json"))
+		rsp := &Message{}
+		if err := cli.Call(context.TODO(), req, rsp, opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	call()
+	call(client.WithLogTag("debug-42"))
+
+	// the client, the server wrapper and the handler log the tagged call
+	entries := log.tagged("debug-42")
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 tagged log entries, got %d: %v", len(entries), entries)
+	}
+	for _, e := range entries {
+		if ep, ok := e["endpoint"]; ok && ep != "Greeter.Hello" {
+			t.Fatalf("Unexpected endpoint %v", ep)
+		}
+	}
+
+	// the untagged call is logged without tag
+	if n := len(log.tagged("")); n != 0 {
+		t.Fatalf("Expected no empty tag, got %d", n)
+	}
+	log.mu.Lock()
+	total := len(*log.entries)
+	log.mu.Unlock()
+	if total != 5 {
+		t.Fatalf("Expected 5 log entries, got %d", total)
+	}
+}
+
+type testRequest struct {
+	server.Request
+}
+
+func (testRequest) Service() string  { return "go.vine.greeter" }
+func (testRequest) Endpoint() string { return "Greeter.Hello" }
+
+func TestLogHandlerDefaultLogger(t *testing.T) {
+	defer func(l logger.Logger) {
+		logger.DefaultLogger = l
+	}(logger.DefaultLogger)
+
+	// the default logger is resolved on each request, not on wrapping
+	h := LogHandler(nil)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return nil
+	})
+
+	log := newCaptureLogger()
+	logger.DefaultLogger = log
+
+	if err := h(context.TODO(), testRequest{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(*log.entries); n != 1 {
+		t.Fatalf("Expected the request logged by the default logger, got %d entries", n)
+	}
+}
@@ -80,21 +80,36 @@ type traceWrapper struct {
 	trace trace.Tracer
 }
 
+// tracer returns the tracer of the wrapper or the default tracer when nil,
+// the flags may replace the default tracer after the wrapper is created
+func tracer(t trace.Tracer) trace.Tracer {
+	if t == nil {
+		return trace.DefaultTracer
+	}
+	return t
+}
+
 func (c *traceWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
-	newCtx, s := c.trace.Start(ctx, req.Service()+"."+req.Endpoint())
+	t := tracer(c.trace)
+	newCtx, s := t.Start(ctx, req.Service()+"."+req.Endpoint())
 
 	s.Type = trace.SpanTypeRequestOutbound
+	s.Metadata["service"] = req.Service()
+	if len(c.name) > 0 {
+		s.Metadata["caller"] = c.name
+	}
+
 	err := c.Client.Call(newCtx, req, rsp, opts...)
 	if err != nil {
 		s.Metadata["error"] = err.Error()
 	}
 
 	// finish the trace
-	c.trace.Finish(s)
+	t.Finish(s)
 	return err
 }
 
-// TraceCall is a call tracing wrapper
+// TraceCall is a call tracing wrapper, a nil tracer uses the default tracer
 func TraceCall(name string, t trace.Tracer, c client.Client) client.Client {
 	return &traceWrapper{
 		name:   name,
@@ -103,8 +118,9 @@ func TraceCall(name string, t trace.Tracer, c client.Client) client.Client {
 	}
 }
 
-// TraceHandler wraps a server handler to perform tracing
-func TraceHandler(t trace.Tracer) server.HandlerWrapper {
+// TraceHandler wraps a server handler to perform tracing, a nil tracer uses
+// the default tracer
+func TraceHandler(tr trace.Tracer) server.HandlerWrapper {
 	// return a handler wrapper
 	return func(h server.HandlerFunc) server.HandlerFunc {
 		// return a function that returns a function
@@ -115,8 +131,10 @@ func TraceHandler(t trace.Tracer) server.HandlerWrapper {
 			}
 
 			// get the span
+			t := tracer(tr)
 			newCtx, s := t.Start(ctx, req.Service()+"."+req.Endpoint())
 			s.Type = trace.SpanTypeRequestInbound
+			s.Metadata["service"] = req.Service()
 
 			err := h(newCtx, req, rsp)
 			if err != nil {
@@ -131,6 +149,25 @@ func TraceHandler(t trace.Tracer) server.HandlerWrapper {
 	}
 }
 
+// inFlight records the requests in flight of the endpoints
+type inFlight interface {
+	Begin(endpoint string)
+	End(endpoint string)
+}
+
+// HandlerStats wraps a server handler to record the current and peak
+// in-flight requests of each endpoint, e.g. with the stats of util/stats
+func HandlerStats(s inFlight) server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			name := req.Service() + "." + req.Endpoint()
+			s.Begin(name)
+			defer s.End(name)
+			return h(ctx, req, rsp)
+		}
+	}
+}
+
 type staticClient struct {
 	address string
 	client.Client
@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2020 Lack
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package wrapper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bmemory "github.com/lack-io/vine/core/broker/memory"
+	"github.com/lack-io/vine/core/client"
+	cgrpc "github.com/lack-io/vine/core/client/grpc"
+	"github.com/lack-io/vine/core/client/selector"
+	"github.com/lack-io/vine/core/registry/memory"
+	"github.com/lack-io/vine/core/server"
+	sgrpc "github.com/lack-io/vine/core/server/grpc"
+	"github.com/lack-io/vine/util/stats"
+)
+
+type Slow struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *Slow) Wait(ctx context.Context, req *Message, rsp *Message) error {
+	s.started <- struct{}{}
+	<-s.release
+	rsp.Name = req.Name
+	return nil
+}
+
+func TestHandlerStats(t *testing.T) {
+	reg := memory.NewRegistry()
+	st := stats.New()
+
+	srv := sgrpc.NewServer(
+		server.Name("go.vine.slow"),
+		server.Address("127.0.0.1:0"),
+		server.Registry(reg),
+		server.Broker(bmemory.NewBroker()),
+		server.WrapHandler(HandlerStats(st)),
+	)
+	slow := &Slow{started: make(chan struct{}), release: make(chan struct{})}
+	if err := srv.Handle(srv.NewHandler(slow)); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	cli := cgrpc.NewClient(
+		client.Registry(reg),
+		client.Selector(selector.NewSelector(selector.Registry(reg))),
+	)
+
+	const calls = 3
+	errs := make(chan error, calls)
+	for i := 0; i < calls; i++ {
+		go func() {
+			req := cli.NewRequest("go.vine.slow", "Slow.Wait", &Message{Name: "John"}, client.WithContentType("application/json"))
+			errs <- cli.Call(context.TODO(), req, &Message{}, client.WithRequestTimeout(5*time.Second))
+		}()
+	}
+	for i := 0; i < calls; i++ {
+		<-slow.started
+	}
+
+	if cur, peak := st.InFlight("go.vine.slow.Slow.Wait"); cur != calls || peak != calls {
+		t.Fatalf("Expected %d requests in flight, got %d (peak %d)", calls, cur, peak)
+	}
+
+	close(slow.release)
+	for i := 0; i < calls; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if cur, peak := st.InFlight("go.vine.slow.Slow.Wait"); cur != 0 || peak != calls {
+		t.Fatalf("Expected no request in flight and a peak of %d, got %d (peak %d)", calls, cur, peak)
+	}
+}